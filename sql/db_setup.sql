@@ -80,22 +80,96 @@ CREATE TABLE IF NOT EXISTS admin_requests (
 
 CREATE INDEX IF NOT EXISTS admin_requests_msg_id_idx ON admin_requests(msg_id);
 
+-- Unique indexes of states and info are created by migrations, as they include user_id column
 CREATE TABLE IF NOT EXISTS states(
     chat_id INTEGER,
     state TEXT
 );
 
-CREATE UNIQUE INDEX IF NOT EXISTS states_chat_id_idx ON states(chat_id);
-
 CREATE TABLE IF NOT EXISTS info(
     chat_id INTEGER,
     json TEXT
 );
 
-CREATE UNIQUE INDEX IF NOT EXISTS info_chat_id_idx ON info(chat_id);
-
 CREATE TABLE IF NOT EXISTS tasks (
     task_timestamp INTEGER NOT NULL,
     task_name TEXT NOT NULL,
     CHECK (LENGTH(task_name) < 50)
 );
+
+CREATE TABLE IF NOT EXISTS crashes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    crash_time INTEGER NOT NULL,
+    update_id INTEGER,
+    chat_id INTEGER,
+    user_id INTEGER,
+    state TEXT,
+    callback_data TEXT,
+    message_text TEXT,
+    error TEXT NOT NULL,
+    stack TEXT
+);
+
+CREATE INDEX IF NOT EXISTS crashes_crash_time_idx ON crashes(crash_time);
+
+CREATE TABLE IF NOT EXISTS processed_updates (
+    update_id INTEGER NOT NULL,
+    callback_id TEXT,
+    processed_time INTEGER NOT NULL,
+    -- Updates are marked before handling and finished after it, so that updates of crashed handlers can be handled again
+    finished BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS processed_updates_update_id_idx ON processed_updates(update_id);
+CREATE UNIQUE INDEX IF NOT EXISTS processed_updates_callback_id_idx ON processed_updates(callback_id);
+
+CREATE TABLE IF NOT EXISTS processed_callbacks (
+    callback_key TEXT PRIMARY KEY,
+    processed_time INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS flow_markups (
+    chat_id INTEGER NOT NULL,
+    msg_id INTEGER NOT NULL,
+    PRIMARY KEY (chat_id, msg_id)
+);
+
+CREATE TABLE IF NOT EXISTS callback_payloads (
+    token TEXT PRIMARY KEY,
+    payload TEXT NOT NULL,
+    expires_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS callback_payloads_expires_at_idx ON callback_payloads(expires_at);
+
+CREATE TABLE IF NOT EXISTS queue_subscriptions (
+    chat_id INTEGER NOT NULL,
+    msg_id INTEGER NOT NULL,
+    lesson_id INTEGER NOT NULL,
+    pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    text TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (chat_id, msg_id)
+);
+
+CREATE INDEX IF NOT EXISTS queue_subscriptions_lesson_id_idx ON queue_subscriptions(lesson_id);
+
+-- History of outcomes of labworks. user_id and marked_by are telegram ids. Outcome of the request during the lesson is the one
+-- shown in its queue and is corrected in place, while the request and the lesson themselves can be deleted later
+CREATE TABLE IF NOT EXISTS labwork_outcomes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    request_id INTEGER NOT NULL,
+    lesson_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    group_id INTEGER NOT NULL,
+    subject TEXT NOT NULL,
+    labwork_number INTEGER NOT NULL,
+    outcome INTEGER NOT NULL,
+    grade INTEGER NOT NULL DEFAULT 0,
+    comment TEXT NOT NULL DEFAULT '',
+    marked_by INTEGER NOT NULL DEFAULT 0,
+    marked_at TEXT NOT NULL,
+    UNIQUE(request_id, lesson_id)
+);
+
+CREATE INDEX IF NOT EXISTS labwork_outcomes_user_id_idx ON labwork_outcomes(user_id);
+CREATE INDEX IF NOT EXISTS labwork_outcomes_group_id_idx ON labwork_outcomes(group_id);
@@ -0,0 +1,33 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+type PayloadsCleaner interface {
+	DeleteExpired(ctx context.Context) error
+}
+
+var _ Task = (*ClearPayloadsTask)(nil)
+
+type ClearPayloadsTask struct {
+	payloads PayloadsCleaner
+}
+
+func NewClearPayloadsTask(payloads PayloadsCleaner) *ClearPayloadsTask {
+	return &ClearPayloadsTask{payloads: payloads}
+}
+
+const CLEAR_PAYLOADS_TIMEOUT = 30 * time.Second
+
+func (task *ClearPayloadsTask) Run(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, CLEAR_PAYLOADS_TIMEOUT)
+	defer cancel()
+	err := task.payloads.DeleteExpired(ctx)
+	if err != nil {
+		slog.Error(fmt.Errorf("failed to clear expired callback payloads: %w", err).Error())
+	}
+}
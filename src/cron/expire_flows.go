@@ -0,0 +1,33 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+type FlowsExpirer interface {
+	ExpireStale(ctx context.Context) error
+}
+
+var _ Task = (*ExpireFlowsTask)(nil)
+
+type ExpireFlowsTask struct {
+	flows FlowsExpirer
+}
+
+func NewExpireFlowsTask(flows FlowsExpirer) *ExpireFlowsTask {
+	return &ExpireFlowsTask{flows: flows}
+}
+
+const EXPIRE_FLOWS_TIMEOUT = 30 * time.Second
+
+func (task *ExpireFlowsTask) Run(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, EXPIRE_FLOWS_TIMEOUT)
+	defer cancel()
+	err := task.flows.ExpireStale(ctx)
+	if err != nil {
+		slog.Error(fmt.Errorf("failed to expire stale flows: %w", err).Error())
+	}
+}
@@ -29,10 +29,12 @@ type TasksController struct {
 	bot            *tgutils.Bot
 	jobs           []gocron.Job
 	tasksRepo      TasksRepository
+	flows          FlowsExpirer
+	payloads       PayloadsCleaner
 }
 
 func NewTasksController(sheets SheetsApi, lessons LessonRepo, lessonsRequest LessonsRequestRepo, 
-	users UsersRepo, drive DriveApi, tasks TasksRepository, bot *tgutils.Bot) *TasksController {
+	users UsersRepo, drive DriveApi, tasks TasksRepository, bot *tgutils.Bot, flows FlowsExpirer, payloads PayloadsCleaner) *TasksController {
 	tasksController := &TasksController{
 		sheets:         sheets,
 		lessons:        lessons,
@@ -41,6 +43,8 @@ func NewTasksController(sheets SheetsApi, lessons LessonRepo, lessonsRequest Les
 		drive:          drive,
 		bot:            bot,
 		tasksRepo:      tasks,
+		flows:          flows,
+		payloads:       payloads,
 	}
 	return tasksController
 }
@@ -106,6 +110,23 @@ func (controller *TasksController) InitTasks(ctx context.Context) {
 	}
 	controller.jobs = append(controller.jobs, clearLessonsJob)
 
+	// Isn't added to the jobs, as it is run every minute and there is no need to catch up on it after restart
+	expireFlows := NewExpireFlowsTask(controller.flows)
+	_, err = scheduler.NewJob(gocron.DurationJob(time.Minute),
+		gocron.NewTask(func() { expireFlows.Run(ctx) }), gocron.WithName("expire flows"), gocron.WithContext(ctx),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule))
+	if err != nil {
+		slog.Error(fmt.Errorf("failed to init expire flows cron: %w", err).Error())
+	}
+
+	// Expired payloads are already unusable, so skipping a run only delays freeing the space
+	clearPayloads := NewClearPayloadsTask(controller.payloads)
+	_, err = scheduler.NewJob(daily,
+		gocron.NewTask(func() { clearPayloads.Run(ctx) }), gocron.WithName("clear callback payloads"), gocron.WithContext(ctx))
+	if err != nil {
+		slog.Error(fmt.Errorf("failed to init clear callback payloads cron: %w", err).Error())
+	}
+
 	scheduler.Start()
 	controller.TasksExec(ctx)
 	<-ctx.Done()
@@ -4,11 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/labworks"
 	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
@@ -94,12 +93,13 @@ func (task *ReminderTask) Run(ctx context.Context) {
 }
 
 func (task *ReminderTask) sendMessageForRequested(ctx context.Context, request *entities.LessonRequest, lesson *persistence.Lesson) error {
-	msg := tgbotapi.NewMessage(request.ChatId, fmt.Sprintf("Вы сдавали данную лабораторную? (%s %s, номер лабораторной %d)", 
+	ctx = task.bot.Localize(ctx, request.UserId, "")
+	msg := tgbotapi.NewMessage(request.ChatId, i18n.T(ctx, i18n.REMINDER_TEXT,
 	lesson.Subject, lesson.DateTime.Format("02.01.2006"), request.LabworkNumber))
 	msg.ReplyToMessageID = int(request.MsgId)
-	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup([]tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardButtonData("Да", 
-	createSheetsRefreshCallbackData(request.Id, true)),
-		tgbotapi.NewInlineKeyboardButtonData("Нет", createSheetsRefreshCallbackData(request.Id, false))})
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup([]tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.YES), 
+	ReminderCallbackCodec.MustEncode(ReminderCallback{Accepted: true, RequestId: request.Id})),
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.NO), ReminderCallbackCodec.MustEncode(ReminderCallback{Accepted: false, RequestId: request.Id}))})
 	_, err := task.bot.SendCtx(ctx, msg)
 	if err != nil {
 		return err
@@ -109,22 +109,9 @@ func (task *ReminderTask) sendMessageForRequested(ctx context.Context, request *
 
 const REMINDER_CALLBACKS = "remind"
 
-func createSheetsRefreshCallbackData(requestId int64, accepted bool) string {
-	formattedAccepted := 0
-	if accepted {
-		formattedAccepted = 1
-	}
-	return REMINDER_CALLBACKS + "|" + fmt.Sprint(formattedAccepted) + "|" + fmt.Sprint(requestId)
+type ReminderCallback struct {
+	Accepted  bool
+	RequestId int64
 }
 
-func parseSheetsRefreshCallbackData(callbackData string) (requestId int64, accepted bool) {
-	callbackData, _ = strings.CutPrefix(callbackData, REMINDER_CALLBACKS+"|")
-	formattedAccepted, formattedRequestId, _ := strings.Cut(callbackData, "|")
-	accepted = false
-	acceptedInt, _ := strconv.Atoi(formattedAccepted)
-	if acceptedInt == 1 {
-		accepted = true
-	}
-	requestId, _ = strconv.ParseInt(formattedRequestId, 10, 64)
-	return requestId, accepted
-}
+var ReminderCallbackCodec = tgutils.NewCallbackCodec[ReminderCallback](REMINDER_CALLBACKS, 1)
@@ -2,47 +2,129 @@ package cron
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
-	"strings"
 
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/labworks"
 	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
+// QueueRefresher edits messages, which are subscribed to the queue of the lesson
+type QueueRefresher interface {
+	Refresh(lessonId int64)
+}
+
+// TurnNotifierReminder tells the students, waiting in the queue of the lesson, their changed places
+type TurnNotifierReminder interface {
+	Notify(ctx context.Context, lessonId int64)
+}
+
+// OutcomesRecorderReminder keeps the history of outcomes of labworks
+type OutcomesRecorderReminder interface {
+	RecordIfAbsent(ctx context.Context, outcome *entities.LabworkOutcome) error
+}
+
 type ReminderCallbackHandler struct {
 	lessons         LessonsRepoReminder
 	lessonsRequests LessonsRequestsRepositoryReminder
 	sheets          SheetsApiReminder
 	users           UsersRepoReminder
+	queues          QueueRefresher
+	outcomes        OutcomesRecorderReminder
+	turns           TurnNotifierReminder
 }
 
 func NewSheetsRefreshCallbackHandler(lessonsRequests LessonsRequestsRepositoryReminder, sheets SheetsApiReminder,
-	users UsersRepoReminder, lessons LessonsRepoReminder) *ReminderCallbackHandler {
-	return &ReminderCallbackHandler{lessonsRequests: lessonsRequests, sheets: sheets, users: users, lessons: lessons}
+	users UsersRepoReminder, lessons LessonsRepoReminder, queues QueueRefresher, outcomes OutcomesRecorderReminder,
+	turns TurnNotifierReminder) *ReminderCallbackHandler {
+	return &ReminderCallbackHandler{lessonsRequests: lessonsRequests, sheets: sheets, users: users, lessons: lessons, queues: queues,
+		outcomes: outcomes, turns: turns}
 }
 
-func (handler *ReminderCallbackHandler) HandleCallback(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) error {
-	if strings.HasPrefix(update.CallbackData(), REMINDER_CALLBACKS) {
-		requestId, accepted := parseSheetsRefreshCallbackData(update.CallbackData())
-		if accepted {
-			err := handler.lessonsRequests.Delete(ctx, requestId)
+// ReminderOwnerMiddleware lets only the student, who submitted the request, answer the reminder about it. It must go before
+// the reminder is marked as processed, so that presses of other members of the chat don't use up the answer
+func ReminderOwnerMiddleware(requests LessonsRequestsRepositoryReminder) tgutils.CallbackMiddleware {
+	return func(next tgutils.CallbackHandler) tgutils.CallbackHandler {
+		return tgutils.CallbackHandlerFunc(func(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) error {
+			data, err := ReminderCallbackCodec.Decode(update.CallbackQuery.Data)
+			if err != nil {
+				return fmt.Errorf("failed to decode reminder callback: %w", err)
+			}
+			req, err := requests.Get(ctx, data.RequestId)
+			if errors.Is(err, sql.ErrNoRows) {
+				return next.HandleCallback(ctx, update, bot)
+			}
 			if err != nil {
-				return fmt.Errorf("failed to delete lesson request in sheets refresh: %w", err)
+				return fmt.Errorf("failed to get lesson request of the reminder: %w", err)
 			}
-		} else {
-			err := handler.SetNextLesson(ctx, requestId)
+			if req.UserId == update.SentFrom().ID {
+				return next.HandleCallback(ctx, update, bot)
+			}
+			_, err = bot.RequestCtx(ctx, tgbotapi.NewCallback(update.CallbackQuery.ID, i18n.T(ctx, i18n.FOREIGN_CALLBACK_TEXT)))
 			if err != nil {
-				return err
+				return fmt.Errorf("failed to answer foreign reminder callback: %w", err)
 			}
+			return nil
+		})
+	}
+}
+
+func (handler *ReminderCallbackHandler) HandleCallback(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot,
+	data *ReminderCallback) error {
+	// Request leaves the queue of its lesson either way
+	lesson, err := handler.lessons.GetLessonByRequest(ctx, data.RequestId)
+	if err != nil {
+		return fmt.Errorf("failed to get lesson by request id in sheets refresh: %w", err)
+	}
+	if data.Accepted {
+		err := handler.recordPassed(ctx, update.SentFrom().ID, lesson, data.RequestId)
+		if err != nil {
+			return err
 		}
-		_, err := bot.SendCtx(ctx, tgbotapi.NewEditMessageReplyMarkup(update.FromChat().ID, update.CallbackQuery.Message.MessageID,
-			tgbotapi.NewInlineKeyboardMarkup([]tgbotapi.InlineKeyboardButton{})))
+		err = handler.lessonsRequests.Delete(ctx, data.RequestId)
 		if err != nil {
-			return fmt.Errorf("failed to delete reply markup on a reminder message: %w", err)
+			return fmt.Errorf("failed to delete lesson request in sheets refresh: %w", err)
 		}
 	} else {
-		return fmt.Errorf("wrong callback data (%s) passed to sheets refresh callback handler", update.CallbackData())
+		err := handler.SetNextLesson(ctx, data.RequestId)
+		if err != nil {
+			return err
+		}
+	}
+	handler.queues.Refresh(lesson.Id)
+	handler.turns.Notify(ctx, lesson.Id)
+	_, err = bot.SendCtx(ctx, tgbotapi.NewEditMessageReplyMarkup(update.FromChat().ID, update.CallbackQuery.Message.MessageID,
+		tgbotapi.NewInlineKeyboardMarkup([]tgbotapi.InlineKeyboardButton{})))
+	if err != nil {
+		return fmt.Errorf("failed to delete reply markup on a reminder message: %w", err)
+	}
+	return nil
+}
+
+// recordPassed adds the labwork, which the student confirmed to have passed, to the history of outcomes,
+// unless the admin has already marked it during the lesson
+func (handler *ReminderCallbackHandler) recordPassed(ctx context.Context, tgId int64, lesson *persistence.Lesson, requestId int64) error {
+	req, err := handler.lessonsRequests.Get(ctx, requestId)
+	if err != nil {
+		return fmt.Errorf("failed to get lesson request to record its outcome: %w", err)
+	}
+	err = handler.outcomes.RecordIfAbsent(ctx, &entities.LabworkOutcome{
+		RequestId:     req.Id,
+		LessonId:      lesson.Id,
+		UserId:        req.UserId,
+		GroupId:       lesson.GroupId,
+		Subject:       lesson.Subject,
+		LabworkNumber: req.LabworkNumber,
+		Outcome:       entities.Passed,
+		MarkedBy:      tgId,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record outcome of labwork in sheets refresh: %w", err)
 	}
 	return nil
 }
@@ -61,6 +143,8 @@ func (handler *ReminderCallbackHandler) SetNextLesson(ctx context.Context, reque
 	if err != nil {
 		return fmt.Errorf("failed to get lessons by request id in sheets refresh cron: %w", err)
 	}
+	handler.queues.Refresh(lesson.Id)
+	handler.turns.Notify(ctx, lesson.Id)
 
 	req, err := handler.lessonsRequests.Get(ctx, requestId)
 	if err != nil {
@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+// LabworkOutcome is the record of the history of outcomes of labworks. UserId and MarkedBy are telegram ids,
+// MarkedBy is 0, if it's unknown. Grade is 0, if the labwork wasn't graded
+type LabworkOutcome struct {
+	MarkedAt      time.Time
+	Subject       string
+	Comment       string
+	FullName      string
+	MarkedByName  string
+	Id            int64
+	RequestId     int64
+	LessonId      int64
+	UserId        int64
+	GroupId       int64
+	MarkedBy      int64
+	LabworkNumber int8
+	Outcome       Outcome
+	Grade         int8
+}
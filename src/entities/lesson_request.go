@@ -16,3 +16,31 @@ func NewLessonRequest(LessonId, UserId, MsgId, ChatId int64, LabworkNumber int8)
 	return &LessonRequest{LessonId: LessonId, UserId: UserId, MsgId: MsgId, ChatId: ChatId, LabworkNumber: LabworkNumber, 
 		SubmitTime: time.Now()}
 }
+
+// UserLessonRequest is the request of the user together with the lesson it was submitted to
+type UserLessonRequest struct {
+	LessonRequest
+	Subject        string
+	LessonDateTime time.Time
+	SubgroupNumber int8
+	// Position in the queue of the lesson, 0 if request is pending
+	Position  int
+	IsPending bool
+}
+
+// Outcome of the labwork, recorded by admin during the lesson
+type Outcome int8
+
+const (
+	NoOutcome Outcome = iota
+	Passed
+	Failed
+	Absent
+)
+
+// QueuedRequest is the accepted request in the queue of the lesson together with the name of its student
+type QueuedRequest struct {
+	LessonRequest
+	FullName string
+	Outcome  Outcome
+}
@@ -0,0 +1,11 @@
+package entities
+
+// QueueSubscription is a message with the queue of the lesson, which is edited, whenever the queue changes.
+// Text is the last sent one, so that unchanged queues aren't edited
+type QueueSubscription struct {
+	Text     string
+	ChatId   int64
+	LessonId int64
+	MsgId    int
+	Pinned   bool
+}
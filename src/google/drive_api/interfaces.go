@@ -18,4 +18,5 @@ func (res *SpreadsheetResult) SpreadsheetId() string {
 type DriveApi interface {
 	SetSpreadsheetPermissions(ctx context.Context, spreadsheetId string) error
 	DoesSheetExist(ctx context.Context, name string) (SpreadsheetResult, error)
+	GetSpreadsheets(ctx context.Context) ([]string, error)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/labworks"
 )
@@ -14,4 +15,9 @@ type SheetsApi interface {
 	CreateSheet(ctx context.Context, groupName string, lessons []persistence.Lesson) (SheetUrl, error)
 	ClearSpreadsheet(ctx context.Context, spreadsheetId string, before time.Time) error
 	AddLabworkRequest(context.Context, *labworks.AppendedLabwork) error
+	DeleteLabworkRequest(context.Context, *labworks.AppendedLabwork) error
+	SetLabworkOutcome(ctx context.Context, req *labworks.AppendedLabwork, outcome entities.Outcome) error
+	WriteOutcomes(ctx context.Context, groupName string, outcomes []entities.LabworkOutcome) error
+	ReorderLessons(ctx context.Context, orderTypes []entities.OrderType, groupName, subject string) error
+	ReorderLesson(ctx context.Context, orderTypes []entities.OrderType, groupName string, lesson persistence.Lesson) error
 }
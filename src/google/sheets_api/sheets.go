@@ -7,6 +7,7 @@ import (
 	"math"
 	"math/rand"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -224,6 +225,10 @@ func (serv *SheetsApiService) ClearSpreadsheet(ctx context.Context, spreadsheetI
 	}
 	deleteSheetsRequest := sheets.BatchUpdateSpreadsheetRequest{}
 	for _, sheet := range spreadsheet.Sheets {
+		// Sheet of outcomes has no date in its title, so it would be parsed as the oldest one
+		if sheet.Properties.Title == OUTCOMES_SHEET_TITLE {
+			continue
+		}
 		_, date, _ := parseLessonName(sheet.Properties.Title)
 		if date.Before(before) {
 			deleteSheetsRequest.Requests = append(deleteSheetsRequest.Requests, &sheets.Request{
@@ -370,6 +375,161 @@ func (serv *SheetsApiService) appendToSheet(ctx context.Context, spreadsheetId s
 	return err
 }
 
+// DeleteLabworkRequest removes the row of the withdrawn request from the sheet of the lesson
+func (serv *SheetsApiService) DeleteLabworkRequest(ctx context.Context, req *labworks.AppendedLabwork) error {
+	spreadsheetId, sheet, row, err := serv.findLabworkRow(ctx, req)
+	if err != nil || row == -1 {
+		return err
+	}
+	err = serv.WithRetries(ctx, func(ctx context.Context) error {
+		_, err := serv.api.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{Requests: []*sheets.Request{
+			{
+				DeleteDimension: &sheets.DeleteDimensionRequest{
+					Range: &sheets.DimensionRange{
+						SheetId:    sheet.Properties.SheetId,
+						Dimension:  "ROWS",
+						StartIndex: int64(row),
+						EndIndex:   int64(row + 1),
+					},
+				},
+			},
+		}}).Context(ctx).Do()
+		return err
+	})()
+	if err != nil {
+		return fmt.Errorf("failed to delete row of labwork request: %w", err)
+	}
+	return nil
+}
+
+var outcomeNames = map[entities.Outcome]string{
+	entities.NoOutcome: "",
+	entities.Passed:    "Сдано",
+	entities.Failed:    "Не сдано",
+	entities.Absent:    "Отсутствовал",
+}
+
+// SetLabworkOutcome writes outcome of the labwork next to the row of the request, outside of the table
+func (serv *SheetsApiService) SetLabworkOutcome(ctx context.Context, req *labworks.AppendedLabwork, outcome entities.Outcome) error {
+	spreadsheetId, sheet, row, err := serv.findLabworkRow(ctx, req)
+	if err != nil {
+		return err
+	}
+	if row == -1 {
+		return fmt.Errorf("no row of %s found for labwork %d", req.FullName, req.LabworkNumber)
+	}
+	title := sheet.Properties.Title
+	err = serv.WithRetries(ctx, func(ctx context.Context) error {
+		_, err := serv.api.Spreadsheets.Values.BatchUpdate(spreadsheetId, &sheets.BatchUpdateValuesRequest{
+			ValueInputOption: "RAW",
+			Data: []*sheets.ValueRange{
+				{Range: fmt.Sprintf("'%s'!D1", title), Values: [][]any{{"Результат"}}},
+				{Range: fmt.Sprintf("'%s'!D%d", title, row+1), Values: [][]any{{outcomeNames[outcome]}}},
+			},
+		}).Context(ctx).Do()
+		return err
+	})()
+	if err != nil {
+		return fmt.Errorf("failed to write outcome of labwork request: %w", err)
+	}
+	return nil
+}
+
+const OUTCOMES_SHEET_TITLE = "Результаты"
+
+var outcomesHeader = []any{"ФИО", "Предмет", "Лабораторная", "Результат", "Оценка", "Комментарий", "Отметил", "Дата"}
+
+// WriteOutcomes rewrites the sheet of outcomes of the group with the given history, the sheet is created, if there is none yet
+func (serv *SheetsApiService) WriteOutcomes(ctx context.Context, groupName string, outcomes []entities.LabworkOutcome) error {
+	group, err := serv.groupsRepo.GetByName(ctx, groupName)
+	if err != nil {
+		return err
+	}
+	spreadsheetId := group.SpreadsheetId
+	spreadsheet, err := serv.api.Spreadsheets.Get(spreadsheetId).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to get spreadsheet during writing outcomes: %w", err)
+	}
+	exists := slices.ContainsFunc(spreadsheet.Sheets, func(sheet *sheets.Sheet) bool {
+		return sheet.Properties.Title == OUTCOMES_SHEET_TITLE
+	})
+	if !exists {
+		err = serv.WithRetries(ctx, func(ctx context.Context) error {
+			_, err := serv.api.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
+				Requests: []*sheets.Request{
+					{AddSheet: &sheets.AddSheetRequest{Properties: &sheets.SheetProperties{Title: OUTCOMES_SHEET_TITLE}}},
+				},
+			}).Context(ctx).Do()
+			return err
+		})()
+		if err != nil {
+			return fmt.Errorf("failed to create sheet of outcomes: %w", err)
+		}
+	}
+
+	values := [][]any{outcomesHeader}
+	for _, outcome := range outcomes {
+		grade := ""
+		if outcome.Grade != 0 {
+			grade = fmt.Sprint(outcome.Grade)
+		}
+		values = append(values, []any{outcome.FullName, outcome.Subject, outcome.LabworkNumber, outcomeNames[outcome.Outcome], grade,
+			outcome.Comment, outcome.MarkedByName, serv.formatDateTimeToEuropean(outcome.MarkedAt)})
+	}
+	err = serv.WithRetries(ctx, func(ctx context.Context) error {
+		_, err := serv.api.Spreadsheets.Values.Clear(spreadsheetId, fmt.Sprintf("'%s'", OUTCOMES_SHEET_TITLE),
+			&sheets.ClearValuesRequest{}).Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+		_, err = serv.api.Spreadsheets.Values.Update(spreadsheetId, fmt.Sprintf("'%s'!A1", OUTCOMES_SHEET_TITLE),
+			&sheets.ValueRange{Values: values}).ValueInputOption("RAW").Context(ctx).Do()
+		return err
+	})()
+	if err != nil {
+		return fmt.Errorf("failed to write outcomes to sheet: %w", err)
+	}
+	return nil
+}
+
+// findLabworkRow returns index of the row of the request in the sheet of its lesson, or -1, if there is no such row.
+// Rows are matched by name of the student and number of the labwork, since values of the time column are formatted by the table
+func (serv *SheetsApiService) findLabworkRow(ctx context.Context, req *labworks.AppendedLabwork) (string, *sheets.Sheet, int, error) {
+	group, err := serv.groupsRepo.GetByName(ctx, req.GroupName)
+	if err != nil {
+		return "", nil, -1, err
+	}
+	spreadsheetId := group.SpreadsheetId
+	spreadsheet, err := serv.api.Spreadsheets.Get(spreadsheetId).Context(ctx).Do()
+	if err != nil {
+		return "", nil, -1, fmt.Errorf("failed to get spreadsheet during search of labwork request: %w", err)
+	}
+	for _, sheet := range spreadsheet.Sheets {
+		titleSubject, titleDate, subgroupNum := parseLessonName(sheet.Properties.Title)
+		if titleSubject != req.DisciplineName || !serv.areDatesEqual(time.Time(req.RequestedDate), titleDate) || 
+		req.SubgroupNumber != subgroupNum {
+			continue
+		}
+		var values *sheets.ValueRange
+		err = serv.WithRetries(ctx, func(ctx context.Context) error {
+			values, err = serv.api.Spreadsheets.Values.Get(spreadsheetId, fmt.Sprintf("'%s'!A2:C", sheet.Properties.Title)).Context(ctx).Do()
+			return err
+		})()
+		if err != nil {
+			return "", nil, -1, fmt.Errorf("failed to read sheet during search of labwork request: %w", err)
+		}
+		for i, row := range values.Values {
+			if len(row) >= 2 && fmt.Sprint(row[0]) == req.FullName && fmt.Sprint(row[1]) == fmt.Sprint(req.LabworkNumber) {
+				// Values are read from the second row, after the header
+				return spreadsheetId, sheet, i + 1, nil
+			}
+		}
+		return spreadsheetId, sheet, -1, nil
+	}
+	return "", nil, -1, fmt.Errorf("no such labwork found for name: %s, date: %s, subgroup: %d", 
+	req.DisciplineName, time.Time(req.RequestedDate).Format(time.Layout), req.SubgroupNumber)
+}
+
 func (serv *SheetsApiService) formatDateTimeToEuropean(dateTime time.Time) string {
 	date := fmt.Sprint(dateTime.Day()) + "/" + fmt.Sprint(int(dateTime.Month())) + "/" + fmt.Sprint(dateTime.Year())
 	time := fmt.Sprintf("%02d:%02d:%d", dateTime.Hour(), dateTime.Minute(), dateTime.Second())
@@ -0,0 +1,225 @@
+package i18n
+
+var be = Catalog{
+	Name:   "Беларуская",
+	Plural: slavicPlural,
+	Messages: map[Key]string{
+		BACK_BUTTON:              "Назад",
+		ACCEPT_BUTTON:            "Прыняць",
+		DECLINE_BUTTON:           "Адхіліць",
+		YES:                      "Так",
+		NO:                       "Не",
+		NO_GROUP_TEXT:            "Вы пакуль не належыце ні да адной групы",
+		NO_LABWORKS_LEFT_TEXT:    "Лабараторных больш не засталося. Адпачніце",
+		ENTER_FULL_NAME_TEXT:     "Увядзіце ваша прозвішча і імя (Прыклад фармату: Іваноў Іван)",
+		MESSAGE_TOO_LONG_TEXT:    "Ваша паведамленне перавышае ліміты памеру паведамленняў у тэлеграме. Калі ласка, змяніце яго і адпраўце зноў",
+		REQUEST_APPROVED_TEXT:    "Ваша заяўка была ўхвалена",
+		REQUEST_DECLINED_TEXT:    "Ваша заяўка была адхілена",
+		GOOGLE_ERROR_TEXT:        "Памылка на баку сэрвісаў гугла. Калі ласка, паспрабуйце пазней",
+		GOOGLE_ERROR_ACCEPT_TEXT: "Памылка на баку сэрвісаў гугла. Паспрабуйце ўхваліць заяўку пазней",
+		CANCELLED_TEXT:           "Бягучае дзеянне адменена",
+		CRASH_TEXT:               "Адбылася нечаканая памылка, бягучае дзеянне было адменена. Паспрабуйце пачаць нанова",
+		NOT_ADMIN_TEXT:           "Вы не з'яўляецеся адмінам для выканання гэтай каманды",
+
+		FLOW_EXPIRED_TEXT:      "Час чакання адказу скончыўся, дзеянне было адменена",
+		BUTTONS_EXPIRED_TEXT:   "Час дзеяння кнопак скончыўся",
+		PAYLOAD_EXPIRED_TEXT:   "Кнопка састарэла, пачніце дзеянне нанова",
+		ALREADY_PROCESSED_TEXT: "Ужо апрацавана",
+		PRIVATE_ONLY_TEXT:      "Каманда %s даступная толькі ў асабістых паведамленнях з ботам",
+		PRIVATE_CHAT_BUTTON:    "Перайсці ў асабістыя паведамленні",
+		FOREIGN_CALLBACK_TEXT:  "Гэтая кнопка прызначана іншаму карыстальніку",
+		JOIN_GROUP_BUTTON:      "Уступіць у групу",
+		ASSIGN_BUTTON:          "Стаць адміністратарам групы",
+		SUBMIT_BUTTON:          "Адправіць заяўку на лабараторную",
+		ADD_LABWORK_BUTTON:     "Дадаць уласную лабараторную",
+
+		HELP_DESCRIPTION:           "Каманды і інфармацыя",
+		SUBMIT_DESCRIPTION:         "Запіс на здачу лабараторнай",
+		ASSIGN_DESCRIPTION:         "Адпраўка заяўкі на ролю адміністратара групы",
+		JOIN_GROUP_DESCRIPTION:     "Адпраўка заяўкі на ўдзел у групе",
+		QUEUE_DESCRIPTION:          "Атрыманне чаргі сваёй групы",
+		REVERT_DESCRIPTION:         "Вяртанне да папярэдняга кроку",
+		CANCEL_DESCRIPTION:         "Адмена бягучага дзеяння",
+		TABLE_DESCRIPTION:          "Атрыманне спасылкі на гугл-табліцу сваёй групы",
+		LANGUAGE_DESCRIPTION:       "Выбар мовы бота",
+		ADD_LABWORK_DESCRIPTION:    "Даданне ўласнай пары",
+		DELETE_DESCRIPTION:         "Выдаленне ўдзельніка з групы",
+		REORDER_DESCRIPTION:        "Змена парадку чаргі на прадмет",
+		REMOVE_REQUEST_DESCRIPTION: "Выдаленне заяўкі з чаргі",
+		CRASHES_DESCRIPTION:        "Апошнія справаздачы пра збоі",
+		MY_REQUESTS_DESCRIPTION:    "Мае заяўкі",
+		LESSON_MODE_DESCRIPTION:    "Рэжым заняткаў: выклік студэнтаў з чаргі",
+		SETTINGS_DESCRIPTION:       "Налады",
+		RESULTS_DESCRIPTION:        "Мае вынікі па лабараторных",
+
+		START_TEXT: "Скарыстайцеся /help для атрымання спіса каманд. Для адпраўкі заявак на лабараторныя " +
+			"вы павінны або стаць адмінам групы, са ўхвалы ўладальніка бота, або ўдзельнікам групы, калі ў яе ўжо ёсць адмін.",
+		NO_CRASHES_TEXT:  "Збояў не зафіксавана",
+		CRASH_CHAT_LINE:  "Чат: %d, карыстальнік: %d, update: %d\n",
+		CRASH_STATE_LINE: "Стан: %s\n",
+		CRASH_TEXT_LINE:  "Тэкст: %s\n",
+		CRASH_ERROR_LINE: "Памылка: %s\n",
+
+		CHOOSE_LANGUAGE_TEXT:      "Абярыце мову",
+		LANGUAGE_CHANGED_TEXT:     "Мова зменена на беларускую",
+		LANGUAGE_UNAVAILABLE_TEXT: "Мову можна будзе абраць пасля ўступлення ў групу, пакуль выкарыстоўваецца мова вашага кліента тэлеграма",
+
+		CHOOSE_DISCIPLINE_AND_DATE_TEXT: "Абярыце прадмет і дату пары",
+		FINISH_REQUEST_FIRST_TEXT:       "Калі ласка, скончыце адпраўку заяўкі, перш чым пераходзіць да астатніх каманд",
+		ENTER_LABWORK_NUMBER_TEXT:       "Увядзіце нумар лабараторнай работы, якую здаяце",
+		INVALID_LABWORK_NUMBER_TEXT:     "Калі ласка, увядзіце карэктны нумар лабараторнай (адна лічба, у разумных межах)",
+		ENTER_LABWORK_PROOF_TEXT:        "Дашліце доказ гатоўнасці лабараторнай работы (адзін прымацаваны файл, магчыма з тэкставым подпісам)",
+		LABWORK_REQUEST_SENT_TEXT:       "Ваша заяўка была адпраўлена адміністратарам",
+		LABWORK_REQUEST_ACCEPTED_TEXT:   "Ваша заяўка была прынята",
+		LABWORK_REQUEST_WITHDRAWN_TEXT:  "Студэнт адклікаў гэтую заяўку",
+		LABWORK_REQUEST_TEMPLATE: "Адправіў: {{.FullName}}\nПрадмет: {{.DisciplineName}}\nНумар лабараторнай: {{.LabworkNumber}}\n" +
+			"Дата: {{date .RequestedDate}}\nЧас адпраўкі: {{dateTime .SentProofTime}}\n{{if .Notes}}Дадатковая інфармацыя: {{.Notes}} {{end}}",
+
+		ALREADY_GROUP_MEMBER_TEXT: "Вы ўжо ўдзельнік групы",
+		ENTER_GROUP_NAME_TEXT:     "Увядзіце нумар групы, у якую хочаце ўступіць",
+		GROUP_NOT_FOUND_TEXT:      "Гэтая група не знойдзена",
+		GROUP_HAS_NO_ADMINS_TEXT:  "У гэтай групы пакуль няма адміністратараў. Папрасіце каго-небудзь з удзельнікаў групы выступіць у гэтай ролі",
+		INVALID_FULL_NAME_TEXT:    "Увядзіце прозвішча і імя як у прыведзеным узоры",
+		GROUP_REQUEST_SENT_TEXT:   "Ваша заяўка была адпраўлена адміністратарам групы",
+		GROUP_JOIN_REQUEST_TEXT:   "Карыстальнік @%s з імем \"%s\" хоча далучыцца да групы",
+		REQUEST_PENDING_TEXT:      "Ваша заяўка ўсё яшчэ разглядаецца, пачакайце",
+
+		CHOOSE_DISCIPLINE_TEXT:             "Абярыце прадмет",
+		QUEUE_EMPTY_TEXT:                   "На гэтую лабараторную няма заявак. Як ведаць, можа, вы будзеце першым",
+		INLINE_JOIN_GROUP_TEXT:             "Уступіце ў групу, каб бачыць чэргі",
+		INLINE_QUEUE_TITLE:                 "Чарга на %s\n",
+		INLINE_QUEUE_EMPTY:                 "На гэтую лабараторную няма заявак",
+		QUEUE_SUBSCRIBE_BUTTON:             "🔔 Сачыць",
+		QUEUE_SUBSCRIBE_PIN_BUTTON:         "📌 Сачыць і замацаваць",
+		QUEUE_UNSUBSCRIBE_BUTTON:           "🔕 Не сачыць",
+		QUEUE_UPDATED_TEXT:                 "\nАбноўлена ў %s",
+		QUEUE_SUBSCRIBED_TEXT:              "Паведамленне будзе абнаўляцца пры змяненні чаргі",
+		QUEUE_UNSUBSCRIBED_TEXT:            "Паведамленне больш не будзе абнаўляцца",
+		QUEUE_PIN_FAILED_TEXT:              "Не атрымалася замацаваць паведамленне, але яно будзе абнаўляцца. Дайце боту права замацоўваць паведамленні",
+		MY_REQUESTS_TITLE:                  "Вашы заяўкі:\n",
+		MY_REQUEST_LINE:                    "%d. %s, %s — лабараторная %d, месца ў чарзе: %d\n",
+		MY_REQUEST_PENDING_LINE:            "%d. %s, %s — лабараторная %d, чакае пацвярджэння\n",
+		MY_REQUESTS_EMPTY_TEXT:             "У вас няма заявак на будучыя заняткі",
+		MY_REQUEST_WITHDRAW_BUTTON:         "Адклікаць %d",
+		MY_REQUEST_CONFIRM_WITHDRAW_TEXT:   "Адклікаць заяўку?\n%s, %s — лабараторная %d",
+		MY_REQUEST_CONFIRM_WITHDRAW_BUTTON: "Так, адклікаць",
+		MY_REQUEST_WITHDRAWN_TEXT:          "Заяўка адклікана",
+		MY_REQUEST_NOT_FOUND_TEXT:          "Заяўка ўжо не існуе",
+		MY_REQUEST_EDIT_BUTTON:             "Змяніць %d",
+		MY_REQUEST_EDIT_TEXT:               "%s, %s — лабараторная %d\nШто змяніць? Фота з доказам застанецца ранейшым",
+		MY_REQUEST_CHANGE_NUMBER_BUTTON:    "Нумар лабараторнай",
+		MY_REQUEST_MOVE_BUTTON:             "Перанесці на іншы занятак",
+		MY_REQUEST_ENTER_NUMBER_TEXT:       "Увядзіце новы нумар лабараторнай",
+		MY_REQUEST_CHOOSE_LESSON_TEXT:      "Выберыце занятак, на які перанесці заяўку",
+		MY_REQUEST_NO_OTHER_LESSONS_TEXT:   "У прадмета няма іншых будучых заняткаў",
+		MY_REQUEST_UPDATED_TEXT:            "Заяўка зменена",
+
+		ADMIN_REQUEST_TEMPLATE: "(ЗАЯЎКА НА РОЛЮ АДМІНІСТРАТАРА)\nІмя: {{.Name}} \nГрупа: {{.Group}}\nІмя карыстальніка: @{{.TgName}} \n" +
+			"{{if .AdditionalInfo}}Дадатковая інфармацыя: {{.AdditionalInfo}} {{end}}",
+		ALREADY_ADMIN_TEXT:          "Вы ўжо адмін групы",
+		ENTER_IIS_GROUP_TEXT:        "Увядзіце нумар вашай групы, пазначаны ў ІІСе",
+		INVALID_IIS_GROUP_TEXT:      "Увядзіце нумар групы, якая існуе ў ІІСе",
+		ENTER_ADMIN_PROOF_TEXT:      "Дашліце доказ паўнамоцтваў, дадзеных групай (у выглядзе фота, з дадатковай тэкставай інфармацыяй па жаданні)",
+		SEND_PHOTO_TEXT:             "Адпраўце фота як частку паведамлення",
+		ADMIN_REQUEST_PENDING_TEXT:  "Пачакайце, ваш запыт на ролю адміністратара яшчэ апрацоўваецца",
+		ADMIN_REQUEST_APPROVED_TEXT: "Ваша заяўка была ўхвалена. Спасылка на гугл-табліцу: %s",
+
+		ENTER_LESSON_NAME_TEXT:       "Увядзіце назву дададзенай пары",
+		SEND_TEXT_ONLY_TEXT:          "Адпраўце тэкставае паведамленне, без прымацаваных файлаў",
+		CHOOSE_LESSON_DATE_TEXT:      "Абярыце дату дададзенай пары",
+		CHOOSE_LESSON_DATE_TIME_TEXT: "Абярыце дату і час пары",
+		LESSON_SAVED_TEXT:            "Ваша лабараторная была захавана",
+		LESSON_EXISTS_TEXT:           "Пара з такой назвай і датай ужо існуе. Калі ласка, пазначце іншую назву",
+		LESSON_NOT_CREATED_TEXT:      "Пара з такой назвай і датай не стварылася. Калі ласка, праверце, што назва падыходзіць для гугл табліц",
+		MONTH_JANUARY:                "Студзень",
+		MONTH_FEBRUARY:               "Люты",
+		MONTH_MARCH:                  "Сакавік",
+		MONTH_APRIL:                  "Красавік",
+		MONTH_MAY:                    "Май",
+		MONTH_JUNE:                   "Чэрвень",
+		MONTH_JULY:                   "Ліпень",
+		MONTH_AUGUST:                 "Жнівень",
+		MONTH_SEPTEMBER:              "Верасень",
+		MONTH_OCTOBER:                "Кастрычнік",
+		MONTH_NOVEMBER:               "Лістапад",
+		MONTH_DECEMBER:               "Снежань",
+		WEEKDAY_MONDAY:               "Пн",
+		WEEKDAY_TUESDAY:              "Аў",
+		WEEKDAY_WEDNESDAY:            "Ср",
+		WEEKDAY_THURSDAY:             "Чц",
+		WEEKDAY_FRIDAY:               "Пт",
+		WEEKDAY_SATURDAY:             "Сб",
+		WEEKDAY_SUNDAY:               "Нд",
+
+		REMINDER_TEXT: "Вы здавалі гэтую лабараторную? (%s %s, нумар лабараторнай %d)",
+
+		REORDER_INVALID_SUBJECT_TEXT:      "Абярыце карэктную назву прадмета",
+		REORDER_APPLY_ALL_TEXT:            "Ці хочаце вы ўжыць гэтыя правілы да ўсіх прадметаў? Увядзіце \"Так\"/любую іншую паслядоўнасць сімвалаў",
+		REORDER_CHOOSE_SUBJECT_TEXT:       "Абярыце прадмет для змены парадку чаргі",
+		REORDER_CHOOSE_SUBJECT_AGAIN_TEXT: "Калі ласка, абярыце прадмет для змены парадку чаргі",
+		REORDER_CHOOSE_ORDER_TEXT: "Абярыце спосабы сартавання, праз коску (парадак важны, сартаванне будзе ўжыта ў пазначаным парадку).\n" +
+			"1 - па часе адпраўкі. 2 - па нумары лабараторнай. Дадайце прэфікс + да нумара, калі хочаце сартаваць па ўбыванні",
+		REORDER_CHOOSE_LESSON_TEXT:         "Абярыце занятак для змены сартавання",
+		REORDER_CHOOSE_LESSON_AGAIN_TEXT:   "Калі ласка, абярыце занятак для змены сартавання",
+		DELETE_REQUEST_CHOOSE_SUBJECT_TEXT: "Абярыце прадмет для выдалення",
+		DELETE_REQUEST_CHOOSE_LESSON_TEXT:  "Абярыце пару для выдалення заяўкі",
+		DELETE_REQUEST_INVALID_NUMBER_TEXT: "Увядзіце карэктны нумар заяўкі",
+		DELETE_REQUEST_NUMBER_RANGE_TEXT:   "Калі ласка, увядзіце лік у межах ад 1 да %d",
+		DELETE_REQUEST_LIST_TEXT:           "Увядзіце нумар заяўкі для выдалення\n",
+		DELETE_REQUEST_LINE:                "%d. %s, лабараторная: %d\n",
+		DELETE_USER_CHOOSE_TEXT:            "Абярыце студэнта для выдалення са спіса:\n",
+		DELETE_USER_ENTER_NUMBER_TEXT:      "Увядзіце канкрэтны нумар удзельніка групы",
+		DELETE_USER_INVALID_NUMBER_TEXT:    "Увядзіце карэктны нумар удзельніка групы",
+		REORDER_INVALID_ORDER_TEXT:         "Увядзіце нумары спосабаў сартавання праз коску, напрыклад: 1,+2",
+		REORDER_DONE_TEXT:                  "Парадак чаргі зменены",
+		DELETE_REQUEST_EMPTY_TEXT:          "На гэты занятак няма заявак",
+		DELETE_REQUEST_DELETED_TEXT:        "Заяўка выдалена",
+		NO_SUBJECTS_TEXT:                   "У раскладзе групы няма прадметаў",
+		NO_LESSONS_TEXT:                    "У прадмета няма будучых заняткаў",
+
+		SETTINGS_TEXT:                 "Налады\n\nАпавяшчэнні пра чаргу: %s",
+		SETTINGS_UNAVAILABLE_TEXT:     "Налады будуць даступныя пасля ўступлення ў групу",
+		TURN_NOTIFICATIONS_ON_BUTTON:  "Уключыць апавяшчэнні пра чаргу",
+		TURN_NOTIFICATIONS_OFF_BUTTON: "Адключыць апавяшчэнні пра чаргу",
+		TURN_NOTIFICATIONS_ENABLED:    "уключаны",
+		TURN_NOTIFICATIONS_DISABLED:   "адключаны",
+		TURN_NEXT_TEXT:                "Вы наступны ў чарзе на лабараторную %d па прадмеце %s\n\nАдключыць апавяшчэнні можна ў /settings",
+		TURN_POSITION_TEXT:            "Ваша месца ў чарзе на лабараторную %d па прадмеце %s: %d\n\nАдключыць апавяшчэнні можна ў /settings",
+
+		LESSON_MODE_CHOOSE_SUBJECT_TEXT: "Абярыце прадмет для правядзення заняткаў",
+		LESSON_MODE_CHOOSE_LESSON_TEXT:  "Абярыце заняткі",
+		LESSON_MODE_TITLE:               "%s, %s\n\n",
+		LESSON_MODE_LINE:                "%s %d. %s — лабараторная %d\n",
+		LESSON_MODE_CURRENT_TEXT:        "\nЗдае: %s",
+		LESSON_MODE_FINISHED_TEXT:       "\nУсе студэнты з чаргі выкліканы",
+		LESSON_MODE_NEXT_BUTTON:         "Наступны",
+		LESSON_MODE_SKIP_BUTTON:         "Прапусціць",
+		LESSON_MODE_PASSED_BUTTON:       "Здаў",
+		LESSON_MODE_FAILED_BUTTON:       "Не здаў",
+		LESSON_MODE_ABSENT_BUTTON:       "Адсутнічае",
+		LESSON_MODE_YOUR_TURN_TEXT:      "Ваша чарга здаваць лабараторную %d па прадмеце %s",
+		LESSON_MODE_GRADE_BUTTON:        "Адзнака і каментарый: %s",
+		LESSON_MODE_ENTER_GRADE_TEXT:    "Увядзіце адзнаку ад 1 да 10 і каментарый для %s, лабараторная %d, напрыклад: 8 Добра\n\nАдзнаку або каментарый можна не ўказваць",
+		LESSON_MODE_INVALID_GRADE_TEXT:  "Адзнака павінна быць лікам ад 1 да 10",
+		LESSON_MODE_GRADE_SAVED_TEXT:    "Адзнака захавана",
+
+		RESULTS_TITLE:      "Вашы вынікі па лабараторных\n",
+		RESULTS_EMPTY_TEXT: "У вас пакуль няма вынікаў па лабараторных",
+		RESULTS_SUBJECT:    "\n%s\n",
+		RESULTS_LINE:       "Лабараторная %d: %s",
+		RESULTS_GRADE:      ", адзнака %d",
+		RESULTS_COMMENT:    " — %s",
+		RESULTS_MARKED_BY:  " (%s, %s)\n",
+		RESULTS_MARKED_AT:  " (%s)\n",
+		OUTCOME_PASSED:     "здадзена",
+		OUTCOME_FAILED:     "не здадзена",
+		OUTCOME_ABSENT:     "адсутнасць",
+	},
+	Plurals: map[Key]Forms{
+		QUEUE_SIZE: {
+			One:  "%d заяўка",
+			Few:  "%d заяўкі",
+			Many: "%d заявак",
+		},
+	},
+}
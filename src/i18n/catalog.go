@@ -0,0 +1,117 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+)
+
+type Key string
+
+type PluralForm int8
+
+const (
+	One PluralForm = iota
+	Few
+	Many
+	Other
+)
+
+// Forms are translations of the message, which depends on the count, for every plural form of the locale
+type Forms map[PluralForm]string
+
+// Catalog contains translations of all of the messages for one locale. Translations are format strings for fmt.Sprintf
+type Catalog struct {
+	Name     string
+	Messages map[Key]string
+	Plurals  map[Key]Forms
+	// Plural returns form of the count according to grammar of the locale
+	Plural func(count int) PluralForm
+}
+
+var catalogs = map[Locale]*Catalog{
+	RU: &ru,
+	EN: &en,
+	BE: &be,
+}
+
+// GetCatalog returns catalog of the locale, falling back to the default one for unsupported locales
+func GetCatalog(locale Locale) *Catalog {
+	catalog, ok := catalogs[locale]
+	if !ok {
+		return catalogs[DEFAULT_LOCALE]
+	}
+	return catalog
+}
+
+// Name returns the name of the language of the locale in that language
+func Name(locale Locale) string {
+	return GetCatalog(locale).Name
+}
+
+// T translates message into locale of the context, formatting it with args. Messages, missing in the locale,
+// are taken from the default one, and key itself is returned, if it is missing there too
+func T(ctx context.Context, key Key, args ...any) string {
+	return Translate(FromContext(ctx), key, args...)
+}
+
+func Translate(locale Locale, key Key, args ...any) string {
+	message, ok := GetCatalog(locale).Messages[key]
+	if !ok {
+		message, ok = GetCatalog(DEFAULT_LOCALE).Messages[key]
+	}
+	if !ok {
+		return string(key)
+	}
+	return format(message, args)
+}
+
+// N is the same as T, but chooses plural form of the message by count. Count is passed as the first argument of the format
+func N(ctx context.Context, key Key, count int, args ...any) string {
+	return TranslatePlural(FromContext(ctx), key, count, args...)
+}
+
+func TranslatePlural(locale Locale, key Key, count int, args ...any) string {
+	catalog := GetCatalog(locale)
+	forms, ok := catalog.Plurals[key]
+	if !ok {
+		catalog = GetCatalog(DEFAULT_LOCALE)
+		forms, ok = catalog.Plurals[key]
+	}
+	if !ok {
+		return string(key)
+	}
+	message, ok := forms[catalog.Plural(count)]
+	if !ok {
+		message = forms[Other]
+	}
+	return format(message, append([]any{count}, args...))
+}
+
+func format(message string, args []any) string {
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// slavicPlural is the plural rule of russian and belarusian languages for integers
+func slavicPlural(count int) PluralForm {
+	if count < 0 {
+		count = -count
+	}
+	switch {
+	case count%10 == 1 && count%100 != 11:
+		return One
+	case count%10 >= 2 && count%10 <= 4 && (count%100 < 12 || count%100 > 14):
+		return Few
+	default:
+		return Many
+	}
+}
+
+func englishPlural(count int) PluralForm {
+	if count == 1 || count == -1 {
+		return One
+	}
+	return Other
+}
@@ -0,0 +1,224 @@
+package i18n
+
+var en = Catalog{
+	Name:   "English",
+	Plural: englishPlural,
+	Messages: map[Key]string{
+		BACK_BUTTON:              "Back",
+		ACCEPT_BUTTON:            "Accept",
+		DECLINE_BUTTON:           "Decline",
+		YES:                      "Yes",
+		NO:                       "No",
+		NO_GROUP_TEXT:            "You don't belong to any group yet",
+		NO_LABWORKS_LEFT_TEXT:    "There are no labworks left. Take a rest",
+		ENTER_FULL_NAME_TEXT:     "Enter your surname and name (Format example: Ivanov Ivan)",
+		MESSAGE_TOO_LONG_TEXT:    "Your message exceeds the telegram limits of message size. Please, shorten it and send again",
+		REQUEST_APPROVED_TEXT:    "Your request was approved",
+		REQUEST_DECLINED_TEXT:    "Your request was declined",
+		GOOGLE_ERROR_TEXT:        "Google services failed. Please, try again later",
+		GOOGLE_ERROR_ACCEPT_TEXT: "Google services failed. Try to accept the request later",
+		CANCELLED_TEXT:           "Current action was cancelled",
+		CRASH_TEXT:               "Unexpected error occurred, current action was cancelled. Try to start again",
+		NOT_ADMIN_TEXT:           "You must be an admin to use this command",
+
+		FLOW_EXPIRED_TEXT:      "Waiting for the answer timed out, the action was cancelled",
+		BUTTONS_EXPIRED_TEXT:   "These buttons have expired",
+		PAYLOAD_EXPIRED_TEXT:   "This button is outdated, start the action again",
+		ALREADY_PROCESSED_TEXT: "Already processed",
+		PRIVATE_ONLY_TEXT:      "Command %s is available only in private messages with the bot",
+		PRIVATE_CHAT_BUTTON:    "Go to private messages",
+		FOREIGN_CALLBACK_TEXT:  "This button is meant for another user",
+		JOIN_GROUP_BUTTON:      "Join a group",
+		ASSIGN_BUTTON:          "Become an admin of a group",
+		SUBMIT_BUTTON:          "Submit a labwork request",
+		ADD_LABWORK_BUTTON:     "Add a custom labwork",
+
+		HELP_DESCRIPTION:           "Commands and information",
+		SUBMIT_DESCRIPTION:         "Sign up for a labwork submission",
+		ASSIGN_DESCRIPTION:         "Request the role of the group admin",
+		JOIN_GROUP_DESCRIPTION:     "Request to join a group",
+		QUEUE_DESCRIPTION:          "Get the queue of your group",
+		REVERT_DESCRIPTION:         "Return to the previous step",
+		CANCEL_DESCRIPTION:         "Cancel the current action",
+		TABLE_DESCRIPTION:          "Get the link to the google sheet of your group",
+		LANGUAGE_DESCRIPTION:       "Choose the language of the bot",
+		ADD_LABWORK_DESCRIPTION:    "Add a custom lesson",
+		DELETE_DESCRIPTION:         "Remove a member from the group",
+		REORDER_DESCRIPTION:        "Change the queue order of a subject",
+		REMOVE_REQUEST_DESCRIPTION: "Remove a request from the queue",
+		CRASHES_DESCRIPTION:        "Latest crash reports",
+		MY_REQUESTS_DESCRIPTION:    "My requests",
+		LESSON_MODE_DESCRIPTION:    "Lesson mode: call students from the queue",
+		SETTINGS_DESCRIPTION:       "Settings",
+		RESULTS_DESCRIPTION:        "My labwork results",
+
+		START_TEXT: "Use /help to get the list of commands. To submit labwork requests " +
+			"you must either become an admin of your group, approved by the owner of the bot, or a member of a group, which already has an admin.",
+		NO_CRASHES_TEXT:  "No crashes were recorded",
+		CRASH_CHAT_LINE:  "Chat: %d, user: %d, update: %d\n",
+		CRASH_STATE_LINE: "State: %s\n",
+		CRASH_TEXT_LINE:  "Text: %s\n",
+		CRASH_ERROR_LINE: "Error: %s\n",
+
+		CHOOSE_LANGUAGE_TEXT:      "Choose the language",
+		LANGUAGE_CHANGED_TEXT:     "Language was changed to English",
+		LANGUAGE_UNAVAILABLE_TEXT: "You can choose the language after joining a group, until then the language of your telegram client is used",
+
+		CHOOSE_DISCIPLINE_AND_DATE_TEXT: "Choose the discipline and the date of the lesson",
+		FINISH_REQUEST_FIRST_TEXT:       "Please, finish sending the request before using other commands",
+		ENTER_LABWORK_NUMBER_TEXT:       "Enter the number of the labwork you submit",
+		INVALID_LABWORK_NUMBER_TEXT:     "Please, enter a valid number of the labwork (a single reasonable number)",
+		ENTER_LABWORK_PROOF_TEXT:        "Send a proof that the labwork is done (a single attached file, optionally with a caption)",
+		LABWORK_REQUEST_SENT_TEXT:       "Your request was sent to the admins",
+		LABWORK_REQUEST_ACCEPTED_TEXT:   "Your request was accepted",
+		LABWORK_REQUEST_WITHDRAWN_TEXT:  "The student has withdrawn this request",
+		LABWORK_REQUEST_TEMPLATE: "Sent by: {{.FullName}}\nDiscipline: {{.DisciplineName}}\nLabwork number: {{.LabworkNumber}}\n" +
+			"Date: {{date .RequestedDate}}\nSent at: {{dateTime .SentProofTime}}\n{{if .Notes}}Notes: {{.Notes}} {{end}}",
+
+		ALREADY_GROUP_MEMBER_TEXT: "You are already a member of a group",
+		ENTER_GROUP_NAME_TEXT:     "Enter the number of the group you want to join",
+		GROUP_NOT_FOUND_TEXT:      "This group wasn't found",
+		GROUP_HAS_NO_ADMINS_TEXT:  "This group has no admins yet. Ask one of the members of the group to become one",
+		INVALID_FULL_NAME_TEXT:    "Enter your surname and name as in the example",
+		GROUP_REQUEST_SENT_TEXT:   "Your request was sent to the admins of the group",
+		GROUP_JOIN_REQUEST_TEXT:   "User @%s with name \"%s\" wants to join the group",
+		REQUEST_PENDING_TEXT:      "Your request is still being reviewed, please wait",
+
+		CHOOSE_DISCIPLINE_TEXT:             "Choose the discipline",
+		QUEUE_EMPTY_TEXT:                   "There are no requests for this labwork. Who knows, maybe you will be the first",
+		INLINE_JOIN_GROUP_TEXT:             "Join a group to see the queues",
+		INLINE_QUEUE_TITLE:                 "Queue for %s\n",
+		INLINE_QUEUE_EMPTY:                 "There are no requests for this labwork",
+		QUEUE_SUBSCRIBE_BUTTON:             "🔔 Follow",
+		QUEUE_SUBSCRIBE_PIN_BUTTON:         "📌 Follow and pin",
+		QUEUE_UNSUBSCRIBE_BUTTON:           "🔕 Unfollow",
+		QUEUE_UPDATED_TEXT:                 "\nUpdated at %s",
+		QUEUE_SUBSCRIBED_TEXT:              "The message will be updated, whenever the queue changes",
+		QUEUE_UNSUBSCRIBED_TEXT:            "The message won't be updated anymore",
+		QUEUE_PIN_FAILED_TEXT:              "Couldn't pin the message, but it will be updated. Allow the bot to pin messages",
+		MY_REQUESTS_TITLE:                  "Your requests:\n",
+		MY_REQUEST_LINE:                    "%d. %s, %s — labwork %d, place in the queue: %d\n",
+		MY_REQUEST_PENDING_LINE:            "%d. %s, %s — labwork %d, awaiting approval\n",
+		MY_REQUESTS_EMPTY_TEXT:             "You have no requests for upcoming lessons",
+		MY_REQUEST_WITHDRAW_BUTTON:         "Withdraw %d",
+		MY_REQUEST_CONFIRM_WITHDRAW_TEXT:   "Withdraw the request?\n%s, %s — labwork %d",
+		MY_REQUEST_CONFIRM_WITHDRAW_BUTTON: "Yes, withdraw",
+		MY_REQUEST_WITHDRAWN_TEXT:          "The request was withdrawn",
+		MY_REQUEST_NOT_FOUND_TEXT:          "The request no longer exists",
+		MY_REQUEST_EDIT_BUTTON:             "Edit %d",
+		MY_REQUEST_EDIT_TEXT:               "%s, %s — labwork %d\nWhat should be changed? The proof stays the same",
+		MY_REQUEST_CHANGE_NUMBER_BUTTON:    "Labwork number",
+		MY_REQUEST_MOVE_BUTTON:             "Move to another lesson",
+		MY_REQUEST_ENTER_NUMBER_TEXT:       "Enter the new number of the labwork",
+		MY_REQUEST_CHOOSE_LESSON_TEXT:      "Choose the lesson to move the request to",
+		MY_REQUEST_NO_OTHER_LESSONS_TEXT:   "The subject has no other upcoming lessons",
+		MY_REQUEST_UPDATED_TEXT:            "The request was changed",
+
+		ADMIN_REQUEST_TEMPLATE: "(ADMIN ROLE REQUEST)\nName: {{.Name}} \nGroup: {{.Group}}\nUsername: @{{.TgName}} \n" +
+			"{{if .AdditionalInfo}}Notes: {{.AdditionalInfo}} {{end}}",
+		ALREADY_ADMIN_TEXT:          "You are already an admin of the group",
+		ENTER_IIS_GROUP_TEXT:        "Enter the number of your group, as in IIS",
+		INVALID_IIS_GROUP_TEXT:      "Enter the number of a group, which exists in IIS",
+		ENTER_ADMIN_PROOF_TEXT:      "Provide a proof of the authority, granted by the group (as a photo, optionally with additional text)",
+		SEND_PHOTO_TEXT:             "Send the photo as a part of the message",
+		ADMIN_REQUEST_PENDING_TEXT:  "Please wait, your admin role request is still being processed",
+		ADMIN_REQUEST_APPROVED_TEXT: "Your request was approved. Link to the google sheet: %s",
+
+		ENTER_LESSON_NAME_TEXT:       "Enter the name of the added lesson",
+		SEND_TEXT_ONLY_TEXT:          "Send a text message without attached files",
+		CHOOSE_LESSON_DATE_TEXT:      "Choose the date of the added lesson",
+		CHOOSE_LESSON_DATE_TIME_TEXT: "Choose the date and the time of the lesson",
+		LESSON_SAVED_TEXT:            "Your labwork was saved",
+		LESSON_EXISTS_TEXT:           "Lesson with this name and date already exists. Please, choose another name",
+		LESSON_NOT_CREATED_TEXT:      "Lesson with this name and date wasn't created. Please, check that the name is valid for google sheets",
+		MONTH_JANUARY:                "January",
+		MONTH_FEBRUARY:               "February",
+		MONTH_MARCH:                  "March",
+		MONTH_APRIL:                  "April",
+		MONTH_MAY:                    "May",
+		MONTH_JUNE:                   "June",
+		MONTH_JULY:                   "July",
+		MONTH_AUGUST:                 "August",
+		MONTH_SEPTEMBER:              "September",
+		MONTH_OCTOBER:                "October",
+		MONTH_NOVEMBER:               "November",
+		MONTH_DECEMBER:               "December",
+		WEEKDAY_MONDAY:               "Mo",
+		WEEKDAY_TUESDAY:              "Tu",
+		WEEKDAY_WEDNESDAY:            "We",
+		WEEKDAY_THURSDAY:             "Th",
+		WEEKDAY_FRIDAY:               "Fr",
+		WEEKDAY_SATURDAY:             "Sa",
+		WEEKDAY_SUNDAY:               "Su",
+
+		REMINDER_TEXT: "Have you submitted this labwork? (%s %s, labwork number %d)",
+
+		REORDER_INVALID_SUBJECT_TEXT:      "Choose a valid name of the discipline",
+		REORDER_APPLY_ALL_TEXT:            "Do you want to apply these rules to all of the disciplines? Enter \"Yes\"/any other text",
+		REORDER_CHOOSE_SUBJECT_TEXT:       "Choose the discipline to change the order of its queue",
+		REORDER_CHOOSE_SUBJECT_AGAIN_TEXT: "Please, choose the discipline to change the order of its queue",
+		REORDER_CHOOSE_ORDER_TEXT: "Choose the ways of sorting, separated by commas (order matters, sorting is applied in the given order).\n" +
+			"1 - by time of sending. 2 - by number of the labwork. Add + prefix to the number to sort in descending order",
+		REORDER_CHOOSE_LESSON_TEXT:         "Choose the lesson to change its sorting",
+		REORDER_CHOOSE_LESSON_AGAIN_TEXT:   "Please, choose the lesson to change its sorting",
+		DELETE_REQUEST_CHOOSE_SUBJECT_TEXT: "Choose the discipline to delete from",
+		DELETE_REQUEST_CHOOSE_LESSON_TEXT:  "Choose the lesson to delete the request of",
+		DELETE_REQUEST_INVALID_NUMBER_TEXT: "Enter a valid number of the request",
+		DELETE_REQUEST_NUMBER_RANGE_TEXT:   "Please, enter a valid number from 1 to %d",
+		DELETE_REQUEST_LIST_TEXT:           "Enter the number of the request to delete\n",
+		DELETE_REQUEST_LINE:                "%d. %s, labwork: %d\n",
+		DELETE_USER_CHOOSE_TEXT:            "Choose the student to remove from the list:\n",
+		DELETE_USER_ENTER_NUMBER_TEXT:      "Enter the number of the member of the group",
+		DELETE_USER_INVALID_NUMBER_TEXT:    "Enter a valid number of the member of the group",
+		REORDER_INVALID_ORDER_TEXT:         "Enter the numbers of the sorting methods separated by commas, e.g.: 1,+2",
+		REORDER_DONE_TEXT:                  "The queue order is changed",
+		DELETE_REQUEST_EMPTY_TEXT:          "There are no requests for this lesson",
+		DELETE_REQUEST_DELETED_TEXT:        "The request is removed",
+		NO_SUBJECTS_TEXT:                   "There are no subjects in the schedule of the group",
+		NO_LESSONS_TEXT:                    "The subject has no upcoming lessons",
+
+		SETTINGS_TEXT:                 "Settings\n\nQueue notifications: %s",
+		SETTINGS_UNAVAILABLE_TEXT:     "Settings will be available after joining a group",
+		TURN_NOTIFICATIONS_ON_BUTTON:  "Turn queue notifications on",
+		TURN_NOTIFICATIONS_OFF_BUTTON: "Turn queue notifications off",
+		TURN_NOTIFICATIONS_ENABLED:    "on",
+		TURN_NOTIFICATIONS_DISABLED:   "off",
+		TURN_NEXT_TEXT:                "You are next in the queue for labwork %d of %s\n\nNotifications can be turned off in /settings",
+		TURN_POSITION_TEXT:            "Your place in the queue for labwork %d of %s: %d\n\nNotifications can be turned off in /settings",
+
+		LESSON_MODE_CHOOSE_SUBJECT_TEXT: "Choose the subject of the lesson",
+		LESSON_MODE_CHOOSE_LESSON_TEXT:  "Choose the lesson",
+		LESSON_MODE_TITLE:               "%s, %s\n\n",
+		LESSON_MODE_LINE:                "%s %d. %s — labwork %d\n",
+		LESSON_MODE_CURRENT_TEXT:        "\nNow passing: %s",
+		LESSON_MODE_FINISHED_TEXT:       "\nAll students from the queue have been called",
+		LESSON_MODE_NEXT_BUTTON:         "Next",
+		LESSON_MODE_SKIP_BUTTON:         "Skip",
+		LESSON_MODE_PASSED_BUTTON:       "Passed",
+		LESSON_MODE_FAILED_BUTTON:       "Failed",
+		LESSON_MODE_ABSENT_BUTTON:       "Absent",
+		LESSON_MODE_YOUR_TURN_TEXT:      "It is your turn to pass labwork %d of %s",
+		LESSON_MODE_GRADE_BUTTON:        "Grade and comment: %s",
+		LESSON_MODE_ENTER_GRADE_TEXT:    "Enter the grade from 1 to 10 and the comment for %s, labwork %d, e.g.: 8 Good\n\nEither the grade or the comment can be omitted",
+		LESSON_MODE_INVALID_GRADE_TEXT:  "The grade must be a number from 1 to 10",
+		LESSON_MODE_GRADE_SAVED_TEXT:    "The grade has been saved",
+
+		RESULTS_TITLE:      "Your labwork results\n",
+		RESULTS_EMPTY_TEXT: "You have no labwork results yet",
+		RESULTS_SUBJECT:    "\n%s\n",
+		RESULTS_LINE:       "Labwork %d: %s",
+		RESULTS_GRADE:      ", grade %d",
+		RESULTS_COMMENT:    " — %s",
+		RESULTS_MARKED_BY:  " (%s, %s)\n",
+		RESULTS_MARKED_AT:  " (%s)\n",
+		OUTCOME_PASSED:     "passed",
+		OUTCOME_FAILED:     "failed",
+		OUTCOME_ABSENT:     "absent",
+	},
+	Plurals: map[Key]Forms{
+		QUEUE_SIZE: {
+			One:   "%d request",
+			Other: "%d requests",
+		},
+	},
+}
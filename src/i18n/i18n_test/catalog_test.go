@@ -0,0 +1,86 @@
+package i18ntest
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+)
+
+var verbRegexp = regexp.MustCompile(`%[^%]`)
+
+func TestCatalogsAreComplete(t *testing.T) {
+	reference := i18n.GetCatalog(i18n.DEFAULT_LOCALE)
+	for _, locale := range i18n.Locales {
+		catalog := i18n.GetCatalog(locale)
+		if len(catalog.Messages) != len(reference.Messages) {
+			t.Errorf("%s has %d messages, want %d", locale, len(catalog.Messages), len(reference.Messages))
+		}
+		for key, message := range reference.Messages {
+			translated, ok := catalog.Messages[key]
+			if !ok {
+				t.Errorf("%s misses message %s", locale, key)
+				continue
+			}
+			if got, want := len(verbRegexp.FindAllString(translated, -1)), len(verbRegexp.FindAllString(message, -1)); got != want {
+				t.Errorf("%s message %s has %d format verbs, want %d", locale, key, got, want)
+			}
+		}
+
+		forms := map[i18n.PluralForm]bool{}
+		for count := range 200 {
+			forms[catalog.Plural(count)] = true
+		}
+		for key := range reference.Plurals {
+			translated, ok := catalog.Plurals[key]
+			if !ok {
+				t.Errorf("%s misses plural %s", locale, key)
+				continue
+			}
+			for form := range forms {
+				if _, ok := translated[form]; !ok {
+					t.Errorf("%s plural %s misses form %d", locale, key, form)
+				}
+			}
+		}
+	}
+}
+
+func TestPlurals(t *testing.T) {
+	tests := []struct {
+		locale i18n.Locale
+		count  int
+		want   string
+	}{
+		{i18n.RU, 1, "1 заявка"},
+		{i18n.RU, 3, "3 заявки"},
+		{i18n.RU, 11, "11 заявок"},
+		{i18n.RU, 22, "22 заявки"},
+		{i18n.RU, 0, "0 заявок"},
+		{i18n.BE, 21, "21 заяўка"},
+		{i18n.EN, 1, "1 request"},
+		{i18n.EN, 0, "0 requests"},
+	}
+	for _, test := range tests {
+		got := i18n.N(i18n.WithLocale(context.Background(), test.locale), i18n.QUEUE_SIZE, test.count)
+		if got != test.want {
+			t.Errorf("N(%s, %d) = %s, want %s", test.locale, test.count, got, test.want)
+		}
+	}
+}
+
+func TestLocaleFallbacks(t *testing.T) {
+	if got := i18n.FromContext(context.Background()); got != i18n.DEFAULT_LOCALE {
+		t.Errorf("FromContext without locale = %s, want %s", got, i18n.DEFAULT_LOCALE)
+	}
+	codes := map[string]i18n.Locale{"": i18n.RU, "ru": i18n.RU, "be": i18n.BE, "en-US": i18n.EN, "de": i18n.FOREIGN_LOCALE}
+	for code, want := range codes {
+		if got := i18n.FromLanguageCode(code); got != want {
+			t.Errorf("FromLanguageCode(%s) = %s, want %s", code, got, want)
+		}
+	}
+	if got := i18n.Translate(i18n.EN, "missing_key"); got != "missing_key" {
+		t.Errorf("Translate of missing key = %s, want key itself", got)
+	}
+}
@@ -0,0 +1,260 @@
+package i18n
+
+// Common
+const (
+	BACK_BUTTON              Key = "back_button"
+	ACCEPT_BUTTON            Key = "accept_button"
+	DECLINE_BUTTON           Key = "decline_button"
+	YES                      Key = "yes"
+	NO                       Key = "no"
+	NO_GROUP_TEXT            Key = "no_group_text"
+	NO_LABWORKS_LEFT_TEXT    Key = "no_labworks_left_text"
+	ENTER_FULL_NAME_TEXT     Key = "enter_full_name_text"
+	MESSAGE_TOO_LONG_TEXT    Key = "message_too_long_text"
+	REQUEST_APPROVED_TEXT    Key = "request_approved_text"
+	REQUEST_DECLINED_TEXT    Key = "request_declined_text"
+	GOOGLE_ERROR_TEXT        Key = "google_error_text"
+	GOOGLE_ERROR_ACCEPT_TEXT Key = "google_error_accept_text"
+	CANCELLED_TEXT           Key = "cancelled_text"
+	CRASH_TEXT               Key = "crash_text"
+	NOT_ADMIN_TEXT           Key = "not_admin_text"
+)
+
+// Flows and buttons, handled by tg utils
+const (
+	FLOW_EXPIRED_TEXT      Key = "flow_expired_text"
+	BUTTONS_EXPIRED_TEXT   Key = "buttons_expired_text"
+	PAYLOAD_EXPIRED_TEXT   Key = "payload_expired_text"
+	ALREADY_PROCESSED_TEXT Key = "already_processed_text"
+	PRIVATE_ONLY_TEXT      Key = "private_only_text"
+	PRIVATE_CHAT_BUTTON    Key = "private_chat_button"
+	FOREIGN_CALLBACK_TEXT  Key = "foreign_callback_text"
+	JOIN_GROUP_BUTTON      Key = "join_group_button"
+	ASSIGN_BUTTON          Key = "assign_button"
+	SUBMIT_BUTTON          Key = "submit_button"
+	ADD_LABWORK_BUTTON     Key = "add_labwork_button"
+)
+
+// Descriptions of commands
+const (
+	HELP_DESCRIPTION           Key = "help_description"
+	SUBMIT_DESCRIPTION         Key = "submit_description"
+	ASSIGN_DESCRIPTION         Key = "assign_description"
+	JOIN_GROUP_DESCRIPTION     Key = "join_group_description"
+	QUEUE_DESCRIPTION          Key = "queue_description"
+	REVERT_DESCRIPTION         Key = "revert_description"
+	CANCEL_DESCRIPTION         Key = "cancel_description"
+	TABLE_DESCRIPTION          Key = "table_description"
+	LANGUAGE_DESCRIPTION       Key = "language_description"
+	ADD_LABWORK_DESCRIPTION    Key = "add_labwork_description"
+	DELETE_DESCRIPTION         Key = "delete_description"
+	REORDER_DESCRIPTION        Key = "reorder_description"
+	REMOVE_REQUEST_DESCRIPTION Key = "remove_request_description"
+	CRASHES_DESCRIPTION        Key = "crashes_description"
+	MY_REQUESTS_DESCRIPTION    Key = "my_requests_description"
+	LESSON_MODE_DESCRIPTION    Key = "lesson_mode_description"
+	SETTINGS_DESCRIPTION       Key = "settings_description"
+	RESULTS_DESCRIPTION        Key = "results_description"
+)
+
+// Idle state
+const (
+	START_TEXT       Key = "start_text"
+	NO_CRASHES_TEXT  Key = "no_crashes_text"
+	CRASH_CHAT_LINE  Key = "crash_chat_line"
+	CRASH_STATE_LINE Key = "crash_state_line"
+	CRASH_TEXT_LINE  Key = "crash_text_line"
+	CRASH_ERROR_LINE Key = "crash_error_line"
+)
+
+// Language
+const (
+	CHOOSE_LANGUAGE_TEXT      Key = "choose_language_text"
+	LANGUAGE_CHANGED_TEXT     Key = "language_changed_text"
+	LANGUAGE_UNAVAILABLE_TEXT Key = "language_unavailable_text"
+)
+
+// Labworks submission
+const (
+	CHOOSE_DISCIPLINE_AND_DATE_TEXT Key = "choose_discipline_and_date_text"
+	FINISH_REQUEST_FIRST_TEXT       Key = "finish_request_first_text"
+	ENTER_LABWORK_NUMBER_TEXT       Key = "enter_labwork_number_text"
+	INVALID_LABWORK_NUMBER_TEXT     Key = "invalid_labwork_number_text"
+	ENTER_LABWORK_PROOF_TEXT        Key = "enter_labwork_proof_text"
+	LABWORK_REQUEST_SENT_TEXT       Key = "labwork_request_sent_text"
+	LABWORK_REQUEST_ACCEPTED_TEXT   Key = "labwork_request_accepted_text"
+	LABWORK_REQUEST_WITHDRAWN_TEXT  Key = "labwork_request_withdrawn_text"
+	// text/template of the request, sent to admins
+	LABWORK_REQUEST_TEMPLATE Key = "labwork_request_template"
+)
+
+// Own requests of the user
+const (
+	MY_REQUESTS_TITLE                  Key = "my_requests_title"
+	MY_REQUEST_LINE                    Key = "my_request_line"
+	MY_REQUEST_PENDING_LINE            Key = "my_request_pending_line"
+	MY_REQUESTS_EMPTY_TEXT             Key = "my_requests_empty_text"
+	MY_REQUEST_WITHDRAW_BUTTON         Key = "my_request_withdraw_button"
+	MY_REQUEST_CONFIRM_WITHDRAW_TEXT   Key = "my_request_confirm_withdraw_text"
+	MY_REQUEST_CONFIRM_WITHDRAW_BUTTON Key = "my_request_confirm_withdraw_button"
+	MY_REQUEST_WITHDRAWN_TEXT          Key = "my_request_withdrawn_text"
+	MY_REQUEST_NOT_FOUND_TEXT          Key = "my_request_not_found_text"
+	MY_REQUEST_EDIT_BUTTON             Key = "my_request_edit_button"
+	MY_REQUEST_EDIT_TEXT               Key = "my_request_edit_text"
+	MY_REQUEST_CHANGE_NUMBER_BUTTON    Key = "my_request_change_number_button"
+	MY_REQUEST_MOVE_BUTTON             Key = "my_request_move_button"
+	MY_REQUEST_ENTER_NUMBER_TEXT       Key = "my_request_enter_number_text"
+	MY_REQUEST_CHOOSE_LESSON_TEXT      Key = "my_request_choose_lesson_text"
+	MY_REQUEST_NO_OTHER_LESSONS_TEXT   Key = "my_request_no_other_lessons_text"
+	MY_REQUEST_UPDATED_TEXT            Key = "my_request_updated_text"
+)
+
+// Joining the group
+const (
+	ALREADY_GROUP_MEMBER_TEXT Key = "already_group_member_text"
+	ENTER_GROUP_NAME_TEXT     Key = "enter_group_name_text"
+	GROUP_NOT_FOUND_TEXT      Key = "group_not_found_text"
+	GROUP_HAS_NO_ADMINS_TEXT  Key = "group_has_no_admins_text"
+	INVALID_FULL_NAME_TEXT    Key = "invalid_full_name_text"
+	GROUP_REQUEST_SENT_TEXT   Key = "group_request_sent_text"
+	GROUP_JOIN_REQUEST_TEXT   Key = "group_join_request_text"
+	REQUEST_PENDING_TEXT      Key = "request_pending_text"
+)
+
+// Queue
+const (
+	CHOOSE_DISCIPLINE_TEXT     Key = "choose_discipline_text"
+	QUEUE_EMPTY_TEXT           Key = "queue_empty_text"
+	INLINE_JOIN_GROUP_TEXT     Key = "inline_join_group_text"
+	INLINE_QUEUE_TITLE         Key = "inline_queue_title"
+	INLINE_QUEUE_EMPTY         Key = "inline_queue_empty"
+	QUEUE_SUBSCRIBE_BUTTON     Key = "queue_subscribe_button"
+	QUEUE_SUBSCRIBE_PIN_BUTTON Key = "queue_subscribe_pin_button"
+	QUEUE_UNSUBSCRIBE_BUTTON   Key = "queue_unsubscribe_button"
+	QUEUE_UPDATED_TEXT         Key = "queue_updated_text"
+	QUEUE_SUBSCRIBED_TEXT      Key = "queue_subscribed_text"
+	QUEUE_UNSUBSCRIBED_TEXT    Key = "queue_unsubscribed_text"
+	QUEUE_PIN_FAILED_TEXT      Key = "queue_pin_failed_text"
+	// Plural
+	QUEUE_SIZE Key = "queue_size"
+)
+
+// Admin role submission
+const (
+	ADMIN_REQUEST_TEMPLATE      Key = "admin_request_template"
+	ALREADY_ADMIN_TEXT          Key = "already_admin_text"
+	ENTER_IIS_GROUP_TEXT        Key = "enter_iis_group_text"
+	INVALID_IIS_GROUP_TEXT      Key = "invalid_iis_group_text"
+	ENTER_ADMIN_PROOF_TEXT      Key = "enter_admin_proof_text"
+	SEND_PHOTO_TEXT             Key = "send_photo_text"
+	ADMIN_REQUEST_PENDING_TEXT  Key = "admin_request_pending_text"
+	ADMIN_REQUEST_APPROVED_TEXT Key = "admin_request_approved_text"
+)
+
+// Custom labworks
+const (
+	ENTER_LESSON_NAME_TEXT       Key = "enter_lesson_name_text"
+	SEND_TEXT_ONLY_TEXT          Key = "send_text_only_text"
+	CHOOSE_LESSON_DATE_TEXT      Key = "choose_lesson_date_text"
+	CHOOSE_LESSON_DATE_TIME_TEXT Key = "choose_lesson_date_time_text"
+	LESSON_SAVED_TEXT            Key = "lesson_saved_text"
+	LESSON_EXISTS_TEXT           Key = "lesson_exists_text"
+	LESSON_NOT_CREATED_TEXT      Key = "lesson_not_created_text"
+	MONTH_JANUARY                Key = "month_january"
+	MONTH_FEBRUARY               Key = "month_february"
+	MONTH_MARCH                  Key = "month_march"
+	MONTH_APRIL                  Key = "month_april"
+	MONTH_MAY                    Key = "month_may"
+	MONTH_JUNE                   Key = "month_june"
+	MONTH_JULY                   Key = "month_july"
+	MONTH_AUGUST                 Key = "month_august"
+	MONTH_SEPTEMBER              Key = "month_september"
+	MONTH_OCTOBER                Key = "month_october"
+	MONTH_NOVEMBER               Key = "month_november"
+	MONTH_DECEMBER               Key = "month_december"
+	WEEKDAY_MONDAY               Key = "weekday_monday"
+	WEEKDAY_TUESDAY              Key = "weekday_tuesday"
+	WEEKDAY_WEDNESDAY            Key = "weekday_wednesday"
+	WEEKDAY_THURSDAY             Key = "weekday_thursday"
+	WEEKDAY_FRIDAY               Key = "weekday_friday"
+	WEEKDAY_SATURDAY             Key = "weekday_saturday"
+	WEEKDAY_SUNDAY               Key = "weekday_sunday"
+)
+
+// Reminders
+const (
+	REMINDER_TEXT Key = "reminder_text"
+)
+
+// Admin commands
+const (
+	REORDER_INVALID_SUBJECT_TEXT       Key = "reorder_invalid_subject_text"
+	REORDER_APPLY_ALL_TEXT             Key = "reorder_apply_all_text"
+	REORDER_CHOOSE_SUBJECT_TEXT        Key = "reorder_choose_subject_text"
+	REORDER_CHOOSE_SUBJECT_AGAIN_TEXT  Key = "reorder_choose_subject_again_text"
+	REORDER_CHOOSE_ORDER_TEXT          Key = "reorder_choose_order_text"
+	REORDER_CHOOSE_LESSON_TEXT         Key = "reorder_choose_lesson_text"
+	REORDER_CHOOSE_LESSON_AGAIN_TEXT   Key = "reorder_choose_lesson_again_text"
+	DELETE_REQUEST_CHOOSE_SUBJECT_TEXT Key = "delete_request_choose_subject_text"
+	DELETE_REQUEST_CHOOSE_LESSON_TEXT  Key = "delete_request_choose_lesson_text"
+	DELETE_REQUEST_INVALID_NUMBER_TEXT Key = "delete_request_invalid_number_text"
+	DELETE_REQUEST_NUMBER_RANGE_TEXT   Key = "delete_request_number_range_text"
+	DELETE_REQUEST_LIST_TEXT           Key = "delete_request_list_text"
+	DELETE_REQUEST_LINE                Key = "delete_request_line"
+	DELETE_USER_CHOOSE_TEXT            Key = "delete_user_choose_text"
+	DELETE_USER_ENTER_NUMBER_TEXT      Key = "delete_user_enter_number_text"
+	DELETE_USER_INVALID_NUMBER_TEXT    Key = "delete_user_invalid_number_text"
+	REORDER_INVALID_ORDER_TEXT         Key = "reorder_invalid_order_text"
+	REORDER_DONE_TEXT                  Key = "reorder_done_text"
+	DELETE_REQUEST_EMPTY_TEXT          Key = "delete_request_empty_text"
+	DELETE_REQUEST_DELETED_TEXT        Key = "delete_request_deleted_text"
+	NO_SUBJECTS_TEXT                   Key = "no_subjects_text"
+	NO_LESSONS_TEXT                    Key = "no_lessons_text"
+)
+
+// Settings and notifications about the turn in the queue
+const (
+	SETTINGS_TEXT                 Key = "settings_text"
+	SETTINGS_UNAVAILABLE_TEXT     Key = "settings_unavailable_text"
+	TURN_NOTIFICATIONS_ON_BUTTON  Key = "turn_notifications_on_button"
+	TURN_NOTIFICATIONS_OFF_BUTTON Key = "turn_notifications_off_button"
+	TURN_NOTIFICATIONS_ENABLED    Key = "turn_notifications_enabled"
+	TURN_NOTIFICATIONS_DISABLED   Key = "turn_notifications_disabled"
+	TURN_NEXT_TEXT                Key = "turn_next_text"
+	TURN_POSITION_TEXT            Key = "turn_position_text"
+)
+
+// Lesson mode
+const (
+	LESSON_MODE_CHOOSE_SUBJECT_TEXT Key = "lesson_mode_choose_subject_text"
+	LESSON_MODE_CHOOSE_LESSON_TEXT  Key = "lesson_mode_choose_lesson_text"
+	LESSON_MODE_TITLE               Key = "lesson_mode_title"
+	LESSON_MODE_LINE                Key = "lesson_mode_line"
+	LESSON_MODE_CURRENT_TEXT        Key = "lesson_mode_current_text"
+	LESSON_MODE_FINISHED_TEXT       Key = "lesson_mode_finished_text"
+	LESSON_MODE_NEXT_BUTTON         Key = "lesson_mode_next_button"
+	LESSON_MODE_SKIP_BUTTON         Key = "lesson_mode_skip_button"
+	LESSON_MODE_PASSED_BUTTON       Key = "lesson_mode_passed_button"
+	LESSON_MODE_FAILED_BUTTON       Key = "lesson_mode_failed_button"
+	LESSON_MODE_ABSENT_BUTTON       Key = "lesson_mode_absent_button"
+	LESSON_MODE_YOUR_TURN_TEXT      Key = "lesson_mode_your_turn_text"
+	LESSON_MODE_GRADE_BUTTON        Key = "lesson_mode_grade_button"
+	LESSON_MODE_ENTER_GRADE_TEXT    Key = "lesson_mode_enter_grade_text"
+	LESSON_MODE_INVALID_GRADE_TEXT  Key = "lesson_mode_invalid_grade_text"
+	LESSON_MODE_GRADE_SAVED_TEXT    Key = "lesson_mode_grade_saved_text"
+)
+
+// Results
+const (
+	RESULTS_TITLE      Key = "results_title"
+	RESULTS_EMPTY_TEXT Key = "results_empty_text"
+	RESULTS_SUBJECT    Key = "results_subject"
+	RESULTS_LINE       Key = "results_line"
+	RESULTS_GRADE      Key = "results_grade"
+	RESULTS_COMMENT    Key = "results_comment"
+	RESULTS_MARKED_BY  Key = "results_marked_by"
+	RESULTS_MARKED_AT  Key = "results_marked_at"
+	OUTCOME_PASSED     Key = "outcome_passed"
+	OUTCOME_FAILED     Key = "outcome_failed"
+	OUTCOME_ABSENT     Key = "outcome_absent"
+)
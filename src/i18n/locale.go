@@ -0,0 +1,57 @@
+package i18n
+
+import (
+	"context"
+	"strings"
+)
+
+type Locale string
+
+const (
+	RU Locale = "ru"
+	EN Locale = "en"
+	BE Locale = "be"
+
+	DEFAULT_LOCALE = RU
+	// Locale of users, which telegram language isn't supported, as they are most likely foreign students
+	FOREIGN_LOCALE = EN
+)
+
+var Locales = []Locale{RU, EN, BE}
+
+// Parse returns supported locale of the language code, e.g. "en" or "en-US"
+func Parse(code string) (Locale, bool) {
+	language, _, _ := strings.Cut(strings.ToLower(code), "-")
+	for _, locale := range Locales {
+		if string(locale) == language {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// FromLanguageCode picks locale for the language of the telegram client of the user, when user hasn't chosen one
+func FromLanguageCode(code string) Locale {
+	if code == "" {
+		return DEFAULT_LOCALE
+	}
+	if locale, ok := Parse(code); ok {
+		return locale
+	}
+	return FOREIGN_LOCALE
+}
+
+type localeKey struct{}
+
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext returns locale of the user, which update is being handled, or the default one
+func FromContext(ctx context.Context) Locale {
+	locale, ok := ctx.Value(localeKey{}).(Locale)
+	if !ok {
+		return DEFAULT_LOCALE
+	}
+	return locale
+}
@@ -0,0 +1,225 @@
+package i18n
+
+var ru = Catalog{
+	Name:   "Русский",
+	Plural: slavicPlural,
+	Messages: map[Key]string{
+		BACK_BUTTON:              "Назад",
+		ACCEPT_BUTTON:            "Принять",
+		DECLINE_BUTTON:           "Отклонить",
+		YES:                      "Да",
+		NO:                       "Нет",
+		NO_GROUP_TEXT:            "Вы пока не принадлежите ни к одной группе",
+		NO_LABWORKS_LEFT_TEXT:    "Больше не осталось лабораторных. Отдохните",
+		ENTER_FULL_NAME_TEXT:     "Введите ваши фамилию и имя (Пример формата: Иванов Иван)",
+		MESSAGE_TOO_LONG_TEXT:    "Ваше сообщение превосходит лимиты размера сообщений в телеграме. Пожалуйста, измените его и отправьте снова",
+		REQUEST_APPROVED_TEXT:    "Ваша заявка была одобрена",
+		REQUEST_DECLINED_TEXT:    "Ваша заявка была отклонена",
+		GOOGLE_ERROR_TEXT:        "Ошибка на стороне сервисов гугла. Пожалуйста, попробуйте позже",
+		GOOGLE_ERROR_ACCEPT_TEXT: "Ошибка на стороне гугл сервисов. Попробуйте одобрить заявку позже",
+		CANCELLED_TEXT:           "Текущее действие отменено",
+		CRASH_TEXT:               "Произошла непредвиденная ошибка, текущее действие было отменено. Попробуйте начать заново",
+		NOT_ADMIN_TEXT:           "Вы не являетесь админом для выполнения этой команды",
+
+		FLOW_EXPIRED_TEXT:      "Время ожидания ответа истекло, действие было отменено",
+		BUTTONS_EXPIRED_TEXT:   "Время действия кнопок истекло",
+		PAYLOAD_EXPIRED_TEXT:   "Кнопка устарела, начните действие заново",
+		ALREADY_PROCESSED_TEXT: "Уже обработано",
+		PRIVATE_ONLY_TEXT:      "Команда %s доступна только в личных сообщениях с ботом",
+		PRIVATE_CHAT_BUTTON:    "Перейти в личные сообщения",
+		FOREIGN_CALLBACK_TEXT:  "Эта кнопка предназначена другому пользователю",
+		JOIN_GROUP_BUTTON:      "Вступить в группу",
+		ASSIGN_BUTTON:          "Стать администратором группы",
+		SUBMIT_BUTTON:          "Отправить заявку на лабораторную",
+		ADD_LABWORK_BUTTON:     "Добавить собственную лабораторную",
+
+		HELP_DESCRIPTION:           "Команды и информация",
+		SUBMIT_DESCRIPTION:         "Запись на сдачу лабораторной",
+		ASSIGN_DESCRIPTION:         "Отправка заявки на роль администратора группы",
+		JOIN_GROUP_DESCRIPTION:     "Отправка заявки на участие в группе",
+		QUEUE_DESCRIPTION:          "Получение очереди своей группы",
+		REVERT_DESCRIPTION:         "Откат к предыдущему состоянию",
+		CANCEL_DESCRIPTION:         "Отмена текущего действия",
+		TABLE_DESCRIPTION:          "Получение ссылки на гугл-таблицу своей группы",
+		LANGUAGE_DESCRIPTION:       "Выбор языка бота",
+		ADD_LABWORK_DESCRIPTION:    "Добавление собственной пары",
+		DELETE_DESCRIPTION:         "Удаление участника из группы",
+		REORDER_DESCRIPTION:        "Изменение порядка очереди на предмет",
+		REMOVE_REQUEST_DESCRIPTION: "Удаление заявки из очереди",
+		CRASHES_DESCRIPTION:        "Последние отчёты о сбоях",
+		MY_REQUESTS_DESCRIPTION:    "Мои заявки",
+		LESSON_MODE_DESCRIPTION:    "Режим занятия: вызов студентов из очереди",
+		SETTINGS_DESCRIPTION:       "Настройки",
+		RESULTS_DESCRIPTION:        "Мои результаты по лабораторным",
+
+		START_TEXT: "Воспользуйтесь /help для получения списка команд. Для отправки заявок на лабораторные " +
+			"вы должны либо стать админом группы, с одобрения владельца бота, либо же членом группы, если у неё уже есть админ.",
+		NO_CRASHES_TEXT:  "Сбоев не зафиксировано",
+		CRASH_CHAT_LINE:  "Чат: %d, пользователь: %d, update: %d\n",
+		CRASH_STATE_LINE: "Состояние: %s\n",
+		CRASH_TEXT_LINE:  "Текст: %s\n",
+		CRASH_ERROR_LINE: "Ошибка: %s\n",
+
+		CHOOSE_LANGUAGE_TEXT:      "Выберите язык",
+		LANGUAGE_CHANGED_TEXT:     "Язык изменён на русский",
+		LANGUAGE_UNAVAILABLE_TEXT: "Язык можно будет выбрать после вступления в группу, пока используется язык вашего клиента телеграма",
+
+		CHOOSE_DISCIPLINE_AND_DATE_TEXT: "Выберите предмет и дату пары",
+		FINISH_REQUEST_FIRST_TEXT:       "Пожалуйста, закончите отправление заявки, прежде чем переходить к остальным командам",
+		ENTER_LABWORK_NUMBER_TEXT:       "Введите номер сдаваемой лабораторной работы",
+		INVALID_LABWORK_NUMBER_TEXT:     "Пожалуйста, введите корректный номер лабораторной (одно число, в разумных пределах)",
+		ENTER_LABWORK_PROOF_TEXT:        "Введите доказательство готовности лабораторной работы (один прикрепленный файл, возможно с текстовой подписью)",
+		LABWORK_REQUEST_SENT_TEXT:       "Ваша заявка была отправлена администраторам",
+		LABWORK_REQUEST_ACCEPTED_TEXT:   "Ваша заявка была принята",
+		LABWORK_REQUEST_WITHDRAWN_TEXT:  "Студент отозвал эту заявку",
+		LABWORK_REQUEST_TEMPLATE: "Отправил: {{.FullName}}\nПредмет: {{.DisciplineName}}\nНомер лабораторной: {{.LabworkNumber}}\n" +
+			"Дата: {{date .RequestedDate}}\nВремя отправки: {{dateTime .SentProofTime}}\n{{if .Notes}}Доп информация: {{.Notes}} {{end}}",
+
+		ALREADY_GROUP_MEMBER_TEXT: "Вы уже член группы",
+		ENTER_GROUP_NAME_TEXT:     "Введите номер группы, в которую хотите вступить",
+		GROUP_NOT_FOUND_TEXT:      "Данная группа не найдена",
+		GROUP_HAS_NO_ADMINS_TEXT:  "У данной группы пока нет администраторов. Попросите кого-либо из участников группы выступить в его роли",
+		INVALID_FULL_NAME_TEXT:    "Введите фамилию и имя как в предоставленном образце",
+		GROUP_REQUEST_SENT_TEXT:   "Ваша заявка была отправлена администраторам группы",
+		GROUP_JOIN_REQUEST_TEXT:   "Пользователь под id @%s и именем \"%s\" хочет присоединиться к группе",
+		REQUEST_PENDING_TEXT:      "Ваша заявка всё ещё рассматривается, подождите",
+
+		CHOOSE_DISCIPLINE_TEXT:             "Выберите предмет",
+		QUEUE_EMPTY_TEXT:                   "На эту лабораторную нет заявок. Как знать, может, вы будете первым",
+		INLINE_JOIN_GROUP_TEXT:             "Вступите в группу, чтобы видеть очереди",
+		INLINE_QUEUE_TITLE:                 "Очередь на %s\n",
+		INLINE_QUEUE_EMPTY:                 "На эту лабораторную нет заявок",
+		QUEUE_SUBSCRIBE_BUTTON:             "🔔 Следить",
+		QUEUE_SUBSCRIBE_PIN_BUTTON:         "📌 Следить и закрепить",
+		QUEUE_UNSUBSCRIBE_BUTTON:           "🔕 Не следить",
+		QUEUE_UPDATED_TEXT:                 "\nОбновлено в %s",
+		QUEUE_SUBSCRIBED_TEXT:              "Сообщение будет обновляться при изменении очереди",
+		QUEUE_UNSUBSCRIBED_TEXT:            "Сообщение больше не будет обновляться",
+		QUEUE_PIN_FAILED_TEXT:              "Не удалось закрепить сообщение, но оно будет обновляться. Дайте боту право закреплять сообщения",
+		MY_REQUESTS_TITLE:                  "Ваши заявки:\n",
+		MY_REQUEST_LINE:                    "%d. %s, %s — лабораторная %d, место в очереди: %d\n",
+		MY_REQUEST_PENDING_LINE:            "%d. %s, %s — лабораторная %d, ожидает подтверждения\n",
+		MY_REQUESTS_EMPTY_TEXT:             "У вас нет заявок на предстоящие занятия",
+		MY_REQUEST_WITHDRAW_BUTTON:         "Отозвать %d",
+		MY_REQUEST_CONFIRM_WITHDRAW_TEXT:   "Отозвать заявку?\n%s, %s — лабораторная %d",
+		MY_REQUEST_CONFIRM_WITHDRAW_BUTTON: "Да, отозвать",
+		MY_REQUEST_WITHDRAWN_TEXT:          "Заявка отозвана",
+		MY_REQUEST_NOT_FOUND_TEXT:          "Заявка уже не существует",
+		MY_REQUEST_EDIT_BUTTON:             "Изменить %d",
+		MY_REQUEST_EDIT_TEXT:               "%s, %s — лабораторная %d\nЧто изменить? Фото с доказательством останется прежним",
+		MY_REQUEST_CHANGE_NUMBER_BUTTON:    "Номер лабораторной",
+		MY_REQUEST_MOVE_BUTTON:             "Перенести на другое занятие",
+		MY_REQUEST_ENTER_NUMBER_TEXT:       "Введите новый номер лабораторной",
+		MY_REQUEST_CHOOSE_LESSON_TEXT:      "Выберите занятие, на которое перенести заявку",
+		MY_REQUEST_NO_OTHER_LESSONS_TEXT:   "У предмета нет других предстоящих занятий",
+		MY_REQUEST_UPDATED_TEXT:            "Заявка изменена",
+
+		ADMIN_REQUEST_TEMPLATE: "(ЗАЯВКА НА РОЛЬ АДМИНИСТРАТОРА)\nИмя: {{.Name}} \nГруппа: {{.Group}}\nИмя пользователя: @{{.TgName}} \n" +
+			"{{if .AdditionalInfo}}Доп информация: {{.AdditionalInfo}} {{end}}",
+		ALREADY_ADMIN_TEXT:          "Вы уже админ группы",
+		ENTER_IIS_GROUP_TEXT:        "Введите ваш номер группы, указанный в ИИСе",
+		INVALID_IIS_GROUP_TEXT:      "Введите номер существующей в ИИСе группы",
+		ENTER_ADMIN_PROOF_TEXT:      "Предоставьте доказательство вверенных группой полномочий (в виде фото, с дополнительной текстовой информацией по усмотрению)",
+		SEND_PHOTO_TEXT:             "Отправьте фото как часть сообщения",
+		ADMIN_REQUEST_PENDING_TEXT:  "Подождите, ваш запрос на роль администратора ещё обрабатывается",
+		ADMIN_REQUEST_APPROVED_TEXT: "Ваша заявка была одобрена. Ссылка на гугл-таблицу: %s",
+
+		ENTER_LESSON_NAME_TEXT:       "Введите название добавленной пары",
+		SEND_TEXT_ONLY_TEXT:          "Отправьте текстовое сообщение, без прикрепленных файлов",
+		CHOOSE_LESSON_DATE_TEXT:      "Выберите дату добавленной пары",
+		CHOOSE_LESSON_DATE_TIME_TEXT: "Выберите дату и время пары",
+		LESSON_SAVED_TEXT:            "Ваша лабораторная была сохранена",
+		LESSON_EXISTS_TEXT:           "Пара под данным именем и датой уже существует. Пожалуйста, укажите другое имя",
+		LESSON_NOT_CREATED_TEXT:      "Пара под данным именем и датой не создалась. Пожалуйста, проверьте, что имя валидно для названия в гугл таблицах",
+		MONTH_JANUARY:                "Январь",
+		MONTH_FEBRUARY:               "Февраль",
+		MONTH_MARCH:                  "Март",
+		MONTH_APRIL:                  "Апрель",
+		MONTH_MAY:                    "Май",
+		MONTH_JUNE:                   "Июнь",
+		MONTH_JULY:                   "Июль",
+		MONTH_AUGUST:                 "Август",
+		MONTH_SEPTEMBER:              "Сентябрь",
+		MONTH_OCTOBER:                "Октябрь",
+		MONTH_NOVEMBER:               "Ноябрь",
+		MONTH_DECEMBER:               "Декабрь",
+		WEEKDAY_MONDAY:               "Пн",
+		WEEKDAY_TUESDAY:              "Вт",
+		WEEKDAY_WEDNESDAY:            "Ср",
+		WEEKDAY_THURSDAY:             "Чт",
+		WEEKDAY_FRIDAY:               "Пт",
+		WEEKDAY_SATURDAY:             "Сб",
+		WEEKDAY_SUNDAY:               "Вс",
+
+		REMINDER_TEXT: "Вы сдавали данную лабораторную? (%s %s, номер лабораторной %d)",
+
+		REORDER_INVALID_SUBJECT_TEXT:      "Выберите корректное название предмета",
+		REORDER_APPLY_ALL_TEXT:            "Хотите ли вы применить данные правила ко всем предметам? Введите \"Да\"/любую другую последовательность символов",
+		REORDER_CHOOSE_SUBJECT_TEXT:       "Выберите предмет для изменения порядка очереди",
+		REORDER_CHOOSE_SUBJECT_AGAIN_TEXT: "Пожалуйста, выберите предмет для изменения порядка очередности",
+		REORDER_CHOOSE_ORDER_TEXT: "Выберите способы сортировки данных, через запятую (порядок важен, сортировка будет применена в указанном порядке).\n" +
+			"1 - по времени отправки. 2 - по номеру лабораторной. Добавьте префикс + к номеру, если хотите установить сортировку по убыванию",
+		REORDER_CHOOSE_LESSON_TEXT:         "Выберите занятие для изменения сортировки",
+		REORDER_CHOOSE_LESSON_AGAIN_TEXT:   "Пожалуйста, выберите занятие для изменения сортировки",
+		DELETE_REQUEST_CHOOSE_SUBJECT_TEXT: "Выберите предмет для удаления",
+		DELETE_REQUEST_CHOOSE_LESSON_TEXT:  "Выберите пару для удаления заявки",
+		DELETE_REQUEST_INVALID_NUMBER_TEXT: "Введите корректный номер заявки",
+		DELETE_REQUEST_NUMBER_RANGE_TEXT:   "Пожалуйста, введите валидное число в пределах от 1 до %d",
+		DELETE_REQUEST_LIST_TEXT:           "Введите число, представляющее заявку, для удаления\n",
+		DELETE_REQUEST_LINE:                "%d. %s, лабораторная: %d\n",
+		DELETE_USER_CHOOSE_TEXT:            "Выберите студента для удаления из списка:\n",
+		DELETE_USER_ENTER_NUMBER_TEXT:      "Введите конкретную цифру с участником группы",
+		DELETE_USER_INVALID_NUMBER_TEXT:    "Введите корректный номер с участником группы",
+		REORDER_INVALID_ORDER_TEXT:         "Введите номера способов сортировки через запятую, например: 1,+2",
+		REORDER_DONE_TEXT:                  "Порядок очереди изменён",
+		DELETE_REQUEST_EMPTY_TEXT:          "На это занятие нет заявок",
+		DELETE_REQUEST_DELETED_TEXT:        "Заявка удалена",
+		NO_SUBJECTS_TEXT:                   "В расписании группы нет предметов",
+		NO_LESSONS_TEXT:                    "У предмета нет предстоящих занятий",
+
+		SETTINGS_TEXT:                 "Настройки\n\nУведомления об очереди: %s",
+		SETTINGS_UNAVAILABLE_TEXT:     "Настройки будут доступны после вступления в группу",
+		TURN_NOTIFICATIONS_ON_BUTTON:  "Включить уведомления об очереди",
+		TURN_NOTIFICATIONS_OFF_BUTTON: "Отключить уведомления об очереди",
+		TURN_NOTIFICATIONS_ENABLED:    "включены",
+		TURN_NOTIFICATIONS_DISABLED:   "отключены",
+		TURN_NEXT_TEXT:                "Вы следующий в очереди на лабораторную %d по предмету %s\n\nОтключить уведомления можно в /settings",
+		TURN_POSITION_TEXT:            "Ваше место в очереди на лабораторную %d по предмету %s: %d\n\nОтключить уведомления можно в /settings",
+
+		LESSON_MODE_CHOOSE_SUBJECT_TEXT: "Выберите предмет для проведения занятия",
+		LESSON_MODE_CHOOSE_LESSON_TEXT:  "Выберите занятие",
+		LESSON_MODE_TITLE:               "%s, %s\n\n",
+		LESSON_MODE_LINE:                "%s %d. %s — лабораторная %d\n",
+		LESSON_MODE_CURRENT_TEXT:        "\nСдаёт: %s",
+		LESSON_MODE_FINISHED_TEXT:       "\nВсе студенты из очереди вызваны",
+		LESSON_MODE_NEXT_BUTTON:         "Следующий",
+		LESSON_MODE_SKIP_BUTTON:         "Пропустить",
+		LESSON_MODE_PASSED_BUTTON:       "Сдал",
+		LESSON_MODE_FAILED_BUTTON:       "Не сдал",
+		LESSON_MODE_ABSENT_BUTTON:       "Отсутствует",
+		LESSON_MODE_YOUR_TURN_TEXT:      "Ваша очередь сдавать лабораторную %d по предмету %s",
+		LESSON_MODE_GRADE_BUTTON:        "Оценка и комментарий: %s",
+		LESSON_MODE_ENTER_GRADE_TEXT:    "Введите оценку от 1 до 10 и комментарий для %s, лабораторная %d, например: 8 Хорошо\n\nОценку или комментарий можно не указывать",
+		LESSON_MODE_INVALID_GRADE_TEXT:  "Оценка должна быть числом от 1 до 10",
+		LESSON_MODE_GRADE_SAVED_TEXT:    "Оценка сохранена",
+
+		RESULTS_TITLE:      "Ваши результаты по лабораторным\n",
+		RESULTS_EMPTY_TEXT: "У вас пока нет результатов по лабораторным",
+		RESULTS_SUBJECT:    "\n%s\n",
+		RESULTS_LINE:       "Лабораторная %d: %s",
+		RESULTS_GRADE:      ", оценка %d",
+		RESULTS_COMMENT:    " — %s",
+		RESULTS_MARKED_BY:  " (%s, %s)\n",
+		RESULTS_MARKED_AT:  " (%s)\n",
+		OUTCOME_PASSED:     "сдана",
+		OUTCOME_FAILED:     "не сдана",
+		OUTCOME_ABSENT:     "отсутствие",
+	},
+	Plurals: map[Key]Forms{
+		QUEUE_SIZE: {
+			One:  "%d заявка",
+			Few:  "%d заявки",
+			Many: "%d заявок",
+		},
+	},
+}
@@ -2,7 +2,9 @@ package ioc
 
 import (
 	"fmt"
-	
+	"os"
+	"strconv"
+
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/logging"
 )
 
@@ -31,7 +33,28 @@ func provider[T any](factory func() T) func() T {
 	}
 }
 
+// stubbableProvider also returns the function, which replaces value of the provider until Reset.
+// It is used by tests for dependencies, talking to external apis
+func stubbableProvider[T any](factory func() T) (func() T, func(T)) {
+	get := provider(factory)
+	providerId := currentId
+	return get, func(value T) {
+		container[providerId] = value
+	}
+}
+
 func Reset() {
 	container = map[int]any{}
 	isPending = map[int]bool{}
-}
\ No newline at end of file
+}
+func getIntEnv(key string, defaultValue int) (int, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %s of %s env variable: %w", value, key, err)
+	}
+	return parsed, nil
+}
@@ -1,42 +1,126 @@
 package ioc
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/cron"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/logging"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/bot"
+	stateMachine "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/outcomes"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/queue"
 	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-var useTgBot = provider(
+var useTgBot, StubTgBot = stubbableProvider(
 	func() *tgutils.Bot {
 		bot_token := os.Getenv("BOT_TOKEN")
 		debug := os.Getenv("DEBUG")
-		bot, err := tgbotapi.NewBotAPI(bot_token)
+		maxParts, err := getIntEnv("MAX_MESSAGE_PARTS", tgutils.DEFAULT_MAX_MESSAGE_PARTS)
+		if err != nil {
+			logging.FatalLog(err.Error())
+		}
+		opts := []func(*tgutils.Bot){tgutils.WithMarkupsStore(useFlowMarkupsRepository()), tgutils.WithLanguagesStore(useUsersRepository()),
+			tgutils.WithRateLimits(tgutils.DefaultRateLimits()), tgutils.WithMaxMessageParts(maxParts)}
+
+		apiEndpoint := tgbotapi.APIEndpoint
+		if apiUrl := os.Getenv("BOT_API_URL"); apiUrl != "" {
+			apiEndpoint = tgutils.APIEndpoint(apiUrl)
+			opts = append(opts, tgutils.WithFileEndpoint(tgutils.FileEndpoint(apiUrl)))
+		}
+		bot, err := tgbotapi.NewBotAPIWithAPIEndpoint(bot_token, apiEndpoint)
 		if err != nil {
 			logging.FatalLog(err.Error())
 		}
 		if strings.EqualFold(debug, "true") {
 			bot.Debug = true
 		}
-		return tgutils.NewBot(bot)
+		return tgutils.NewBot(bot, opts...)
 	},
 )
 
 var UseBotController = provider(
 	func() *bot.BotController {
-		bot, err := bot.NewBotController(useTgBot(), UseMessageService(), UseCallbacksService())
+		bot, err := bot.NewBotController(useTgBot(), UseMessageService(), UseCallbacksService(), UseInlineQueriesService(),
+			useDispatcher(), useUpdatesSource(), useProcessedUpdatesRepository())
 		if err != nil {
 			logging.FatalLog(err.Error())
 		}
 		RegisterRoutes(useMux())
+		err = useMux().Validate()
+		if err != nil {
+			logging.FatalLog(err.Error())
+		}
+		// Bot works without commands menu, so it is set in the background and failing to set it isn't fatal
+		menu := useCommandsMenu()
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), stateMachine.SET_DEFAULTS_TIMEOUT)
+			defer cancel()
+			err := menu.SetDefaults(ctx)
+			if err != nil {
+				logging.Error(err.Error())
+			}
+		}()
 		return bot
 	},
 )
 
+// ValidateRoutes validates flows once more, so that states, which handlers have saved since the start, are checked too
+func ValidateRoutes() error {
+	return useMux().Validate()
+}
+
+var useDispatcher = provider(
+	func() *bot.Dispatcher {
+		workers, err := getIntEnv("DISPATCHER_WORKERS", bot.DEFAULT_DISPATCHER_WORKERS)
+		if err != nil {
+			logging.FatalLog(err.Error())
+		}
+		queueSize, err := getIntEnv("DISPATCHER_QUEUE_SIZE", bot.DEFAULT_DISPATCHER_QUEUE_SIZE)
+		if err != nil {
+			logging.FatalLog(err.Error())
+		}
+		return bot.NewDispatcher(workers, queueSize)
+	},
+)
+
+var useUpdatesSource = provider(
+	func() bot.UpdatesSource {
+		mode := os.Getenv("BOT_MODE")
+		switch mode {
+		case "", bot.POLLING_MODE:
+			return bot.NewPollingSource(useTgBot(), useProcessedUpdatesRepository())
+		case bot.WEBHOOK_MODE:
+			port, err := getIntEnv("WEBHOOK_PORT", 8443)
+			if err != nil {
+				logging.FatalLog(err.Error())
+			}
+			config := bot.WebhookConfig{
+				Url:    os.Getenv("WEBHOOK_URL"),
+				Path:   os.Getenv("WEBHOOK_PATH"),
+				Port:   port,
+				Secret: os.Getenv("WEBHOOK_SECRET"),
+			}
+			if config.Path == "" {
+				config.Path = "/"
+			}
+			if config.Url == "" || config.Secret == "" {
+				logging.FatalLog("WEBHOOK_URL and WEBHOOK_SECRET are required in webhook mode")
+			}
+			return bot.NewWebhookSource(useTgBot(), config)
+		default:
+			logging.FatalLog(fmt.Sprintf("unknown bot mode %s, expected %s or %s", mode, bot.POLLING_MODE, bot.WEBHOOK_MODE))
+		}
+		return nil
+	},
+)
+
 var useMux = provider(
 	func() *tgutils.Mux {
 		mux := tgutils.NewMux(useHandlersCache(), useTgBot())
@@ -44,10 +128,48 @@ var useMux = provider(
 	},
 )
 
+var useCallbackPayloads = provider(
+	func() *tgutils.CallbackPayloads {
+		return tgutils.NewCallbackPayloads(useCallbackPayloadsRepository(), constants.CALLBACK_PAYLOAD_TTL)
+	},
+)
+
+var useLiveQueues = provider(
+	func() *queue.LiveQueues {
+		interval, err := getIntEnv("QUEUE_REFRESH_INTERVAL", int(queue.DEFAULT_REFRESH_INTERVAL/time.Second))
+		if err != nil {
+			logging.FatalLog(err.Error())
+		}
+		return queue.NewLiveQueues(useTgBot(), useQueueSubscriptionsRepository(), useLessonsRequestsRepository(), useLessonsRepository(),
+			time.Duration(interval)*time.Second)
+	},
+)
+
+var useTurnNotifier = provider(
+	func() *queue.TurnNotifier {
+		count, err := getIntEnv("TURN_NOTIFICATIONS_COUNT", queue.DEFAULT_TURN_NOTIFICATIONS)
+		if err != nil {
+			logging.FatalLog(err.Error())
+		}
+		return queue.NewTurnNotifier(useTgBot(), useLessonsRequestsRepository(), useLessonsRepository(), useUsersRepository(), count)
+	},
+)
+
+var useOutcomesRecorder = provider(
+	func() *outcomes.OutcomesRecorder {
+		interval, err := getIntEnv("OUTCOMES_REFRESH_INTERVAL", int(outcomes.DEFAULT_REFRESH_INTERVAL/time.Second))
+		if err != nil {
+			logging.FatalLog(err.Error())
+		}
+		return outcomes.NewOutcomesRecorder(useLabworkOutcomesRepository(), useGroupsRepository(), UseSheetsApiService(),
+			time.Duration(interval)*time.Second)
+	},
+)
+
 var UseTasksController = provider(
 	func() *cron.TasksController {
 		return cron.NewTasksController(UseSheetsApiService(), useLessonsRepository(),
 			useLessonsRequestsRepository(), useUsersRepository(),
-			UseDriveApiService(), useTasksRepository(), useTgBot())
+			UseDriveApiService(), useTasksRepository(), useTgBot(), useMux(), useCallbackPayloads())
 	},
 )
@@ -2,19 +2,29 @@ package ioc
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
+	requestdelete "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/admin/delete_request"
+	lessonmode "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/admin/lesson_mode"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/admin/reorder"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
 	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-var useAdminMiddleware = func(next tgutils.MuxHandler) func() tgutils.MuxHandler {
-	bot := useTgBot()
-	users := useUsersRepository()
-	return provider(
-		func() tgutils.MuxHandler {
+var useAdminMiddleware = provider(
+	func() tgutils.Middleware {
+		bot := useTgBot()
+		users := useUsersRepository()
+		cache := useHandlersCache()
+		return func(next tgutils.MuxHandler) tgutils.MuxHandler {
 			return tgutils.NewHandlerFunc(
 				func(ctx context.Context, message *tgbotapi.Message) error {
 					user, err := users.GetByTgId(ctx, message.From.ID)
@@ -22,13 +32,112 @@ var useAdminMiddleware = func(next tgutils.MuxHandler) func() tgutils.MuxHandler
 						return fmt.Errorf("failed to get user by id during admin middleware: %w", err)
 					}
 					if !slices.Contains(user.Roles, entities.Admin) {
-						_, err := bot.SendCtx(ctx, tgbotapi.NewMessage(message.From.ID, "Вы не являетесь админом для выполнения этой команды"))
+						err := cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, constants.IDLE_STATE))
+						if err != nil {
+							return fmt.Errorf("failed to reset state during admin middleware handling: %w", err)
+						}
+						_, err = bot.SendCtx(ctx, tgbotapi.NewMessage(message.From.ID, i18n.T(ctx, i18n.NOT_ADMIN_TEXT)))
 						if err != nil {
 							return fmt.Errorf("failed to send not admin message during admin middleware handling: %w", err)
 						}
+						return nil
 					}
 					return next.Handle(ctx, message)
 				}, next.Revert)
-		},
-	)
-}
\ No newline at end of file
+		}
+	},
+)
+
+// useAdminCallbackMiddleware is the same as useAdminMiddleware, but for callbacks of admin flows.
+// Buttons stay in the chat, so they can be pressed after the user has lost admin role or moved to another group
+var useAdminCallbackMiddleware = provider(
+	func() tgutils.CallbackMiddleware {
+		users := useUsersRepository()
+		groups := useAdminCallbackGroups()
+		return func(next tgutils.CallbackHandler) tgutils.CallbackHandler {
+			return tgutils.CallbackHandlerFunc(func(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) error {
+				user, err := users.GetByTgId(ctx, update.SentFrom().ID)
+				if err != nil {
+					return fmt.Errorf("failed to get user by id during admin callback middleware: %w", err)
+				}
+				if user == nil || !slices.Contains(user.Roles, entities.Admin) {
+					return answerNotAdmin(ctx, update, bot)
+				}
+				for prefix, groupOf := range groups {
+					if !strings.HasPrefix(update.CallbackData(), prefix) {
+						continue
+					}
+					groupId, err := groupOf(ctx, update.CallbackData())
+					if err != nil {
+						return fmt.Errorf("failed to get group of the callback during admin callback middleware: %w", err)
+					}
+					if groupId != 0 && groupId != user.GroupId {
+						return answerNotAdmin(ctx, update, bot)
+					}
+				}
+				return next.HandleCallback(ctx, update, bot)
+			})
+		}
+	},
+)
+
+func answerNotAdmin(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) error {
+	_, err := bot.RequestCtx(ctx, tgbotapi.NewCallbackWithAlert(update.CallbackQuery.ID, i18n.T(ctx, i18n.NOT_ADMIN_TEXT)))
+	if err != nil {
+		return fmt.Errorf("failed to answer callback of not admin during admin callback middleware: %w", err)
+	}
+	return nil
+}
+
+// callbackGroupFunc returns the group, which callback of admin flow refers to, or zero if there is no such group anymore
+type callbackGroupFunc func(ctx context.Context, data string) (int64, error)
+
+// useAdminCallbackGroups resolves groups of admin flow callbacks, which carry ids of lessons or outcomes, so that admin of
+// one group can't reach another one with them. Other callbacks carry names of subjects, which are looked up in the group of the admin
+var useAdminCallbackGroups = provider(
+	func() map[string]callbackGroupFunc {
+		lessons := useLessonsRepository()
+		outcomes := useLabworkOutcomesRepository()
+		lessonGroup := func(lessonOf func(data string) (int64, error)) callbackGroupFunc {
+			return func(ctx context.Context, data string) (int64, error) {
+				lessonId, err := lessonOf(data)
+				if err != nil {
+					return 0, err
+				}
+				lesson, err := lessons.Get(ctx, lessonId)
+				if errors.Is(err, sql.ErrNoRows) {
+					return 0, nil
+				}
+				if err != nil {
+					return 0, fmt.Errorf("failed to get lesson %d of the callback: %w", lessonId, err)
+				}
+				return lesson.GroupId, nil
+			}
+		}
+		return map[string]callbackGroupFunc{
+			constants.DELETE_REQUEST_TIME_CALLBACK:     lessonGroup(requestdelete.ParseTimeCallbackData),
+			constants.REORDER_LESSON_CONCRETE_CALLBACK: lessonGroup(reorder.ParseLessonConcreteCallback),
+			constants.LESSON_MODE_PANEL_CALLBACKS: lessonGroup(func(data string) (int64, error) {
+				panel, err := lessonmode.PanelCodec.Decode(data)
+				if err != nil {
+					return 0, err
+				}
+				return panel.LessonId, nil
+			}),
+			constants.LESSON_MODE_GRADE_CALLBACKS: func(ctx context.Context, data string) (int64, error) {
+				grade, err := lessonmode.GradeCodec.Decode(data)
+				if err != nil {
+					return 0, err
+				}
+				outcome, err := outcomes.Get(ctx, grade.OutcomeId)
+				if err != nil {
+					return 0, err
+				}
+				if outcome == nil {
+					return 0, nil
+				}
+				return outcome.GroupId, nil
+			},
+		}
+	},
+)
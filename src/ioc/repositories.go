@@ -12,9 +12,9 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 )
 
-var useSqliteConnection = provider(
+var useSqliteConnection, StubSqliteConnection = stubbableProvider(
 	func() *sql.DB {
-		conn, err := sql.Open("sqlite3", os.Getenv("SQLITE_FILE"))
+		conn, err := sql.Open("sqlite3", sqlite.Dsn(os.Getenv("SQLITE_FILE")))
 		if err != nil {
 			logging.FatalLog(err.Error())
 		}
@@ -98,3 +98,39 @@ var useTasksRepository = provider(
 		return sqlite.NewTasksRepository(useSqliteConnection())
 	},
 )
+
+var useCrashesRepository = provider(
+	func() *sqlite.CrashesRepository {
+		return sqlite.NewCrashesRepository(useSqliteConnection())
+	},
+)
+
+var useProcessedUpdatesRepository = provider(
+	func() *sqlite.ProcessedUpdatesRepository {
+		return sqlite.NewProcessedUpdatesRepository(useSqliteConnection())
+	},
+)
+
+var useFlowMarkupsRepository = provider(
+	func() *sqlite.FlowMarkupsRepository {
+		return sqlite.NewFlowMarkupsRepository(useSqliteConnection())
+	},
+)
+
+var useQueueSubscriptionsRepository = provider(
+	func() *sqlite.QueueSubscriptionsRepository {
+		return sqlite.NewQueueSubscriptionsRepository(useSqliteConnection())
+	},
+)
+
+var useLabworkOutcomesRepository = provider(
+	func() *sqlite.LabworkOutcomesRepository {
+		return sqlite.NewLabworkOutcomesRepository(useSqliteConnection())
+	},
+)
+
+var useCallbackPayloadsRepository = provider(
+	func() *sqlite.CallbackPayloadsRepository {
+		return sqlite.NewCallbackPayloadsRepository(useSqliteConnection())
+	},
+)
@@ -2,92 +2,201 @@ package ioc
 
 import (
 	"context"
+	"maps"
+	"slices"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/cron"
 	stateMachine "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers"
+	requestdelete "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/admin/delete_request"
 	delete "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/admin/delete_user"
+	lessonmode "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/admin/lesson_mode"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/admin/reorder"
 	admin "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/admin_submit"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
 	customlabworks "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/custom_labworks"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/group"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/labworks"
+	myrequests "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/my_requests"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/outcomes"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/queue"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/settings"
 	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 func RegisterRoutes(mux *tgutils.Mux) {
 	mux.NotFoundHandler = useIdleState()
+	mux.CancelHandler = useCancelState()
 
-	adminMux := tgutils.NewMux(useHandlersCache(), useTgBot())
-	mux.RegisterRoute(constants.ADMIN_STATES, useAdminMiddleware(adminMux)())
-	RegisterDeleteRoutes(adminMux)
-
-	mux.RegisterRoute(constants.IDLE_STATE, (useIdleState()))
-	RegisterAdminSubmitRoutes(mux)
-	RegisterLabworkRoutes(mux)
-	RegisterLabworkAddRoutes(mux)
-	RegisterGroupRoutes(mux)
-	RegisterQueueRoutes(mux)
-	RegisterCronCalbacks(mux)
+	mux.Use(tgutils.RecoverMiddleware(), tgutils.LoggingMiddleware(), tgutils.GroupChatMiddleware(useTgBot(), constants.GROUP_CHAT_COMMANDS...))
+	mux.UseCallback(tgutils.RecoverCallbackMiddleware(), tgutils.LoggingCallbackMiddleware(), tgutils.ExpiredPayloadMiddleware())
+	mux.UseFor(constants.ADMIN_STATES, useAdminMiddleware())
+	mux.UseCallbackFor(constants.ADMIN_FLOW_CALLBACKS, useAdminCallbackMiddleware())
+	mux.UseCallbackFor(cron.REMINDER_CALLBACKS, cron.ReminderOwnerMiddleware(useLessonsRequestsRepository()))
+	onceByKey := map[string]tgutils.CallbackKeyFunc{
+		constants.LABWORK_ACCEPT_CALLBACKS: labworks.RequestCallbackKey,
+		constants.LABWORK_DECLINE_CALLBACK: labworks.RequestCallbackKey,
+		constants.GROUP_ACCEPT_CALLBACKS:   group.RequestCallbackKey(useRequestsRepository()),
+		constants.GROUP_DECLINE_CALLBACKS:  group.RequestCallbackKey(useRequestsRepository()),
+		constants.ADMIN_ACCEPT_CALLBACKS:   admin.RequestCallbackKey(useAdminRequestsRepository()),
+		constants.ADMIN_DECLINE_CALLBACKS:  admin.RequestCallbackKey(useAdminRequestsRepository()),
+		cron.REMINDER_CALLBACKS:            tgutils.CallbackMessageKey,
+	}
+	for prefix, keyOf := range onceByKey {
+		mux.UseCallbackFor(prefix, tgutils.OnceCallbackMiddleware(useProcessedUpdatesRepository(), keyOf))
+	}
+
+	RegisterTimeouts(mux)
+
+	mux.RegisterFlow(idleFlow())
+	mux.RegisterFlow(deleteFlow())
+	mux.RegisterFlow(deleteRequestFlow())
+	mux.RegisterFlow(reorderFlow())
+	mux.RegisterFlow(lessonModeFlow())
+	mux.RegisterFlow(adminSubmitFlow())
+	mux.RegisterFlow(labworkSubmitFlow())
+	mux.RegisterFlow(labworkAddFlow())
+	mux.RegisterFlow(groupFlow())
+	mux.RegisterFlow(queueFlow())
+	mux.RegisterFlow(myRequestsFlow())
+	mux.RegisterFlow(settingsFlow())
+	mux.RegisterFlow(resultsFlow())
+}
+
+func RegisterTimeouts(mux *tgutils.Mux) {
+	for _, prefix := range []constants.State{constants.LABWORK_SUBMIT_STATES, constants.LABWORK_ADD_STATES, constants.QUEUE_STATES,
+		constants.GROUP_STATES, constants.ADMIN_SUBMIT_STATES, constants.ADMIN_STATES, constants.MY_REQUESTS_STATES} {
+		mux.RegisterTimeout(prefix, constants.FLOW_TIMEOUT)
+	}
+	// Requests, waiting for approval, can be accepted by admins or owners much later
+	mux.RegisterTimeout(constants.GROUP_WAITING_STATE, 0)
+	mux.RegisterTimeout(constants.ADMIN_WAITING_STATE, 0)
+}
+
+func idleFlow() *tgutils.Flow {
+	return tgutils.NewFlow(constants.IDLE_STATE).
+		State(constants.IDLE_STATE, useIdleState(), slices.Sorted(maps.Values(constants.COMMAND_STATES))...).
+		Callback(stateMachine.LanguageCodec.Prefix(), tgutils.TypedCallback(stateMachine.LanguageCodec, useLanguageCallbackHandler().HandleCallback)).
+		Callback(cron.ReminderCallbackCodec.Prefix(), tgutils.TypedCallback(cron.ReminderCallbackCodec, useReminderCallbackHandler().HandleCallback))
 }
 
-func RegisterLabworkRoutes(mux *tgutils.Mux) {
-	mux.RegisterRoute(constants.LABWORK_SUBMIT_START_STATE, useLabworkSubmitStartState())
-	mux.RegisterRoute(constants.LABWORK_SUBMIT_NUMBER_STATE, useLabworkSubmitNumberState())
-	mux.RegisterRoute(constants.LABWORK_SUBMIT_PROOF_STATE, useLabworkSubmitProofState())
-	mux.RegisterRoute(constants.LABWORK_SUBMIT_WAITING_STATE, useLabworkSubmitWaitingState())
+func labworkSubmitFlow() *tgutils.Flow {
+	return tgutils.NewFlow(constants.LABWORK_SUBMIT_STATES).
+		State(constants.LABWORK_SUBMIT_START_STATE, useLabworkSubmitStartState(), constants.LABWORK_SUBMIT_WAITING_STATE, constants.IDLE_STATE).
+		State(constants.LABWORK_SUBMIT_WAITING_STATE, useLabworkSubmitWaitingState(), constants.IDLE_STATE).
+		State(constants.LABWORK_SUBMIT_NUMBER_STATE, useLabworkSubmitNumberState(), constants.LABWORK_SUBMIT_PROOF_STATE,
+			constants.LABWORK_SUBMIT_START_STATE).
+		State(constants.LABWORK_SUBMIT_PROOF_STATE, useLabworkSubmitProofState(), constants.IDLE_STATE, constants.LABWORK_SUBMIT_NUMBER_STATE).
+		Callback(constants.LABWORK_CALLBACKS, useLabworkSubmitCallbackHandler(), constants.LABWORK_SUBMIT_NUMBER_STATE, constants.IDLE_STATE)
+}
 
-	mux.RegisterCallback(constants.LABWORK_CALLBACKS, useLabworkSubmitCallbackHandler())
+func labworkAddFlow() *tgutils.Flow {
+	return tgutils.NewFlow(constants.LABWORK_ADD_STATES).
+		State(constants.LABWORK_ADD_START_STATE, useLabworkAddStartState(), constants.LABWORK_ADD_SUBMIT_NAME_STATE, constants.IDLE_STATE).
+		State(constants.LABWORK_ADD_SUBMIT_NAME_STATE, useLabworkAddSubmitNameState(), constants.LABWORK_ADD_WAITING_STATE, constants.IDLE_STATE).
+		State(constants.LABWORK_ADD_WAITING_STATE, useLabworkAddWaitingState(), constants.LABWORK_ADD_START_STATE).
+		Callback(constants.CALENDAR_CALLBACKS, useCalendarCallbackHandler()).
+		Callback(constants.TIME_PICKER_CALLBACKS, useTimePickerCallbackHandler(), constants.LABWORK_ADD_SUBMIT_NAME_STATE, constants.IDLE_STATE).
+		Callback(constants.IGNORE_CALLBACKS, tgutils.CallbackHandlerFunc(func(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) error { return nil }))
 }
 
-func RegisterLabworkAddRoutes(mux *tgutils.Mux) {
-	mux.RegisterRoute(constants.LABWORK_ADD_START_STATE, useLabworkAddStartState())
-	mux.RegisterRoute(constants.LABWORK_ADD_SUBMIT_NAME_STATE, useLabworkAddSubmitNameState())
-	mux.RegisterRoute(constants.LABWORK_ADD_WAITING_STATE, useLabworkAddWaitingState())
+func groupFlow() *tgutils.Flow {
+	return tgutils.NewFlow(constants.GROUP_STATES).
+		State(constants.GROUP_SUBMIT_START_STATE, useGroupSubmitStartState(), constants.GROUP_SUBMIT_GROUPNAME_STATE, constants.IDLE_STATE).
+		State(constants.GROUP_SUBMIT_GROUPNAME_STATE, useGroupSubmitGroupNameState(), constants.GROUP_SUBMIT_NAME_STATE, constants.IDLE_STATE).
+		State(constants.GROUP_SUBMIT_NAME_STATE, useGroupSubmitNameState(), constants.GROUP_WAITING_STATE, constants.GROUP_SUBMIT_START_STATE).
+		State(constants.GROUP_WAITING_STATE, useGroupSubmitWaitingState()).
+		Callback(constants.GROUP_CALLBACKS, useGroupCallbackHandler(), constants.IDLE_STATE)
+}
 
-	mux.RegisterCallback(constants.CALENDAR_CALLBACKS, useCalendarCallbackHandler())
-	mux.RegisterCallback(constants.TIME_PICKER_CALLBACKS, useTimePickerCallbackHandler())
-	mux.RegisterCallback(constants.IGNORE_CALLBACKS, tgutils.CallbackHandlerFunc(func(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) error { return nil }))
+func queueFlow() *tgutils.Flow {
+	return tgutils.NewFlow(constants.QUEUE_STATES).
+		State(constants.QUEUE_START_STATE, useQueueStartState(), constants.QUEUE_WAITING_STATE, constants.IDLE_STATE).
+		State(constants.QUEUE_WAITING_STATE, useQueueWaitingState(), constants.IDLE_STATE).
+		Callback(constants.QUEUE_CALLBACKS, useQueueCallbackHandler(), constants.IDLE_STATE)
 }
 
-func RegisterGroupRoutes(mux *tgutils.Mux) {
-	mux.RegisterRoute(constants.GROUP_SUBMIT_START_STATE, useGroupSubmitStartState())
-	mux.RegisterRoute(constants.GROUP_WAITING_STATE, useGroupSubmitWaitingState())
-	mux.RegisterRoute(constants.GROUP_SUBMIT_NAME_STATE, useGroupSubmitNameState())
-	mux.RegisterRoute(constants.GROUP_SUBMIT_GROUPNAME_STATE, useGroupSubmitGroupNameState())
+// Buttons of the list are pressed after the flow has ended, so only the number callback changes the state
+func myRequestsFlow() *tgutils.Flow {
+	handler := useMyRequestsCallbackHandler()
+	return tgutils.NewFlow(constants.MY_REQUESTS_STATES).
+		State(constants.MY_REQUESTS_START_STATE, useMyRequestsStartState(), constants.IDLE_STATE).
+		State(constants.MY_REQUESTS_NUMBER_STATE, useMyRequestsNumberState(), constants.IDLE_STATE).
+		Callback(myrequests.WithdrawCodec.Prefix(), tgutils.TypedCallback(myrequests.WithdrawCodec, handler.HandleWithdraw)).
+		Callback(myrequests.EditCodec.Prefix(), tgutils.TypedCallback(myrequests.EditCodec, handler.HandleEdit)).
+		Callback(myrequests.NumberCodec.Prefix(), tgutils.TypedCallback(myrequests.NumberCodec, handler.HandleNumber),
+			constants.MY_REQUESTS_NUMBER_STATE).
+		Callback(myrequests.MoveCodec.Prefix(), tgutils.TypedCallback(myrequests.MoveCodec, handler.HandleMove)).
+		Callback(constants.MY_REQUESTS_BACK_CALLBACKS, tgutils.CallbackHandlerFunc(handler.HandleBack))
+}
 
-	mux.RegisterCallback(constants.GROUP_CALLBACKS, useGroupCallbackHandler())
+// Buttons of settings are pressed after the flow has ended
+func settingsFlow() *tgutils.Flow {
+	return tgutils.NewFlow(constants.SETTINGS_STATES).
+		State(constants.SETTINGS_START_STATE, useSettingsStartState(), constants.IDLE_STATE).
+		Callback(settings.SettingsCodec.Prefix(), tgutils.TypedCallback(settings.SettingsCodec, useSettingsCallbackHandler().HandleCallback))
 }
 
-func RegisterQueueRoutes(mux *tgutils.Mux) {
-	mux.RegisterRoute(constants.QUEUE_START_STATE, useQueueStartState())
-	mux.RegisterRoute(constants.QUEUE_WAITING_STATE, useQueueWaitingState())
-	mux.RegisterCallback(constants.QUEUE_CALLBACKS, useQueueCallbackHandler())
+func resultsFlow() *tgutils.Flow {
+	return tgutils.NewFlow(constants.RESULTS_STATES).
+		State(constants.RESULTS_START_STATE, useResultsStartState(), constants.IDLE_STATE)
 }
 
-func RegisterAdminSubmitRoutes(mux *tgutils.Mux) {
-	mux.RegisterRoute(constants.ADMIN_SUBMIT_START_STATE, useAdminSubmitStartState())
-	mux.RegisterRoute(constants.ADMIN_SUBMITTING_NAME_STATE, useAdminSubmittingNameState())
-	mux.RegisterRoute(constants.ADMIN_SUBMITTING_GROUP_STATE, useAdminSubmittingGroupState())
-	mux.RegisterRoute(constants.ADMIN_SUBMITTING_PROOF_STATE, useAdminSubmittingProofState())
-	mux.RegisterRoute(constants.ADMIN_WAITING_STATE, useAdminWaitingState())
+func adminSubmitFlow() *tgutils.Flow {
+	return tgutils.NewFlow(constants.ADMIN_SUBMIT_STATES).
+		State(constants.ADMIN_SUBMIT_START_STATE, useAdminSubmitStartState(), constants.ADMIN_SUBMITTING_NAME_STATE,
+			constants.ADMIN_SUBMITTING_PROOF_STATE, constants.IDLE_STATE).
+		State(constants.ADMIN_SUBMITTING_NAME_STATE, useAdminSubmittingNameState(), constants.ADMIN_SUBMITTING_GROUP_STATE, constants.IDLE_STATE).
+		State(constants.ADMIN_SUBMITTING_GROUP_STATE, useAdminSubmittingGroupState(), constants.ADMIN_SUBMITTING_PROOF_STATE,
+			constants.ADMIN_SUBMITTING_NAME_STATE).
+		State(constants.ADMIN_SUBMITTING_PROOF_STATE, useAdminSubmittingProofState(), constants.ADMIN_WAITING_STATE,
+			constants.ADMIN_SUBMITTING_NAME_STATE).
+		State(constants.ADMIN_WAITING_STATE, useAdminWaitingState()).
+		Callback(constants.ADMIN_CALLBACKS, useAdminCallbackHandler(), constants.IDLE_STATE)
+}
 
-	mux.RegisterCallback(constants.ADMIN_CALLBACKS, useAdminCallbackHandler())
+func deleteFlow() *tgutils.Flow {
+	return tgutils.NewFlow(constants.DELETE_STATES).
+		State(constants.DELETE_START_STATE, useDeleteStartState(), constants.DELETE_CHOOSE_STATE).
+		State(constants.DELETE_CHOOSE_STATE, useDeleteChooseState(), constants.IDLE_STATE)
 }
 
-func RegisterDeleteRoutes(mux *tgutils.Mux) {
-	mux.RegisterRoute(constants.DELETE_START_STATE, useDeleteStartState())
-	mux.RegisterRoute(constants.DELETE_CHOOSE_STATE, useDeleteChooseState())
+func deleteRequestFlow() *tgutils.Flow {
+	return tgutils.NewFlow(constants.DELETE_REQUEST_STATES).
+		State(constants.DELETE_REQUEST_START, useDeleteRequestStartState(), constants.DELETE_REQUEST_WAITING, constants.IDLE_STATE).
+		State(constants.DELETE_REQUEST_WAITING, useDeleteRequestWaitingState(), constants.IDLE_STATE).
+		State(constants.DELETE_REQUEST_CHOOSE_STATE, useDeleteRequestChooseState(), constants.IDLE_STATE, constants.DELETE_REQUEST_START).
+		Callback(constants.DELETE_REQUEST_LESSON_CALLBACK, useDeleteRequestLessonCallbackHandler()).
+		Callback(constants.DELETE_REQUEST_TIME_CALLBACK, useDeleteRequestTimeCallbackHandler(), constants.DELETE_REQUEST_CHOOSE_STATE)
 }
 
-func RegisterCronCalbacks(mux *tgutils.Mux) {
-	mux.RegisterCallback(cron.REMINDER_CALLBACKS, useReminderCallbackHandler())
+func reorderFlow() *tgutils.Flow {
+	return tgutils.NewFlow(constants.REORDER_REQUESTS_STATES).
+		State(constants.REORDER_REQUEST_START_STATE, useReorderStartState(), constants.REORDER_WAITING_STATE, constants.IDLE_STATE).
+		State(constants.REORDER_WAITING_STATE, useReorderWaitingState(), constants.REORDER_REQUEST_START_STATE).
+		State(constants.REORDER_CHOOSE_STATE, useReorderChooseState(), constants.REORDER_REQUEST_METHOD_STATE,
+			constants.REORDER_CHOOSE_LESSON_STATE, constants.REORDER_REQUEST_START_STATE, constants.IDLE_STATE).
+		State(constants.REORDER_CHOOSE_LESSON_STATE, useReorderChooseLessonState(), constants.REORDER_CHOOSE_STATE).
+		State(constants.REORDER_REQUEST_METHOD_STATE, useReorderMethodState(), constants.IDLE_STATE, constants.REORDER_CHOOSE_STATE).
+		Callback(constants.REORDER_LESSON_NAME_CALLBACK, useReorderLessonCallbackHandler(), constants.REORDER_CHOOSE_STATE).
+		Callback(constants.REORDER_LESSON_CONCRETE_CALLBACK, useReorderConcreteLessonCallbackHandler(), constants.REORDER_REQUEST_METHOD_STATE)
+}
+
+// Buttons of the panel are pressed after the flow has ended, so that the admin can use other commands during the lesson
+func lessonModeFlow() *tgutils.Flow {
+	handler := useLessonModeCallbackHandler()
+	return tgutils.NewFlow(constants.LESSON_MODE_STATES).
+		State(constants.LESSON_MODE_START_STATE, useLessonModeStartState(), constants.IDLE_STATE).
+		State(constants.LESSON_MODE_GRADE_STATE, useLessonModeGradeState(), constants.IDLE_STATE).
+		Callback(constants.LESSON_MODE_SUBJECT_CALLBACKS, tgutils.CallbackHandlerFunc(handler.HandleSubject)).
+		Callback(lessonmode.PanelCodec.Prefix(), tgutils.TypedCallback(lessonmode.PanelCodec, handler.HandlePanel)).
+		Callback(lessonmode.GradeCodec.Prefix(), tgutils.TypedCallback(lessonmode.GradeCodec, handler.HandleGrade),
+			constants.LESSON_MODE_GRADE_STATE)
 }
 
 var useLabworkSubmitStartState = provider(
 	func() tgutils.MuxHandler {
-		return labworks.NewLabworkSubmitStartState(useTgBot(), useHandlersCache(), useLessonsRepository(), useUsersRepository())
+		return labworks.NewLabworkSubmitStartState(useTgBot(), useHandlersCache(), useLessonsRepository(), useUsersRepository(), useCallbackPayloads())
 	},
 )
 var useLabworkSubmitNumberState = provider(
@@ -108,7 +217,7 @@ var useLabworkSubmitWaitingState = provider(
 var useLabworkSubmitCallbackHandler = provider(
 	func() tgutils.CallbackHandler {
 		return labworks.NewLabworksCallbackHandler(useTgBot(), useHandlersCache(), useLessonsRepository(), useRequestsRepository(),
-			useLessonsRequestsRepository(), useUsersRepository(), UseSheetsApiService())
+			useLessonsRequestsRepository(), useUsersRepository(), UseSheetsApiService(), useCallbackPayloads(), useLiveQueues())
 	},
 )
 var useLabworkAddStartState = provider(
@@ -159,13 +268,13 @@ var useGroupSubmitWaitingState = provider(
 )
 var useGroupCallbackHandler = provider(
 	func() tgutils.CallbackHandler {
-		return group.NewGroupCallbackHandler(useUsersRepository(), useHandlersCache(), useRequestsRepository())
+		return group.NewGroupCallbackHandler(useUsersRepository(), useHandlersCache(), useRequestsRepository(), useCommandsMenu())
 	},
 )
 
 var useQueueStartState = provider(
 	func() tgutils.MuxHandler {
-		return queue.NewQueueStartState(useTgBot(), useHandlersCache(), useUsersRepository(), useLessonsRepository())
+		return queue.NewQueueStartState(useTgBot(), useHandlersCache(), useUsersRepository(), useLessonsRepository(), useCallbackPayloads())
 	},
 )
 var useQueueWaitingState = provider(
@@ -175,9 +284,51 @@ var useQueueWaitingState = provider(
 )
 var useQueueCallbackHandler = provider(
 	func() tgutils.CallbackHandler {
-		return queue.NewQueueCallbackHandler(useUsersRepository(), useLessonsRepository(), useHandlersCache(), useTgBot(), useLessonsRequestsRepository())
+		return queue.NewQueueCallbackHandler(useUsersRepository(), useLessonsRepository(), useHandlersCache(), useTgBot(), useLessonsRequestsRepository(), useCallbackPayloads(),
+			useLiveQueues())
+	},
+)
+
+var useMyRequestsStartState = provider(
+	func() tgutils.MuxHandler {
+		return myrequests.NewMyRequestsStartState(useTgBot(), useHandlersCache(), useLessonsRequestsRepository())
+	},
+)
+var useMyRequestsNumberState = provider(
+	func() tgutils.MuxHandler {
+		return myrequests.NewMyRequestsNumberState(useTgBot(), useHandlersCache(), useLessonsRequestsRepository(), useUsersRepository(),
+			useMyRequestsEditor())
 	},
 )
+var useMyRequestsCallbackHandler = provider(
+	func() *myrequests.MyRequestsCallbackHandler {
+		return myrequests.NewMyRequestsCallbackHandler(useHandlersCache(), useLessonsRequestsRepository(), useUsersRepository(),
+			useLessonsRepository(), useMyRequestsEditor())
+	},
+)
+var useMyRequestsEditor = provider(
+	func() *myrequests.RequestsEditor {
+		return myrequests.NewRequestsEditor(useLessonsRequestsRepository(), UseSheetsApiService(), useLiveQueues(), useTurnNotifier())
+	},
+)
+
+var useSettingsStartState = provider(
+	func() *settings.SettingsStartState {
+		return settings.NewSettingsStartState(useTgBot(), useHandlersCache(), useUsersRepository())
+	},
+)
+var useSettingsCallbackHandler = provider(
+	func() *settings.SettingsCallbackHandler {
+		return settings.NewSettingsCallbackHandler(useUsersRepository())
+	},
+)
+
+var useResultsStartState = provider(
+	func() *outcomes.ResultsStartState {
+		return outcomes.NewResultsStartState(useTgBot(), useHandlersCache(), useLabworkOutcomesRepository())
+	},
+)
+
 var useAdminSubmitStartState = provider(
 	func() tgutils.MuxHandler {
 		return admin.NewAdminSubmitState(useHandlersCache(), useTgBot(), useUsersRepository())
@@ -205,13 +356,31 @@ var useAdminWaitingState = provider(
 )
 var useAdminCallbackHandler = provider(
 	func() tgutils.CallbackHandler {
-		return admin.NewAdminCallbackHandler(useUsersRepository(), useHandlersCache(), useAdminRequestsRepository(), UseLessonsService())
+		return admin.NewAdminCallbackHandler(useUsersRepository(), useHandlersCache(), useAdminRequestsRepository(), UseLessonsService(), useCommandsMenu())
 	},
 )
 
 var useIdleState = provider(
 	func() tgutils.MuxHandler {
-		return stateMachine.NewIdleState(useHandlersCache(), useTgBot(), useUsersRepository(), useGroupsRepository(), useLessonsRepository(), useMux())
+		return stateMachine.NewIdleState(useHandlersCache(), useTgBot(), useUsersRepository(), useGroupsRepository(), useLessonsRepository(), useCrashesRepository(), useMux())
+	},
+)
+
+var useLanguageCallbackHandler = provider(
+	func() *stateMachine.LanguageCallbackHandler {
+		return stateMachine.NewLanguageCallbackHandler(useUsersRepository(), useCommandsMenu())
+	},
+)
+
+var useCommandsMenu = provider(
+	func() *stateMachine.CommandsMenu {
+		return stateMachine.NewCommandsMenu(useTgBot(), useUsersRepository())
+	},
+)
+
+var useCancelState = provider(
+	func() tgutils.MuxHandler {
+		return stateMachine.NewCancelState(useTgBot(), useUsersRepository())
 	},
 )
 
@@ -222,10 +391,94 @@ var useDeleteStartState = provider(
 )
 var useDeleteChooseState = provider(
 	func() *delete.DeleteChooseState {
-		return delete.NewDeleteChooseState(useTgBot(), useHandlersCache(), useUsersRepository())
+		return delete.NewDeleteChooseState(useTgBot(), useHandlersCache(), useUsersRepository(), useCommandsMenu())
+	},
+)
+
+var useDeleteRequestStartState = provider(
+	func() *requestdelete.DeleteStartState {
+		return requestdelete.NewDeleteStartState(useTgBot(), useHandlersCache(), useLessonsRequestsRepository(), useUsersRepository(),
+			useLessonsRepository(), useCallbackPayloads())
+	},
+)
+var useDeleteRequestWaitingState = provider(
+	func() *requestdelete.DeleteWaitingState {
+		return requestdelete.NewDeleteWaitingState(useTgBot(), useHandlersCache())
+	},
+)
+var useDeleteRequestChooseState = provider(
+	func() *requestdelete.DeleteChooseState {
+		return requestdelete.NewDeleteChooseState(useHandlersCache(), useTgBot(), useMux(), useLessonsRequestsRepository(), useLiveQueues(),
+			useTurnNotifier())
+	},
+)
+var useDeleteRequestLessonCallbackHandler = provider(
+	func() *requestdelete.DeleteLessonCallbackHandler {
+		return requestdelete.NewDeleteLessonCallbackHandler(useHandlersCache(), useUsersRepository(), useTgBot(), useLessonsRepository(), useCallbackPayloads())
+	},
+)
+var useDeleteRequestTimeCallbackHandler = provider(
+	func() *requestdelete.DeleteTimeCallbackHandler {
+		return requestdelete.NewDeleteTimeCallbackHandler(useHandlersCache(), useTgBot(), useLessonsRequestsRepository(), useUsersRepository())
+	},
+)
+
+var useReorderStartState = provider(
+	func() *reorder.ReorderStartState {
+		return reorder.NewReorderStartState(useHandlersCache(), useTgBot(), useUsersRepository(), useLessonsRepository(), useGroupsRepository(),
+			useCallbackPayloads())
+	},
+)
+var useReorderWaitingState = provider(
+	func() *reorder.ReorderWaitingState {
+		return reorder.NewReorderWaitingState(useHandlersCache(), useTgBot(), useMux())
+	},
+)
+var useReorderChooseState = provider(
+	func() *reorder.ReorderChooseAllState {
+		return reorder.NewReorderChooseState(useTgBot(), useHandlersCache(), useMux(), useLessonsRepository(), useUsersRepository())
+	},
+)
+var useReorderChooseLessonState = provider(
+	func() *reorder.ReorderChooseLessonState {
+		return reorder.NewReorderChooseLessonState(useHandlersCache(), useTgBot())
+	},
+)
+var useReorderMethodState = provider(
+	func() *reorder.ReorderMethodState {
+		return reorder.NewReorderMethodState(useHandlersCache(), useTgBot(), useLessonsRequestsRepository(), UseSheetsApiService(),
+			useLessonsRepository(), useMux(), useLiveQueues())
+	},
+)
+var useReorderLessonCallbackHandler = provider(
+	func() *reorder.ReorderLessonCallbackHandler {
+		return reorder.NewReorderLessonCallbackHandler(useTgBot(), useHandlersCache(), useCallbackPayloads())
+	},
+)
+var useReorderConcreteLessonCallbackHandler = provider(
+	func() *reorder.ReorderConcreteLessonCallbackHandler {
+		return reorder.NewReorderConcreteLessonCallbackHandler(useTgBot(), useHandlersCache())
+	},
+)
+
+var useLessonModeStartState = provider(
+	func() *lessonmode.LessonModeStartState {
+		return lessonmode.NewLessonModeStartState(useTgBot(), useHandlersCache(), useUsersRepository(), useLessonsRepository(), useCallbackPayloads())
+	},
+)
+var useLessonModeCallbackHandler = provider(
+	func() *lessonmode.LessonModeCallbackHandler {
+		return lessonmode.NewLessonModeCallbackHandler(useHandlersCache(), useUsersRepository(), useLessonsRepository(),
+			useLessonsRequestsRepository(), UseSheetsApiService(), useOutcomesRecorder(), useTurnNotifier(), useCallbackPayloads())
+	},
+)
+var useLessonModeGradeState = provider(
+	func() *lessonmode.LessonModeGradeState {
+		return lessonmode.NewLessonModeGradeState(useTgBot(), useHandlersCache(), useOutcomesRecorder())
 	},
 )
 
 var useReminderCallbackHandler = provider(func() *cron.ReminderCallbackHandler {
-	return cron.NewSheetsRefreshCallbackHandler(useLessonsRequestsRepository(), UseSheetsApiService(), useUsersRepository(), UseLessonsService())
+	return cron.NewSheetsRefreshCallbackHandler(useLessonsRequestsRepository(), UseSheetsApiService(), useUsersRepository(), UseLessonsService(),
+		useLiveQueues(), useOutcomesRecorder(), useTurnNotifier())
 })
@@ -5,6 +5,8 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/cron"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
 	google_docs_auth "github.com/aCrYoZPS/bsuir_queue_bot/src/google/auth"
 	driveapi "github.com/aCrYoZPS/bsuir_queue_bot/src/google/drive_api"
 	sheetsapi "github.com/aCrYoZPS/bsuir_queue_bot/src/google/sheets_api"
@@ -14,13 +16,27 @@ import (
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/bot"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers"
 	stateMachine "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers"
+	adminInterfaces "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/admin_submit/interfaces"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/queue"
 	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/option"
 	"google.golang.org/api/sheets/v4"
 )
 
-var useGroupsService = provider(
-	func() *iis_api.GroupsService {
+// GroupsService looks up groups, loaded from iis api, and their admins
+type GroupsService interface {
+	GetAdmins(ctx context.Context, groupName string) ([]entities.User, error)
+	DoesGroupExist(ctx context.Context, groupName string) (bool, error)
+}
+
+// LessonsService loads schedules of groups from iis api into the database and the sheets
+type LessonsService interface {
+	adminInterfaces.LessonsService
+	cron.LessonsRepoReminder
+}
+
+var useGroupsService, StubGroupsService = stubbableProvider(
+	func() GroupsService {
 		ctx, cancel := context.WithTimeout(context.Background(), constants.INIT_TIMEOUT)
 		defer cancel()
 		serv := iis_api.NewGroupsService(useGroupsRepository())
@@ -65,16 +81,16 @@ var useDriveApi = provider(
 	},
 )
 
-var UseDriveApiService = provider(
-	func() *driveapi.DriveApiService {
+var UseDriveApiService, StubDriveApiService = stubbableProvider(
+	func() driveapi.DriveApi {
 		return driveapi.NewDriveApiService(
 			useGroupsRepository(), useDriveApi(),
 		)
 	},
 )
 
-var UseSheetsApiService = provider(
-	func() *sheetsapi.SheetsApiService {
+var UseSheetsApiService, StubSheetsApiService = stubbableProvider(
+	func() sheetsapi.SheetsApi {
 		return sheetsapi.NewSheetsApiService(
 			useGroupsRepository(),
 			UseDriveApiService(), useSheetsApi(),
@@ -85,13 +101,13 @@ var UseSheetsApiService = provider(
 var UseMessageService = provider(
 	func() bot.MessagesService {
 		return update_handlers.NewMessagesHandler(
-			useMux(), useHandlersCache(),
+			useMux(), useHandlersCache(), useCrashReporter(),
 		)
 	},
 )
 
-var UseLessonsService = provider(
-	func() *iis_api.LessonsService {
+var UseLessonsService, StubLessonsService = stubbableProvider(
+	func() LessonsService {
 		return iis_api.NewLessonsService(useLessonsRepository(), UseSheetsApiService())
 	},
 )
@@ -99,6 +115,19 @@ var UseLessonsService = provider(
 var UseCallbacksService = provider(
 	func() bot.CallbacksService {
 		return stateMachine.NewCallbackService(
-			useHandlersCache(), useMux())
+			useHandlersCache(), useMux(), useCrashReporter())
+	},
+)
+
+var UseInlineQueriesService = provider(
+	func() bot.InlineQueriesService {
+		return stateMachine.NewInlineQueriesService(
+			queue.NewQueueInlineHandler(useUsersRepository(), useLessonsRepository(), useLessonsRequestsRepository()), useCrashReporter())
+	},
+)
+
+var useCrashReporter = provider(
+	func() *stateMachine.CrashReporter {
+		return stateMachine.NewCrashReporter(useCrashesRepository(), useHandlersCache(), useTgBot())
 	},
 )
@@ -0,0 +1,25 @@
+package interfaces
+
+import "context"
+
+type chatUserKey struct{}
+
+type chatUser struct {
+	chatId int64
+	userId int64
+}
+
+// WithChatUser scopes states and infos of the chat to the user, so that members of group chats have independent flows
+func WithChatUser(ctx context.Context, chatId, userId int64) context.Context {
+	return context.WithValue(ctx, chatUserKey{}, chatUser{chatId: chatId, userId: userId})
+}
+
+// ChatUser returns the user, which flow in the chat is being handled. Chats outside of the scope are treated as private ones,
+// which ids are equal to ids of their users, e.g. when handlers change states of requesters after approval
+func ChatUser(ctx context.Context, chatId int64) int64 {
+	scope, ok := ctx.Value(chatUserKey{}).(chatUser)
+	if !ok || scope.chatId != chatId {
+		return chatId
+	}
+	return scope.userId
+}
@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// CrashReport describes a panic or an unexpected error, which happened during handling of the update
+type CrashReport struct {
+	Id           int64
+	Time         time.Time
+	UpdateId     int
+	ChatId       int64
+	UserId       int64
+	State        string
+	CallbackData string
+	MessageText  string
+	Error        string
+	Stack        string
+}
+
+type CrashesRepository interface {
+	Save(ctx context.Context, report *CrashReport) error
+	GetLatest(ctx context.Context, limit int) ([]CrashReport, error)
+}
@@ -10,6 +10,7 @@ import (
 
 type CachedInfo struct {
 	chatId   int64
+	userId   int64
 	state    constants.State
 	sendTime time.Time
 }
@@ -26,14 +27,37 @@ func (info *CachedInfo) ChatId() int64 {
 	return info.chatId
 }
 
-func NewCachedInfo(ChatId int64, state constants.State) *CachedInfo {
-	return &CachedInfo{
+// UserId returns the user, which state in the chat it is. Is zero, unless it was set explicitly or loaded from the cache,
+// in which case the user is taken from the context
+func (info *CachedInfo) UserId() int64 {
+	return info.userId
+}
+
+func NewCachedInfo(ChatId int64, state constants.State, opts ...func(*CachedInfo)) *CachedInfo {
+	info := &CachedInfo{
 		chatId:   ChatId,
 		state:    state,
 		sendTime: time.Now(),
 	}
+	for _, opt := range opts {
+		opt(info)
+	}
+	return info
+}
+
+func WithSendTime(sendTime time.Time) func(*CachedInfo) {
+	return func(info *CachedInfo) {
+		info.sendTime = sendTime
+	}
+}
+
+func WithUserId(userId int64) func(*CachedInfo) {
+	return func(info *CachedInfo) {
+		info.userId = userId
+	}
 }
 
+// HandlersCache stores states and infos of the chats, scoped to the users of the context (see WithChatUser)
 type HandlersCache interface {
 	SaveState(context.Context, CachedInfo) error
 	GetState(ctx context.Context, chatId int64) (*CachedInfo, error)
@@ -42,4 +66,6 @@ type HandlersCache interface {
 	AcquireLock(ctx context.Context, chatId int64, key string) *sync.Mutex
 	ReleaseLock(ctx context.Context, chatId int64, key string)
 	RemoveInfo(ctx context.Context, chatId int64) error
+	// GetStaleStates returns states other than idle one, which weren't changed since given time
+	GetStaleStates(ctx context.Context, before time.Time) ([]CachedInfo, error)
 }
@@ -0,0 +1,26 @@
+package mocks
+
+import (
+	"context"
+
+	driveapi "github.com/aCrYoZPS/bsuir_queue_bot/src/google/drive_api"
+)
+
+// DriveApiMock pretends, that drive has no spreadsheets
+type DriveApiMock struct{}
+
+func NewDriveApiMock() *DriveApiMock {
+	return &DriveApiMock{}
+}
+
+func (*DriveApiMock) SetSpreadsheetPermissions(ctx context.Context, spreadsheetId string) error {
+	return nil
+}
+
+func (*DriveApiMock) DoesSheetExist(ctx context.Context, name string) (driveapi.SpreadsheetResult, error) {
+	return driveapi.SpreadsheetResult{}, nil
+}
+
+func (*DriveApiMock) GetSpreadsheets(ctx context.Context) ([]string, error) {
+	return []string{}, nil
+}
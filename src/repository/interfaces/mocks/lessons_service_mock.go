@@ -0,0 +1,26 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
+)
+
+// LessonsServiceMock creates sheets from lessons, which are already in the repository, instead of loading schedules from iis api
+type LessonsServiceMock struct {
+	interfaces.LessonsRepository
+	sheets *SheetsApiMock
+}
+
+func NewLessonsServiceMock(lessons interfaces.LessonsRepository, sheets *SheetsApiMock) *LessonsServiceMock {
+	return &LessonsServiceMock{LessonsRepository: lessons, sheets: sheets}
+}
+
+func (mock *LessonsServiceMock) AddGroupLessons(ctx context.Context, groupName string) (string, error) {
+	lessons, err := mock.GetAll(ctx, groupName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get group %s lessons in lessons service mock: %w", groupName, err)
+	}
+	return mock.sheets.CreateSheet(ctx, groupName, lessons)
+}
@@ -0,0 +1,142 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/labworks"
+)
+
+const MOCK_SHEET_URL = "https://docs.google.com/spreadsheets/d/mock"
+
+// SheetsApiMock records changes of the sheets instead of sending them to google
+type SheetsApiMock struct {
+	mu       sync.Mutex
+	labworks []labworks.AppendedLabwork
+	outcomes map[string]entities.Outcome
+	history  map[string][]entities.LabworkOutcome
+	lessons  []persistence.Lesson
+	sheets   []string
+	// appendErr is returned instead of appending rows, as if google failed
+	appendErr error
+}
+
+func NewSheetsApiMock() *SheetsApiMock {
+	return &SheetsApiMock{outcomes: map[string]entities.Outcome{}, history: map[string][]entities.LabworkOutcome{}}
+}
+
+func (mock *SheetsApiMock) Add(ctx context.Context, lesson *persistence.Lesson) error {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	mock.lessons = append(mock.lessons, *lesson)
+	return nil
+}
+
+func (mock *SheetsApiMock) CreateSheet(ctx context.Context, groupName string, lessons []persistence.Lesson) (string, error) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	mock.sheets = append(mock.sheets, groupName)
+	return MOCK_SHEET_URL, nil
+}
+
+func (mock *SheetsApiMock) ClearSpreadsheet(ctx context.Context, spreadsheetId string, before time.Time) error {
+	return nil
+}
+
+func (mock *SheetsApiMock) AddLabworkRequest(ctx context.Context, labwork *labworks.AppendedLabwork) error {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	if mock.appendErr != nil {
+		return mock.appendErr
+	}
+	mock.labworks = append(mock.labworks, *labwork)
+	return nil
+}
+
+// FailAppends makes appending of rows fail with the error, until it's called with nil
+func (mock *SheetsApiMock) FailAppends(err error) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	mock.appendErr = err
+}
+
+// DeleteLabworkRequest removes the first recorded row of the same student and labwork
+func (mock *SheetsApiMock) DeleteLabworkRequest(ctx context.Context, labwork *labworks.AppendedLabwork) error {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	for i, appended := range mock.labworks {
+		if appended.FullName == labwork.FullName && appended.DisciplineName == labwork.DisciplineName &&
+			appended.LabworkNumber == labwork.LabworkNumber && appended.RequestedDate == labwork.RequestedDate {
+			mock.labworks = slices.Delete(mock.labworks, i, i+1)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (mock *SheetsApiMock) SetLabworkOutcome(ctx context.Context, labwork *labworks.AppendedLabwork, outcome entities.Outcome) error {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	mock.outcomes[outcomeKey(labwork.FullName, labwork.LabworkNumber)] = outcome
+	return nil
+}
+
+func (mock *SheetsApiMock) WriteOutcomes(ctx context.Context, groupName string, outcomes []entities.LabworkOutcome) error {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	mock.history[groupName] = append([]entities.LabworkOutcome{}, outcomes...)
+	return nil
+}
+
+func (mock *SheetsApiMock) ReorderLessons(ctx context.Context, orderTypes []entities.OrderType, groupName, subject string) error {
+	return nil
+}
+
+func (mock *SheetsApiMock) ReorderLesson(ctx context.Context, orderTypes []entities.OrderType, groupName string,
+	lesson persistence.Lesson) error {
+	return nil
+}
+
+// Labworks returns rows, appended to the sheets by accepted labwork requests
+func (mock *SheetsApiMock) Labworks() []labworks.AppendedLabwork {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	return append([]labworks.AppendedLabwork{}, mock.labworks...)
+}
+
+// Lessons returns lessons, added to the sheets by admins
+func (mock *SheetsApiMock) Lessons() []persistence.Lesson {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	return append([]persistence.Lesson{}, mock.lessons...)
+}
+
+// Sheets returns names of groups, which sheets were created
+func (mock *SheetsApiMock) Sheets() []string {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	return append([]string{}, mock.sheets...)
+}
+
+// Outcome returns outcome of the labwork of the student, written to the sheets
+func (mock *SheetsApiMock) Outcome(fullName string, labworkNumber int8) entities.Outcome {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	return mock.outcomes[outcomeKey(fullName, labworkNumber)]
+}
+
+// History returns the last history of outcomes, written to the sheet of outcomes of the group
+func (mock *SheetsApiMock) History(groupName string) []entities.LabworkOutcome {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	return append([]entities.LabworkOutcome{}, mock.history[groupName]...)
+}
+
+func outcomeKey(fullName string, labworkNumber int8) string {
+	return fmt.Sprintf("%s/%d", fullName, labworkNumber)
+}
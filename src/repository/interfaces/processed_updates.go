@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+type ProcessedUpdatesRepository interface {
+	// MarkUpdate marks update (and callback query, if it is not empty) as being processed. Returns false, if either of them was already marked
+	MarkUpdate(ctx context.Context, updateId int, callbackId string) (bool, error)
+	// FinishUpdate marks update as successfully processed
+	FinishUpdate(ctx context.Context, updateId int) error
+	// UnmarkUpdate removes mark of the update, which failed, so that it is processed again, if it is delivered again
+	UnmarkUpdate(ctx context.Context, updateId int) error
+	// UnmarkUnfinished removes marks of updates, which processing was interrupted, e.g. by crash of the bot
+	UnmarkUnfinished(ctx context.Context) error
+	// LastUpdateId returns id of the last marked update, or 0 if there are none
+	LastUpdateId(ctx context.Context) (int, error)
+	// MarkCallback marks arbitrary callback key as processed. Returns false, if it was already marked
+	MarkCallback(ctx context.Context, key string) (bool, error)
+	UnmarkCallback(ctx context.Context, key string) error
+	DeleteBefore(ctx context.Context, before time.Time) error
+}
@@ -0,0 +1,48 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+)
+
+const CALLBACK_PAYLOADS_TABLE = "callback_payloads"
+
+var _ tgutils.PayloadsStore = (*CallbackPayloadsRepository)(nil)
+
+type CallbackPayloadsRepository struct {
+	db *sql.DB
+}
+
+func NewCallbackPayloadsRepository(db *sql.DB) *CallbackPayloadsRepository {
+	return &CallbackPayloadsRepository{db: db}
+}
+
+func (repo *CallbackPayloadsRepository) SavePayload(ctx context.Context, token, payload string, expiresAt time.Time) error {
+	query := fmt.Sprintf("INSERT INTO %s (token, payload, expires_at) VALUES ($1, $2, $3)", CALLBACK_PAYLOADS_TABLE)
+	_, err := repo.db.ExecContext(ctx, query, token, payload, expiresAt.Unix())
+	return err
+}
+
+func (repo *CallbackPayloadsRepository) GetPayload(ctx context.Context, token string) (string, error) {
+	query := fmt.Sprintf("SELECT payload FROM %s WHERE token=$1 AND expires_at>$2", CALLBACK_PAYLOADS_TABLE)
+	payload := ""
+	err := repo.db.QueryRowContext(ctx, query, token, time.Now().Unix()).Scan(&payload)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", tgutils.ErrPayloadNotFound
+		}
+		return "", err
+	}
+	return payload, nil
+}
+
+func (repo *CallbackPayloadsRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE expires_at<=$1", CALLBACK_PAYLOADS_TABLE)
+	_, err := repo.db.ExecContext(ctx, query, before.Unix())
+	return err
+}
@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
+)
+
+const CRASHES_TABLE = "crashes"
+
+var _ interfaces.CrashesRepository = (*CrashesRepository)(nil)
+
+type CrashesRepository struct {
+	db *sql.DB
+}
+
+func NewCrashesRepository(db *sql.DB) *CrashesRepository {
+	return &CrashesRepository{db: db}
+}
+
+func (repo *CrashesRepository) Save(ctx context.Context, report *interfaces.CrashReport) error {
+	query := fmt.Sprintf(`INSERT INTO %s (crash_time, update_id, chat_id, user_id, state, callback_data, message_text, error, stack)
+	 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`, CRASHES_TABLE)
+	res, err := repo.db.ExecContext(ctx, query, report.Time.Unix(), report.UpdateId, report.ChatId, report.UserId, report.State,
+		report.CallbackData, report.MessageText, report.Error, report.Stack)
+	if err != nil {
+		return err
+	}
+	report.Id, err = res.LastInsertId()
+	return err
+}
+
+func (repo *CrashesRepository) GetLatest(ctx context.Context, limit int) ([]interfaces.CrashReport, error) {
+	query := fmt.Sprintf(`SELECT id, crash_time, update_id, chat_id, user_id, state, callback_data, message_text, error, stack
+	 FROM %s ORDER BY crash_time DESC, id DESC LIMIT $1`, CRASHES_TABLE)
+	rows, err := repo.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	reports := []interfaces.CrashReport{}
+	for rows.Next() {
+		report := interfaces.CrashReport{}
+		crashTime := int64(0)
+		err := rows.Scan(&report.Id, &crashTime, &report.UpdateId, &report.ChatId, &report.UserId, &report.State,
+			&report.CallbackData, &report.MessageText, &report.Error, &report.Stack)
+		if err != nil {
+			return nil, err
+		}
+		report.Time = time.Unix(crashTime, 0)
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+)
+
+const FLOW_MARKUPS_TABLE = "flow_markups"
+
+var _ tgutils.MarkupsStore = (*FlowMarkupsRepository)(nil)
+
+type FlowMarkupsRepository struct {
+	db *sql.DB
+}
+
+func NewFlowMarkupsRepository(db *sql.DB) *FlowMarkupsRepository {
+	return &FlowMarkupsRepository{db: db}
+}
+
+func (repo *FlowMarkupsRepository) AddMarkup(ctx context.Context, chatId int64, msgId int) error {
+	query := fmt.Sprintf("INSERT OR IGNORE INTO %s (chat_id, msg_id, user_id) VALUES ($1, $2, $3)", FLOW_MARKUPS_TABLE)
+	_, err := repo.db.ExecContext(ctx, query, chatId, msgId, interfaces.ChatUser(ctx, chatId))
+	return err
+}
+
+func (repo *FlowMarkupsRepository) GetMarkups(ctx context.Context, chatId int64) ([]int, error) {
+	query := fmt.Sprintf("SELECT msg_id FROM %s WHERE chat_id=$1 AND user_id=$2", FLOW_MARKUPS_TABLE)
+	rows, err := repo.db.QueryContext(ctx, query, chatId, interfaces.ChatUser(ctx, chatId))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	msgIds := []int{}
+	for rows.Next() {
+		msgId := 0
+		err := rows.Scan(&msgId)
+		if err != nil {
+			return nil, err
+		}
+		msgIds = append(msgIds, msgId)
+	}
+	return msgIds, rows.Err()
+}
+
+func (repo *FlowMarkupsRepository) RemoveMarkups(ctx context.Context, chatId int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE chat_id=$1 AND user_id=$2", FLOW_MARKUPS_TABLE)
+	_, err := repo.db.ExecContext(ctx, query, chatId, interfaces.ChatUser(ctx, chatId))
+	return err
+}
+
+func (repo *FlowMarkupsRepository) GetMarkupUser(ctx context.Context, chatId int64, msgId int) (int64, error) {
+	query := fmt.Sprintf("SELECT user_id FROM %s WHERE chat_id=$1 AND msg_id=$2", FLOW_MARKUPS_TABLE)
+	userId := int64(0)
+	err := repo.db.QueryRowContext(ctx, query, chatId, msgId).Scan(&userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return userId, err
+}
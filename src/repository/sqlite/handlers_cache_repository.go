@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
@@ -31,26 +32,53 @@ const (
 )
 
 func (cache *HandlersCache) SaveState(ctx context.Context, info interfaces.CachedInfo) error {
-	query := fmt.Sprintf("INSERT OR REPLACE INTO %s (chat_id, state) VALUES ($1, $2)", STATES_TABLE)
-	_, err := cache.db.ExecContext(ctx, query, info.ChatId(), info.State())
+	userId := info.UserId()
+	if userId == 0 {
+		userId = interfaces.ChatUser(ctx, info.ChatId())
+	}
+	query := fmt.Sprintf("INSERT OR REPLACE INTO %s (chat_id, user_id, state, updated_at) VALUES ($1, $2, $3, $4)", STATES_TABLE)
+	_, err := cache.db.ExecContext(ctx, query, info.ChatId(), userId, info.State(), info.SendTime().Unix())
 	return err
 }
 
 func (cache *HandlersCache) GetState(ctx context.Context, chatId int64) (*interfaces.CachedInfo, error) {
-	query := fmt.Sprintf("SELECT state FROM %s WHERE chat_id=$1", STATES_TABLE)
-	row := cache.db.QueryRowContext(ctx, query, chatId)
+	userId := interfaces.ChatUser(ctx, chatId)
+	query := fmt.Sprintf("SELECT state, updated_at FROM %s WHERE chat_id=$1 AND user_id=$2", STATES_TABLE)
+	row := cache.db.QueryRowContext(ctx, query, chatId, userId)
 	if row.Err() != nil {
 		return nil, row.Err()
 	}
 	state := ""
-	err := row.Scan(&state)
+	updatedAt := int64(0)
+	err := row.Scan(&state, &updatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return interfaces.NewCachedInfo(chatId, constants.State(state)), nil
+			return interfaces.NewCachedInfo(chatId, constants.State(state), interfaces.WithUserId(userId)), nil
 		}
 		return nil, err
 	}
-	return interfaces.NewCachedInfo(chatId, constants.State(state)), nil
+	return interfaces.NewCachedInfo(chatId, constants.State(state), interfaces.WithUserId(userId),
+		interfaces.WithSendTime(time.Unix(updatedAt, 0))), nil
+}
+
+func (cache *HandlersCache) GetStaleStates(ctx context.Context, before time.Time) ([]interfaces.CachedInfo, error) {
+	query := fmt.Sprintf("SELECT chat_id, user_id, state, updated_at FROM %s WHERE state NOT IN ('', $1) AND updated_at<$2", STATES_TABLE)
+	rows, err := cache.db.QueryContext(ctx, query, string(constants.IDLE_STATE), before.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	infos := []interfaces.CachedInfo{}
+	for rows.Next() {
+		chatId, userId, state, updatedAt := int64(0), int64(0), "", int64(0)
+		err := rows.Scan(&chatId, &userId, &state, &updatedAt)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, *interfaces.NewCachedInfo(chatId, constants.State(state), interfaces.WithUserId(userId),
+			interfaces.WithSendTime(time.Unix(updatedAt, 0))))
+	}
+	return infos, rows.Err()
 }
 
 func (cache *HandlersCache) AcquireLock(ctx context.Context, chatId int64, key string) *sync.Mutex {
@@ -67,14 +95,14 @@ func (cache *HandlersCache) ReleaseLock(ctx context.Context, chatId int64, key s
 }
 
 func (cache *HandlersCache) SaveInfo(ctx context.Context, chatId int64, json string) error {
-	query := fmt.Sprintf("INSERT OR REPLACE INTO %s (chat_id, json) VALUES ($1, $2)", INFO_TABLE)
-	_, err := cache.db.ExecContext(ctx, query, chatId, json)
+	query := fmt.Sprintf("INSERT OR REPLACE INTO %s (chat_id, user_id, json) VALUES ($1, $2, $3)", INFO_TABLE)
+	_, err := cache.db.ExecContext(ctx, query, chatId, interfaces.ChatUser(ctx, chatId), json)
 	return err
 }
 
 func (cache *HandlersCache) GetInfo(ctx context.Context, chatId int64) (string, error) {
-	query := fmt.Sprintf("SELECT json FROM %s WHERE chat_id=$1", INFO_TABLE)
-	row := cache.db.QueryRowContext(ctx, query, chatId)
+	query := fmt.Sprintf("SELECT json FROM %s WHERE chat_id=$1 AND user_id=$2", INFO_TABLE)
+	row := cache.db.QueryRowContext(ctx, query, chatId, interfaces.ChatUser(ctx, chatId))
 	if row.Err() != nil {
 		return "", row.Err()
 	}
@@ -87,8 +115,8 @@ func (cache *HandlersCache) GetInfo(ctx context.Context, chatId int64) (string,
 }
 
 func (cache *HandlersCache) RemoveInfo(ctx context.Context, chatId int64) error {
-	query := fmt.Sprintf("DELETE FROM %s WHERE chat_id=$1", INFO_TABLE)
-	_, err := cache.db.ExecContext(ctx, query, chatId)
+	query := fmt.Sprintf("DELETE FROM %s WHERE chat_id=$1 AND user_id=$2", INFO_TABLE)
+	_, err := cache.db.ExecContext(ctx, query, chatId, interfaces.ChatUser(ctx, chatId))
 	if err != nil {
 		return err
 	}
@@ -1,11 +1,25 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
 	"io"
 	"os"
+	"strings"
 )
 
+// Dispatcher shards, cron jobs and queue refreshes write concurrently. Transactions take the write lock right away
+// and wait for each other, instead of failing with locked database
+const CONNECTION_OPTIONS = "_busy_timeout=5000&_txlock=immediate"
+
+// Dsn adds connection options to the database file, keeping options which are already there
+func Dsn(file string) string {
+	if strings.Contains(file, "?") {
+		return file + "&" + CONNECTION_OPTIONS
+	}
+	return file + "?" + CONNECTION_OPTIONS
+}
+
 func DatabaseInit(db *sql.DB) error {
 	queryFile, err := os.Open(os.Getenv("SQLITE_INIT_FILE"))
 	if err != nil {
@@ -18,6 +32,9 @@ func DatabaseInit(db *sql.DB) error {
 	}
 
 	_, err = db.Exec(string(query))
+	if err != nil {
+		return err
+	}
 
-	return err
+	return migrate(context.Background(), db)
 }
@@ -0,0 +1,124 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+)
+
+const LABWORK_OUTCOMES_TABLE = "labwork_outcomes"
+
+// Names of the student and of the one, who marked the outcome, are joined, since users can leave the group later
+var labworkOutcomesSelect = fmt.Sprintf("SELECT o.id, o.request_id, o.lesson_id, o.user_id, o.group_id, o.subject, o.labwork_number, "+
+	"o.outcome, o.grade, o.comment, o.marked_by, o.marked_at, COALESCE(u.full_name, ''), COALESCE(m.full_name, '') FROM %s AS o "+
+	"LEFT JOIN %s AS u ON u.tg_id=o.user_id LEFT JOIN %[2]s AS m ON m.tg_id=o.marked_by", LABWORK_OUTCOMES_TABLE, USERS_TABLE)
+
+type LabworkOutcomesRepository struct {
+	db *sql.DB
+}
+
+func NewLabworkOutcomesRepository(db *sql.DB) *LabworkOutcomesRepository {
+	return &LabworkOutcomesRepository{db: db}
+}
+
+// Save adds the outcome to the history and returns its id. Outcome of the request, which was already marked during the same lesson,
+// is replaced instead, so that admins can correct their marks, while outcomes of the previous lessons are kept.
+// Grade and comment are reset, when the outcome changes, since they were given for the previous one
+func (repo *LabworkOutcomesRepository) Save(ctx context.Context, outcome *entities.LabworkOutcome) (int64, error) {
+	query := fmt.Sprintf("INSERT INTO %s (request_id, lesson_id, user_id, group_id, subject, labwork_number, outcome, grade, comment, "+
+		"marked_by, marked_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) ON CONFLICT(request_id, lesson_id) DO UPDATE SET "+
+		"grade=CASE WHEN outcome=excluded.outcome THEN grade ELSE excluded.grade END, "+
+		"comment=CASE WHEN outcome=excluded.outcome THEN comment ELSE excluded.comment END, "+
+		"outcome=excluded.outcome, marked_by=excluded.marked_by, marked_at=excluded.marked_at RETURNING id", LABWORK_OUTCOMES_TABLE)
+	var id int64
+	err := repo.db.QueryRowContext(ctx, query, outcome.RequestId, outcome.LessonId, outcome.UserId, outcome.GroupId, outcome.Subject,
+		outcome.LabworkNumber, outcome.Outcome, outcome.Grade, outcome.Comment, outcome.MarkedBy, outcome.MarkedAt.Format(savedFormat)).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save outcome of labwork: %w", err)
+	}
+	return id, nil
+}
+
+// AddIfAbsent adds the outcome, unless the request was already marked during the lesson. Returns whether it was added
+func (repo *LabworkOutcomesRepository) AddIfAbsent(ctx context.Context, outcome *entities.LabworkOutcome) (bool, error) {
+	query := fmt.Sprintf("INSERT INTO %s (request_id, lesson_id, user_id, group_id, subject, labwork_number, outcome, grade, comment, "+
+		"marked_by, marked_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) ON CONFLICT(request_id, lesson_id) DO NOTHING",
+		LABWORK_OUTCOMES_TABLE)
+	res, err := repo.db.ExecContext(ctx, query, outcome.RequestId, outcome.LessonId, outcome.UserId, outcome.GroupId, outcome.Subject,
+		outcome.LabworkNumber, outcome.Outcome, outcome.Grade, outcome.Comment, outcome.MarkedBy, outcome.MarkedAt.Format(savedFormat))
+	if err != nil {
+		return false, fmt.Errorf("failed to add outcome of labwork: %w", err)
+	}
+	added, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check addition of outcome: %w", err)
+	}
+	return added != 0, nil
+}
+
+// Get returns nil, if there is no such outcome
+func (repo *LabworkOutcomesRepository) Get(ctx context.Context, id int64) (*entities.LabworkOutcome, error) {
+	outcomes, err := repo.query(ctx, labworkOutcomesSelect+" WHERE o.id=$1", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outcome %d: %w", id, err)
+	}
+	if len(outcomes) == 0 {
+		return nil, nil
+	}
+	return &outcomes[0], nil
+}
+
+func (repo *LabworkOutcomesRepository) SetGrade(ctx context.Context, id int64, grade int8, comment string) error {
+	query := fmt.Sprintf("UPDATE %s SET grade=$1, comment=$2 WHERE id=$3", LABWORK_OUTCOMES_TABLE)
+	_, err := repo.db.ExecContext(ctx, query, grade, comment, id)
+	if err != nil {
+		return fmt.Errorf("failed to set grade of outcome %d: %w", id, err)
+	}
+	return nil
+}
+
+// GetUserOutcomes returns history of the user by subjects and labworks, attempts of the same labwork go in order they were marked
+func (repo *LabworkOutcomesRepository) GetUserOutcomes(ctx context.Context, tgId int64) ([]entities.LabworkOutcome, error) {
+	outcomes, err := repo.query(ctx, labworkOutcomesSelect+" WHERE o.user_id=$1 ORDER BY o.subject, o.labwork_number, o.marked_at", tgId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outcomes of user %d: %w", tgId, err)
+	}
+	return outcomes, nil
+}
+
+// GetGroupOutcomes returns history of the group by students, subjects and labworks
+func (repo *LabworkOutcomesRepository) GetGroupOutcomes(ctx context.Context, groupId int64) ([]entities.LabworkOutcome, error) {
+	outcomes, err := repo.query(ctx, labworkOutcomesSelect+" WHERE o.group_id=$1 ORDER BY u.full_name, o.user_id, o.subject, "+
+		"o.labwork_number, o.marked_at", groupId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outcomes of group %d: %w", groupId, err)
+	}
+	return outcomes, nil
+}
+
+func (repo *LabworkOutcomesRepository) query(ctx context.Context, query string, args ...any) ([]entities.LabworkOutcome, error) {
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	outcomes := []entities.LabworkOutcome{}
+	for rows.Next() {
+		var (
+			outcome  entities.LabworkOutcome
+			markedAt string
+		)
+		err := rows.Scan(&outcome.Id, &outcome.RequestId, &outcome.LessonId, &outcome.UserId, &outcome.GroupId, &outcome.Subject,
+			&outcome.LabworkNumber, &outcome.Outcome, &outcome.Grade, &outcome.Comment, &outcome.MarkedBy, &markedAt, &outcome.FullName,
+			&outcome.MarkedByName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outcome of labwork: %w", err)
+		}
+		outcome.MarkedAt, _ = time.Parse(savedFormat, markedAt)
+		outcomes = append(outcomes, outcome)
+	}
+	return outcomes, rows.Err()
+}
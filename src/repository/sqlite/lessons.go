@@ -55,12 +55,12 @@ func (repo *LessonsRepository) AddRange(ctx context.Context, lessons []*entities
 }
 
 func (repo *LessonsRepository) Get(ctx context.Context, id int64) (persistence.Lesson, error) {
-	query := fmt.Sprintf("SELECT l.group_id, l.lesson_type, l.subject, l.subgroup_number, l.date_time FROM %s as l WHERE l.id=$1",
+	query := fmt.Sprintf("SELECT l.id, l.group_id, l.lesson_type, l.subject, l.subgroup_number, l.date_time FROM %s as l WHERE l.id=$1",
 		LESSONS_TABLE)
 	row := repo.db.QueryRowContext(ctx, query, id)
 	var lesson persistence.Lesson
 	var storedDateTime int64
-	err := row.Scan(&lesson.GroupId, &lesson.LessonType, &lesson.SubgroupNumber, &storedDateTime)
+	err := row.Scan(&lesson.Id, &lesson.GroupId, &lesson.LessonType, &lesson.Subject, &lesson.SubgroupNumber, &storedDateTime)
 	if err != nil {
 		return persistence.Lesson{}, err
 	}
@@ -155,6 +155,31 @@ func (repo *LessonsRepository) GetSubjects(ctx context.Context, groupId int64) (
 	return subjects, nil
 }
 
+// GetCurrentSubjects returns subjects of lessons since the start of today, unlike GetSubjects, which skips lessons of today
+func (repo *LessonsRepository) GetCurrentSubjects(ctx context.Context, groupId int64) ([]string, error) {
+	now := time.Now()
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local).Unix()
+	query := fmt.Sprintf("SELECT DISTINCT subject FROM %s WHERE group_id=$1 AND date_time >= $2 ORDER BY subject", LESSONS_TABLE)
+	rows, err := repo.db.QueryContext(ctx, query, groupId, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	subjects := []string{}
+	for rows.Next() {
+		var subject string
+		err := rows.Scan(&subject)
+		if err != nil {
+			return nil, err
+		}
+		subjects = append(subjects, subject)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return subjects, nil
+}
+
 const falsePositiveRate = 0.01
 
 func (repo *LessonsRepository) getSortedLessons(lessons []*entities.Lesson) []persistence.Lesson {
@@ -252,8 +277,14 @@ func (repo *LessonsRepository) GetLessonByRequest(ctx context.Context, requestId
 	return &lesson, nil
 }
 
+// DeleteLessons also deletes queue subscriptions of the lessons, so that their messages aren't edited anymore
 func (repo *LessonsRepository) DeleteLessons(ctx context.Context, before time.Time) error {
-	query := fmt.Sprintf("DELETE FROM %s WHERE date_time-$1 < 0", LESSONS_TABLE)
+	query := fmt.Sprintf("DELETE FROM %s WHERE lesson_id IN (SELECT id FROM %s WHERE date_time-$1 < 0)", QUEUE_SUBSCRIPTIONS_TABLE, LESSONS_TABLE)
 	_, err := repo.db.ExecContext(ctx, query, before.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to delete queue subscriptions of old lessons: %w", err)
+	}
+	query = fmt.Sprintf("DELETE FROM %s WHERE date_time-$1 < 0", LESSONS_TABLE)
+	_, err = repo.db.ExecContext(ctx, query, before.Unix())
 	return err
 }
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
@@ -55,7 +56,7 @@ func (repo *LessonsRequestsRepository) Add(ctx context.Context, req *entities.Le
 }
 
 func (repo *LessonsRequestsRepository) Get(ctx context.Context, id int64) (*entities.LessonRequest, error) {
-	query := fmt.Sprintf("SELECT id, user_id, lesson_id, msg_id, chat_id, subgroup_num, submit_time FROM %s+ WHERE id=$1",
+	query := fmt.Sprintf("SELECT id, user_id, lesson_id, msg_id, chat_id, subgroup_num, submit_time FROM %s WHERE id=$1",
 	 LESSONS_REQUESTS_TABLE)
 	row := repo.db.QueryRowContext(ctx, query, id)
 	if row.Err() != nil {
@@ -89,7 +90,8 @@ func (repo *LessonsRequestsRepository) GetByTgIds(ctx context.Context, msgId int
 }
 
 func (repo *LessonsRequestsRepository) GetLessonRequests(ctx context.Context, lessonId int64) ([]entities.LessonRequest, error) {
-	query := fmt.Sprintf("SELECT id, user_id, lesson_id, msg_id, chat_id, subgroup_num FROM %s WHERE lesson_id=$1", LESSONS_REQUESTS_TABLE)
+	query := fmt.Sprintf("SELECT id, user_id, lesson_id, msg_id, chat_id, subgroup_num FROM %s " +
+	"WHERE lesson_id=$1 ORDER BY order_position", LESSONS_REQUESTS_TABLE)
 	rows, err := repo.db.QueryContext(ctx, query, lessonId)
 	if err != nil {
 		return nil, err
@@ -115,11 +117,12 @@ func (repo *LessonsRequestsRepository) Delete(ctx context.Context, requestId int
 	if err != nil {
 		return fmt.Errorf("failed to begin tx during lsson request delition: %w", err)
 	}
+	defer tx.Rollback()
 	var lessonId int64
 	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1 RETURNING lesson_id", LESSONS_REQUESTS_TABLE)
 	row := tx.QueryRowContext(ctx, query, requestId)
 	if row.Err() != nil {
-		return fmt.Errorf("failed to delete lesson request: %w", err)
+		return fmt.Errorf("failed to delete lesson request: %w", row.Err())
 	}
 	err = row.Scan(&lessonId)
 	if err != nil {
@@ -136,6 +139,8 @@ func (repo *LessonsRequestsRepository) Delete(ctx context.Context, requestId int
 	return nil
 }
 
+// SetToNextLesson moves the request to the next lesson of the subject. Outcomes are kept by lessons, so the student is called
+// again during the next lesson, while the outcome itself stays in the history of outcomes
 func (repo *LessonsRequestsRepository) SetToNextLesson(ctx context.Context, requestId int64) error {
 	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -166,34 +171,10 @@ func (repo *LessonsRequestsRepository) SetToNextLesson(ctx context.Context, requ
 	return nil
 }
 
+// SetAccepted marks request as approved by admin, so that it appears in the queue of the lesson
 func (repo *LessonsRequestsRepository) SetAccepted(ctx context.Context, requestId int64) error {
-	query := fmt.Sprintf("SELECT q.lesson_id, q.order_type, q.ascending FROM %s AS q " + 
-	"INNER JOIN %s AS r ON r.lesson_id=$1 WHERE q.lesson_id=r.lesson_id", QUEUE_TABLE, LESSONS_REQUESTS_TABLE)
-	rows, err := repo.db.QueryContext(ctx, query, requestId)
-	if err != nil {
-		return fmt.Errorf("failed to read lesson requests order: %w", err)
-	}
-	defer rows.Close()
-
-	orderTypes := []persistence.OrderType{}
-	var lessonId int64
-	for rows.Next() {
-		var orderType persistence.OrderType
-		err = rows.Scan(&lessonId, &orderType.Value, &orderType.Ascending)
-		if err != nil {
-			return fmt.Errorf("failed to scan order types: %w", err)
-		}
-		orderTypes = append(orderTypes, orderType)
-	}
-
-	tx, err := repo.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin tx: %w", err)
-	}
-	defer tx.Rollback()
-
-	query = fmt.Sprintf("UPDATE %s SET is_pending=0 WHERE id=$1", LESSONS_REQUESTS_TABLE)
-	_, err = tx.ExecContext(ctx, query, requestId)
+	query := fmt.Sprintf("UPDATE %s SET is_pending=FALSE WHERE id=$1", LESSONS_REQUESTS_TABLE)
+	_, err := repo.db.ExecContext(ctx, query, requestId)
 	if err != nil {
 		return fmt.Errorf("failed to set request as not pending: %w", err)
 	}
@@ -234,26 +215,32 @@ func (repo *LessonsRequestsRepository) ChangeOrderation(ctx context.Context, ord
 	return nil
 }
 
-func (repo *LessonsRequestsRepository) ChangeOrderationSubject(ctx context.Context, orderTypes []entities.OrderType, subject string) error {
-	tx, err := repo.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin tx: %w", err)
-	}
-
-	defer tx.Rollback()
-
-	query := fmt.Sprintf("SELECT id from %[1]s where name =$1", LESSONS_TABLE)
-	rows, err := tx.QueryContext(ctx, query, subject)
+func (repo *LessonsRequestsRepository) ChangeOrderationSubject(ctx context.Context, orderTypes []entities.OrderType, subject string, groupId int64) error {
+	query := fmt.Sprintf("SELECT id FROM %s WHERE subject=$1 AND group_id=$2", LESSONS_TABLE)
+	rows, err := repo.db.QueryContext(ctx, query, subject, groupId)
 	if err != nil {
 		return fmt.Errorf("failed to get lessons id: %w", err)
 	}
+	defer rows.Close()
+	lessonsIds := []int64{}
 	for rows.Next() {
 		var lessonId int64
 		err := rows.Scan(&lessonId)
 		if err != nil {
 			return fmt.Errorf("failed to scan lesson id: %w", err)
 		}
+		lessonsIds = append(lessonsIds, lessonId)
+	}
+	rows.Close()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+
+	defer tx.Rollback()
 
+	for _, lessonId := range lessonsIds {
 		query := fmt.Sprintf("DELETE FROM %s WHERE lesson_id=$1", QUEUE_TABLE)
 		_, err = tx.ExecContext(ctx, query, lessonId)
 		if err != nil {
@@ -282,8 +269,8 @@ func (repo *LessonsRequestsRepository) ChangeOrderationSubject(ctx context.Conte
 
 //Separate swap table for all of this shit, which is also queried every time... Like, holy fuck...
 func (repo *LessonsRequestsRepository) reorderRequestsTx(ctx context.Context, tx *sql.Tx, lessonId int64) error {
-	query := fmt.Sprintf("SELECT id, user_id, lesson_id, msg_id, chat_id, subgroup_num, submit_time FROM %s" + 
-	" WHERE id=$1 AND is_pending=false", LESSONS_REQUESTS_TABLE)
+	query := fmt.Sprintf("SELECT id, user_id, lesson_id, msg_id, chat_id, subgroup_num, submit_time FROM %s" +
+	" WHERE lesson_id=$1", LESSONS_REQUESTS_TABLE)
 	rows, err := tx.QueryContext(ctx, query, lessonId)
 	if err != nil {
 		return fmt.Errorf("failed to query requests for lesson: %w", err)
@@ -292,15 +279,18 @@ func (repo *LessonsRequestsRepository) reorderRequestsTx(ctx context.Context, tx
 	requests := []entities.LessonRequest{}
 	for rows.Next() {
 		var cur entities.LessonRequest
-		err = rows.Scan(&cur.Id, &cur.UserId, &cur.LessonId, &cur.MsgId, &cur.ChatId, &cur.LabworkNumber, &cur.SubmitTime)
+		var storedTime string
+		err = rows.Scan(&cur.Id, &cur.UserId, &cur.LessonId, &cur.MsgId, &cur.ChatId, &cur.LabworkNumber, &storedTime)
 		if err != nil {
 			return fmt.Errorf("failed to scan lesson request: %w", err)
 		}
+		cur.SubmitTime, _ = time.Parse(savedFormat, storedTime)
 		requests = append(requests, cur)
 	}
+	rows.Close()
 
 	orderTypes := make([]persistence.OrderType, 0)
-	query = fmt.Sprintf("SELECT q.order_type, q.ascending FROM %s as q WHERE q.lesson_id=$1", QUEUE_TABLE)
+	query = fmt.Sprintf("SELECT q.order_type, q.ascending FROM %s as q WHERE q.lesson_id=$1 ORDER BY q.id", QUEUE_TABLE)
 	rows, err = tx.QueryContext(ctx, query, lessonId)
 	if err != nil {
 		return fmt.Errorf("failed to read lesson requests order during reordering: %w", err)
@@ -314,37 +304,32 @@ func (repo *LessonsRequestsRepository) reorderRequestsTx(ctx context.Context, tx
 		}
 		orderTypes = append(orderTypes, cur)
 	}
+	rows.Close()
+	if len(orderTypes) == 0 {
+		orderTypes = persistence.NewPersistedQueue().OrderedBy
+	}
 
-	prev := func(_, _ entities.LessonRequest) int { return 0 }
-	for _, orderType := range orderTypes {
-		cur := func(_, _ entities.LessonRequest) int { return 0 }
-		switch orderType.Value {
-		case persistence.ByLabworkNumber:
-			cur = func(a, b entities.LessonRequest) int {
-				if orderType.Ascending {
-					return int((a.LabworkNumber - b.LabworkNumber))
-				} else {
-					return (int(b.LabworkNumber - a.LabworkNumber))
-				}
+	//Order types are applied one after another, so the next one only breaks ties of the previous ones
+	slices.SortStableFunc(requests, func(a, b entities.LessonRequest) int {
+		for _, orderType := range orderTypes {
+			cmp := 0
+			switch orderType.Value {
+			case persistence.ByLabworkNumber:
+				cmp = int(a.LabworkNumber) - int(b.LabworkNumber)
+			case persistence.BySubmission:
+				cmp = a.SubmitTime.Compare(b.SubmitTime)
 			}
-		case persistence.BySubmission:
-			cur = func(a, b entities.LessonRequest) int {
-				if orderType.Ascending {
-					return int(a.SubmitTime.Unix() - b.SubmitTime.Unix())
-				} else {
-					return int(a.SubmitTime.Unix() - b.SubmitTime.Unix())
-				}
+			if !orderType.Ascending {
+				cmp = -cmp
 			}
-		}
-		slices.SortFunc(requests, func(a, b entities.LessonRequest) int {
-			if prev(a, b) == 0 {
-				return cur(a, b)
+			if cmp != 0 {
+				return cmp
 			}
-			return 0
-		})
-	}
+		}
+		return 0
+	})
 
-	query = fmt.Sprintf("UPDATE %s SET order_position=$1 WHERE id=$2", REQUESTS_TABLE)
+	query = fmt.Sprintf("UPDATE %s SET order_position=$1 WHERE id=$2", LESSONS_REQUESTS_TABLE)
 	for i, request := range requests {
 		_, err = tx.ExecContext(ctx, query, i+1, request.Id)
 		if err != nil {
@@ -356,7 +341,7 @@ func (repo *LessonsRequestsRepository) reorderRequestsTx(ctx context.Context, tx
 
 func (repo *LessonsRequestsRepository) GetLabworkQueue(ctx context.Context, labworkId int64) ([]entities.User, error) {
 	query := fmt.Sprintf("SELECT u.id, u.full_name, u.tg_id, u.group_id FROM %s AS l" + 
-	" INNER JOIN %s as u ON u.tg_id=l.user_id WHERE l.lesson_id=$1 AND is_pending=TRUE ORDER BY order_position", 
+	" INNER JOIN %s as u ON u.tg_id=l.user_id WHERE l.lesson_id=$1 AND is_pending=FALSE ORDER BY order_position", 
 	LESSONS_REQUESTS_TABLE, USERS_TABLE)
 	rows, err := repo.db.QueryContext(ctx, query, labworkId)
 	if err != nil {
@@ -374,3 +359,141 @@ func (repo *LessonsRequestsRepository) GetLabworkQueue(ctx context.Context, labw
 	}
 	return users, nil
 }
+
+// GetLabworkQueues is GetLabworkQueue for several labworks at once, keyed by labwork id
+func (repo *LessonsRequestsRepository) GetLabworkQueues(ctx context.Context, labworkIds []int64) (map[int64][]entities.User, error) {
+	queues := make(map[int64][]entities.User, len(labworkIds))
+	if len(labworkIds) == 0 {
+		return queues, nil
+	}
+	placeholders := make([]string, len(labworkIds))
+	args := make([]any, len(labworkIds))
+	for i, id := range labworkIds {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	query := fmt.Sprintf("SELECT l.lesson_id, u.id, u.full_name, u.tg_id, u.group_id FROM %s AS l" +
+	" INNER JOIN %s as u ON u.tg_id=l.user_id WHERE l.lesson_id IN (%s) AND is_pending=FALSE ORDER BY l.lesson_id, order_position",
+	LESSONS_REQUESTS_TABLE, USERS_TABLE, strings.Join(placeholders, ","))
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queues of labworks: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			labworkId int64
+			user      entities.User
+		)
+		err = rows.Scan(&labworkId, &user.Id, &user.FullName, &user.TgId, &user.GroupId)
+		if err != nil {
+			return nil, err
+		}
+		queues[labworkId] = append(queues[labworkId], user)
+	}
+	return queues, rows.Err()
+}
+
+// GetUserRequests returns requests of the user to the upcoming lessons, ordered by date of the lesson
+func (repo *LessonsRequestsRepository) GetUserRequests(ctx context.Context, userTgId int64) ([]entities.UserLessonRequest, error) {
+	query := fmt.Sprintf("SELECT r.id, r.user_id, r.lesson_id, r.msg_id, r.chat_id, r.subgroup_num, r.submit_time, r.is_pending, l.subject, " +
+	"l.subgroup_number, l.date_time, CASE WHEN r.is_pending THEN 0 ELSE (SELECT COUNT(*) FROM %[1]s AS q " + 
+	"WHERE q.lesson_id=r.lesson_id AND q.is_pending=FALSE AND q.order_position<=r.order_position) END " +
+	"FROM %[1]s AS r INNER JOIN %[2]s AS l ON l.id=r.lesson_id WHERE r.user_id=$1 AND l.date_time>=$2 ORDER BY l.date_time, r.id",
+	LESSONS_REQUESTS_TABLE, LESSONS_TABLE)
+	today := time.Now()
+	date := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local).Unix()
+	rows, err := repo.db.QueryContext(ctx, query, userTgId, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requests of user %d: %w", userTgId, err)
+	}
+	defer rows.Close()
+	requests := []entities.UserLessonRequest{}
+	for rows.Next() {
+		var (
+			req            entities.UserLessonRequest
+			storedTime     string
+			storedDateTime int64
+		)
+		err := rows.Scan(&req.Id, &req.UserId, &req.LessonId, &req.MsgId, &req.ChatId, &req.LabworkNumber, &storedTime, &req.IsPending,
+			&req.Subject, &req.SubgroupNumber, &storedDateTime, &req.Position)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan request of user %d: %w", userTgId, err)
+		}
+		req.SubmitTime, _ = time.Parse(savedFormat, storedTime)
+		req.LessonDateTime = time.Unix(storedDateTime, 0)
+		requests = append(requests, req)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return requests, nil
+}
+
+// UpdateRequest moves the request to the lesson and changes its labwork number, keeping the proof and submission time.
+// Queues of both lessons are reordered
+func (repo *LessonsRequestsRepository) UpdateRequest(ctx context.Context, requestId, lessonId int64, labworkNumber int8) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx during update of lesson request: %w", err)
+	}
+	defer tx.Rollback()
+
+	var oldLessonId int64
+	query := fmt.Sprintf("SELECT lesson_id FROM %s WHERE id=$1", LESSONS_REQUESTS_TABLE)
+	err = tx.QueryRowContext(ctx, query, requestId).Scan(&oldLessonId)
+	if err != nil {
+		return fmt.Errorf("failed to get lesson of request %d: %w", requestId, err)
+	}
+	query = fmt.Sprintf("UPDATE %s SET lesson_id=$1, subgroup_num=$2 WHERE id=$3", LESSONS_REQUESTS_TABLE)
+	_, err = tx.ExecContext(ctx, query, lessonId, labworkNumber, requestId)
+	if err != nil {
+		return fmt.Errorf("failed to update lesson request %d: %w", requestId, err)
+	}
+	err = repo.reorderRequestsTx(ctx, tx, lessonId)
+	if err != nil {
+		return err
+	}
+	if oldLessonId != lessonId {
+		err = repo.reorderRequestsTx(ctx, tx, oldLessonId)
+		if err != nil {
+			return err
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit update of lesson request: %w", err)
+	}
+	return nil
+}
+
+// GetLessonQueue returns accepted requests of the lesson in order of the queue together with their outcomes, marked during the lesson
+func (repo *LessonsRequestsRepository) GetLessonQueue(ctx context.Context, lessonId int64) ([]entities.QueuedRequest, error) {
+	query := fmt.Sprintf("SELECT r.id, r.user_id, r.lesson_id, r.msg_id, r.chat_id, r.subgroup_num, r.submit_time, " +
+	"COALESCE(o.outcome, 0), u.full_name FROM %s AS r INNER JOIN %s AS u ON u.tg_id=r.user_id " +
+	"LEFT JOIN %s AS o ON o.request_id=r.id AND o.lesson_id=r.lesson_id WHERE r.lesson_id=$1 AND r.is_pending=FALSE " +
+	"ORDER BY r.order_position", LESSONS_REQUESTS_TABLE, USERS_TABLE, LABWORK_OUTCOMES_TABLE)
+	rows, err := repo.db.QueryContext(ctx, query, lessonId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue of lesson %d: %w", lessonId, err)
+	}
+	defer rows.Close()
+	requests := []entities.QueuedRequest{}
+	for rows.Next() {
+		var (
+			req        entities.QueuedRequest
+			storedTime string
+		)
+		err := rows.Scan(&req.Id, &req.UserId, &req.LessonId, &req.MsgId, &req.ChatId, &req.LabworkNumber, &storedTime, &req.Outcome,
+			&req.FullName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan request of lesson %d: %w", lessonId, err)
+		}
+		req.SubmitTime, _ = time.Parse(savedFormat, storedTime)
+		requests = append(requests, req)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return requests, nil
+}
@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+const MIGRATIONS_TABLE = "schema_migrations"
+
+type migration struct {
+	version int
+	query   string
+}
+
+// Migrations are applied after db_setup.sql, which creates the initial schema. Tables can be created in db_setup.sql,
+// but changes of the existing ones must be done here, so that they are also applied to already existing databases
+var migrations = []migration{
+	{
+		version: 1,
+		query: `ALTER TABLE states ADD COLUMN updated_at INTEGER NOT NULL DEFAULT 0;
+		UPDATE states SET updated_at = CAST(strftime('%s', 'now') AS INTEGER);`,
+	},
+	{
+		// States are scoped by users of the chats for group chats. Ids of private chats are equal to ids of their users
+		version: 2,
+		query: `ALTER TABLE states ADD COLUMN user_id INTEGER NOT NULL DEFAULT 0;
+		UPDATE states SET user_id = chat_id;
+		DROP INDEX IF EXISTS states_chat_id_idx;
+		CREATE UNIQUE INDEX states_chat_user_idx ON states(chat_id, user_id);
+		ALTER TABLE info ADD COLUMN user_id INTEGER NOT NULL DEFAULT 0;
+		UPDATE info SET user_id = chat_id;
+		DROP INDEX IF EXISTS info_chat_id_idx;
+		CREATE UNIQUE INDEX info_chat_user_idx ON info(chat_id, user_id);
+		ALTER TABLE flow_markups ADD COLUMN user_id INTEGER NOT NULL DEFAULT 0;
+		UPDATE flow_markups SET user_id = chat_id;`,
+	},
+	{
+		// Empty language means, that user hasn't chosen one, so language of the telegram client is used
+		version: 3,
+		query:   `ALTER TABLE users ADD COLUMN language TEXT NOT NULL DEFAULT '';`,
+	},
+	{
+		// Students are notified about their turn in the queue, unless they turn it off in settings
+		version: 4,
+		query:   `ALTER TABLE users ADD COLUMN turn_notifications INTEGER NOT NULL DEFAULT 1;`,
+	},
+}
+
+func migrate(ctx context.Context, db *sql.DB) error {
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version INTEGER PRIMARY KEY)", MIGRATIONS_TABLE)
+	_, err := db.ExecContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+	currentVersion := 0
+	query = fmt.Sprintf("SELECT COALESCE(MAX(version), 0) FROM %s", MIGRATIONS_TABLE)
+	err = db.QueryRowContext(ctx, query).Scan(&currentVersion)
+	if err != nil {
+		return fmt.Errorf("failed to get current schema version: %w", err)
+	}
+	for _, migration := range migrations {
+		if migration.version <= currentVersion {
+			continue
+		}
+		err := applyMigration(ctx, db, migration)
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %d: %w", migration.version, err)
+		}
+	}
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, migration migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx, migration.query)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("INSERT INTO %s (version) VALUES ($1)", MIGRATIONS_TABLE)
+	_, err = tx.ExecContext(ctx, query, migration.version)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
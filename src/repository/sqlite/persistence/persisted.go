@@ -51,7 +51,7 @@ func FromLessonEntity(lesson *iisEntities.Lesson, date time.Time) *Lesson {
 type OrderField = int8
 
 const (
-	BySubmission OrderField = iota
+	BySubmission OrderField = iota + 1
 	ByLabworkNumber
 )
 
@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
+)
+
+const (
+	PROCESSED_UPDATES_TABLE   = "processed_updates"
+	PROCESSED_CALLBACKS_TABLE = "processed_callbacks"
+)
+
+var _ interfaces.ProcessedUpdatesRepository = (*ProcessedUpdatesRepository)(nil)
+
+type ProcessedUpdatesRepository struct {
+	db *sql.DB
+}
+
+func NewProcessedUpdatesRepository(db *sql.DB) *ProcessedUpdatesRepository {
+	return &ProcessedUpdatesRepository{db: db}
+}
+
+func (repo *ProcessedUpdatesRepository) MarkUpdate(ctx context.Context, updateId int, callbackId string) (bool, error) {
+	query := fmt.Sprintf("INSERT OR IGNORE INTO %s (update_id, callback_id, processed_time) VALUES ($1, $2, $3)", PROCESSED_UPDATES_TABLE)
+	res, err := repo.db.ExecContext(ctx, query, updateId, sql.NullString{String: callbackId, Valid: callbackId != ""}, time.Now().Unix())
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected != 0, nil
+}
+
+func (repo *ProcessedUpdatesRepository) FinishUpdate(ctx context.Context, updateId int) error {
+	query := fmt.Sprintf("UPDATE %s SET finished=TRUE WHERE update_id=$1", PROCESSED_UPDATES_TABLE)
+	_, err := repo.db.ExecContext(ctx, query, updateId)
+	return err
+}
+
+func (repo *ProcessedUpdatesRepository) UnmarkUpdate(ctx context.Context, updateId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE update_id=$1", PROCESSED_UPDATES_TABLE)
+	_, err := repo.db.ExecContext(ctx, query, updateId)
+	return err
+}
+
+func (repo *ProcessedUpdatesRepository) UnmarkUnfinished(ctx context.Context) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE finished=FALSE", PROCESSED_UPDATES_TABLE)
+	_, err := repo.db.ExecContext(ctx, query)
+	return err
+}
+
+func (repo *ProcessedUpdatesRepository) LastUpdateId(ctx context.Context) (int, error) {
+	query := fmt.Sprintf("SELECT COALESCE(MAX(update_id), 0) FROM %s", PROCESSED_UPDATES_TABLE)
+	updateId := 0
+	err := repo.db.QueryRowContext(ctx, query).Scan(&updateId)
+	if err != nil {
+		return 0, err
+	}
+	return updateId, nil
+}
+
+func (repo *ProcessedUpdatesRepository) MarkCallback(ctx context.Context, key string) (bool, error) {
+	query := fmt.Sprintf("INSERT OR IGNORE INTO %s (callback_key, processed_time) VALUES ($1, $2)", PROCESSED_CALLBACKS_TABLE)
+	res, err := repo.db.ExecContext(ctx, query, key, time.Now().Unix())
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected != 0, nil
+}
+
+func (repo *ProcessedUpdatesRepository) UnmarkCallback(ctx context.Context, key string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE callback_key=$1", PROCESSED_CALLBACKS_TABLE)
+	_, err := repo.db.ExecContext(ctx, query, key)
+	return err
+}
+
+// DeleteBefore removes marks of old updates. The last update is always kept, so that offset of updates is preserved
+func (repo *ProcessedUpdatesRepository) DeleteBefore(ctx context.Context, before time.Time) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	query := fmt.Sprintf("DELETE FROM %[1]s WHERE processed_time<$1 AND update_id<(SELECT MAX(update_id) FROM %[1]s)", PROCESSED_UPDATES_TABLE)
+	_, err = tx.ExecContext(ctx, query, before.Unix())
+	if err != nil {
+		return err
+	}
+	query = fmt.Sprintf("DELETE FROM %s WHERE processed_time<$1", PROCESSED_CALLBACKS_TABLE)
+	_, err = tx.ExecContext(ctx, query, before.Unix())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
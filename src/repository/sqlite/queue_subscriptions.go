@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+)
+
+const QUEUE_SUBSCRIPTIONS_TABLE = "queue_subscriptions"
+
+type QueueSubscriptionsRepository struct {
+	db *sql.DB
+}
+
+func NewQueueSubscriptionsRepository(db *sql.DB) *QueueSubscriptionsRepository {
+	return &QueueSubscriptionsRepository{db: db}
+}
+
+func (repo *QueueSubscriptionsRepository) Add(ctx context.Context, subscription *entities.QueueSubscription) error {
+	query := fmt.Sprintf("INSERT OR REPLACE INTO %s (chat_id, msg_id, lesson_id, pinned, text) VALUES ($1, $2, $3, $4, $5)",
+		QUEUE_SUBSCRIPTIONS_TABLE)
+	_, err := repo.db.ExecContext(ctx, query, subscription.ChatId, subscription.MsgId, subscription.LessonId, subscription.Pinned,
+		subscription.Text)
+	if err != nil {
+		return fmt.Errorf("failed to add queue subscription: %w", err)
+	}
+	return nil
+}
+
+// Get returns nil, if the message isn't subscribed
+func (repo *QueueSubscriptionsRepository) Get(ctx context.Context, chatId int64, msgId int) (*entities.QueueSubscription, error) {
+	query := fmt.Sprintf("SELECT chat_id, msg_id, lesson_id, pinned, text FROM %s WHERE chat_id=$1 AND msg_id=$2", QUEUE_SUBSCRIPTIONS_TABLE)
+	subscription := &entities.QueueSubscription{}
+	err := repo.db.QueryRowContext(ctx, query, chatId, msgId).Scan(&subscription.ChatId, &subscription.MsgId, &subscription.LessonId,
+		&subscription.Pinned, &subscription.Text)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+func (repo *QueueSubscriptionsRepository) GetByLesson(ctx context.Context, lessonId int64) ([]entities.QueueSubscription, error) {
+	query := fmt.Sprintf("SELECT chat_id, msg_id, lesson_id, pinned, text FROM %s WHERE lesson_id=$1", QUEUE_SUBSCRIPTIONS_TABLE)
+	rows, err := repo.db.QueryContext(ctx, query, lessonId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue subscriptions of lesson: %w", err)
+	}
+	defer rows.Close()
+	subscriptions := []entities.QueueSubscription{}
+	for rows.Next() {
+		subscription := entities.QueueSubscription{}
+		err := rows.Scan(&subscription.ChatId, &subscription.MsgId, &subscription.LessonId, &subscription.Pinned, &subscription.Text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan queue subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, rows.Err()
+}
+
+func (repo *QueueSubscriptionsRepository) UpdateText(ctx context.Context, chatId int64, msgId int, text string) error {
+	query := fmt.Sprintf("UPDATE %s SET text=$1 WHERE chat_id=$2 AND msg_id=$3", QUEUE_SUBSCRIPTIONS_TABLE)
+	_, err := repo.db.ExecContext(ctx, query, text, chatId, msgId)
+	if err != nil {
+		return fmt.Errorf("failed to update text of queue subscription: %w", err)
+	}
+	return nil
+}
+
+func (repo *QueueSubscriptionsRepository) Delete(ctx context.Context, chatId int64, msgId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE chat_id=$1 AND msg_id=$2", QUEUE_SUBSCRIPTIONS_TABLE)
+	_, err := repo.db.ExecContext(ctx, query, chatId, msgId)
+	if err != nil {
+		return fmt.Errorf("failed to delete queue subscription: %w", err)
+	}
+	return nil
+}
@@ -90,31 +90,31 @@ func (repo *UsersRepository) GetByRequestId(ctx context.Context, requestId int64
 
 func (repo *UsersRepository) GetAll(ctx context.Context) ([]entities.User, error) {
 	query := fmt.Sprintf("SELECT %[1]s.id, %[1]s.tg_id, %[1]s.group_id, %[1]s.full_name, %[2]s.role_name FROM %[1]s " +  
-	"INNER JOIN %[2]s ON %[1]s.id = %[2]s.user_id", USERS_TABLE, ROLES_TABLE)
+	"INNER JOIN %[2]s ON %[1]s.id = %[2]s.user_id ORDER BY %[1]s.id", USERS_TABLE, ROLES_TABLE)
 	rows, err := repo.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	users := make([]entities.User, 0)
-
-	user := &entities.User{}
-	var (
-		previousId = int64(0)
-		curId      = previousId
-	)
+	// Every role of the user is a separate row, so rows of the same user are merged
 	for rows.Next() {
-		var roleName string
-		rows.Scan(&user.Id, &user.TgId, &user.GroupId, &user.FullName, &roleName)
-		user.Roles = append(user.Roles, entities.RoleFromString(roleName))
-		if previousId != curId {
-			users = append(users, *user)
+		var (
+			user     entities.User
+			roleName string
+		)
+		err = rows.Scan(&user.Id, &user.TgId, &user.GroupId, &user.FullName, &roleName)
+		if err != nil {
+			return nil, err
+		}
+		if len(users) == 0 || users[len(users)-1].Id != user.Id {
+			users = append(users, user)
 		}
-		previousId = curId
-		curId = user.Id
+		last := &users[len(users)-1]
+		last.Roles = append(last.Roles, entities.RoleFromString(roleName))
 	}
 	if rows.Err() != nil {
-		return nil, err
+		return nil, rows.Err()
 	}
 	return users, nil
 }
@@ -217,6 +217,56 @@ func (repo *UsersRepository) Delete(ctx context.Context, id int64) error {
 	return err
 }
 
+// GetLanguage returns language, chosen by the user, or empty string, if user hasn't chosen one or isn't registered
+func (repo *UsersRepository) GetLanguage(ctx context.Context, tgId int64) (string, error) {
+	query := fmt.Sprintf("SELECT language FROM %s WHERE tg_id=$1", USERS_TABLE)
+	language := ""
+	err := repo.db.QueryRowContext(ctx, query, tgId).Scan(&language)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	return language, nil
+}
+
+// SetLanguage saves language of the user. Returns false, if user isn't registered
+func (repo *UsersRepository) SetLanguage(ctx context.Context, tgId int64, language string) (bool, error) {
+	query := fmt.Sprintf("UPDATE %s SET language=$1 WHERE tg_id=$2", USERS_TABLE)
+	res, err := repo.db.ExecContext(ctx, query, language, tgId)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected != 0, nil
+}
+
+// GetTurnNotifications returns, whether the user wants to be notified about their turn in queues. Unregistered users aren't notified
+func (repo *UsersRepository) GetTurnNotifications(ctx context.Context, tgId int64) (bool, error) {
+	query := fmt.Sprintf("SELECT turn_notifications FROM %s WHERE tg_id=$1", USERS_TABLE)
+	enabled := false
+	err := repo.db.QueryRowContext(ctx, query, tgId).Scan(&enabled)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+	return enabled, nil
+}
+
+// SetTurnNotifications turns notifications about the turn of the user on or off. Returns false, if user isn't registered
+func (repo *UsersRepository) SetTurnNotifications(ctx context.Context, tgId int64, enabled bool) (bool, error) {
+	query := fmt.Sprintf("UPDATE %s SET turn_notifications=$1 WHERE tg_id=$2", USERS_TABLE)
+	res, err := repo.db.ExecContext(ctx, query, enabled, tgId)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected != 0, nil
+}
+
 func (repo *UsersRepository) GetStudents(ctx context.Context, groupname string) ([]entities.User, error) {
 	query := fmt.Sprintf("SELECT u.id, u.tg_id, u.group_id, u.full_name FROM %s as u INNER JOIN %s as g ON g.id=u.group_id WHERE g.name=$1", USERS_TABLE, GROUPS_TABLE)
 	rows, err := repo.db.QueryContext(ctx, query, groupname)
@@ -2,26 +2,43 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
-	"sync"
+	"time"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/logging"
 	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
+const (
+	stopTimeout               = 10 * time.Second
+	processedUpdatesRetention = 30 * 24 * time.Hour
+)
+
 type BotController struct {
 	bot         *tgutils.Bot
 	msgSrv      MessagesService
 	callbackSrv CallbacksService
+	inlineSrv   InlineQueriesService
+	dispatcher  *Dispatcher
+	source      UpdatesSource
+	processed   ProcessedUpdatesRepository
 }
 
-func NewBotController(bot *tgutils.Bot, msgSrv MessagesService, callbackSrv CallbacksService) (*BotController, error) {
+func NewBotController(bot *tgutils.Bot, msgSrv MessagesService, callbackSrv CallbacksService, inlineSrv InlineQueriesService,
+	dispatcher *Dispatcher,
+	source UpdatesSource, processed ProcessedUpdatesRepository) (*BotController, error) {
 	bc := &BotController{
 		bot:         bot,
 		msgSrv:      msgSrv,
 		callbackSrv: callbackSrv,
+		inlineSrv:   inlineSrv,
+		dispatcher:  dispatcher,
+		source:      source,
+		processed:   processed,
 	}
 	return bc, nil
 }
@@ -32,32 +49,94 @@ func (controller *BotController) Server() http.Client {
 
 func (controller *BotController) Start(ctx context.Context) {
 	logging.Info(fmt.Sprintf("authorized on account %s", controller.bot.Self.UserName))
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 600
-	u.AllowedUpdates = []string{"message", "callback_query"}
-
-	updates := controller.bot.GetUpdatesChan(u)
-
-	wg := sync.WaitGroup{}
-	select {
-	case <-ctx.Done():
-		controller.bot.StopReceivingUpdates()
-		wg.Wait()
-	default:
-		for update := range updates {
-			if update.Message != nil {
-				wg.Add(1)
-				go func(*sync.WaitGroup) {
-					controller.msgSrv.HandleMessages(&update, controller.bot)
-					wg.Done()
-				}(&wg)
-			} else if update.CallbackQuery != nil {
-				wg.Add(1)
-				go func(*sync.WaitGroup) {
-					controller.callbackSrv.HandleCallbacks(&update, controller.bot)
-					wg.Done()
-				}(&wg)
+	err := controller.processed.DeleteBefore(ctx, time.Now().Add(-processedUpdatesRetention))
+	if err != nil {
+		logging.Error(fmt.Sprintf("failed to delete old processed updates: %s", err.Error()))
+	}
+	// Handling of these updates was interrupted by the previous stop of the bot, so they are handled again, if telegram delivers them
+	err = controller.processed.UnmarkUnfinished(ctx)
+	if err != nil {
+		logging.Error(fmt.Sprintf("failed to unmark unfinished updates: %s", err.Error()))
+	}
+	updates, err := controller.source.Updates(ctx)
+	// Bot, stopped while starting, e.g. by a signal, just doesn't start
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		logging.FatalLog(fmt.Sprintf("failed to start receiving updates: %s", err.Error()))
+	}
+
+	controller.dispatcher.Start()
+	defer controller.dispatcher.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			stopCtx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+			defer cancel()
+			err := controller.source.Stop(stopCtx)
+			if err != nil {
+				logging.Error(fmt.Sprintf("failed to stop receiving updates: %s", err.Error()))
+			}
+			return
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			err := controller.dispatch(ctx, update)
+			if err != nil {
+				logging.Error(fmt.Sprintf("failed to dispatch update %d: %s", update.UpdateID, err.Error()))
 			}
 		}
 	}
 }
+
+// dispatch marks update before handling, so that update is applied at most once, even if it is delivered again
+// (e.g. webhook retry or restart of the bot). Marks of failed updates are removed, so that redelivered ones are handled again
+func (controller *BotController) dispatch(ctx context.Context, update tgbotapi.Update) error {
+	if update.Message == nil && update.CallbackQuery == nil && update.InlineQuery == nil {
+		return nil
+	}
+	callbackId := ""
+	if update.CallbackQuery != nil {
+		callbackId = update.CallbackQuery.ID
+	}
+	marked, err := controller.processed.MarkUpdate(ctx, update.UpdateID, callbackId)
+	if err != nil {
+		return fmt.Errorf("failed to mark update as processed: %w", err)
+	}
+	if !marked {
+		slog.Debug("skipped already processed update", "update_id", update.UpdateID)
+		return nil
+	}
+	var handle func() error
+	if update.Message != nil {
+		handle = func() error { return controller.msgSrv.HandleMessages(&update, controller.bot) }
+	} else if update.CallbackQuery != nil {
+		handle = func() error { return controller.callbackSrv.HandleCallbacks(&update, controller.bot) }
+	} else {
+		handle = func() error { return controller.inlineSrv.HandleInlineQueries(&update, controller.bot) }
+	}
+	err = controller.dispatcher.Dispatch(ctx, UpdateKey(&update), func() {
+		controller.finish(update.UpdateID, handle())
+	})
+	if err != nil {
+		return errors.Join(err, controller.processed.UnmarkUpdate(context.WithoutCancel(ctx), update.UpdateID))
+	}
+	return nil
+}
+
+// finish marks handled update as finished or removes its mark, if handling failed
+func (controller *BotController) finish(updateId int, handleErr error) {
+	ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+	defer cancel()
+	var err error
+	if handleErr == nil {
+		err = controller.processed.FinishUpdate(ctx, updateId)
+	} else {
+		err = controller.processed.UnmarkUpdate(ctx, updateId)
+	}
+	if err != nil {
+		logging.Error(fmt.Sprintf("failed to finish update %d: %s", updateId, err.Error()))
+	}
+}
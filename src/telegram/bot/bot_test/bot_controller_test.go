@@ -0,0 +1,146 @@
+package bottest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/bot"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const stepTimeout = 5 * time.Second
+
+type channelSource struct {
+	updates chan tgbotapi.Update
+}
+
+func (source *channelSource) Updates(ctx context.Context) (tgbotapi.UpdatesChannel, error) {
+	return source.updates, nil
+}
+
+func (source *channelSource) Stop(ctx context.Context) error {
+	return nil
+}
+
+// flakyMessages fails handling of the first updates, e.g. while google is down
+type flakyMessages struct {
+	mu       sync.Mutex
+	failures int
+	attempts int
+	handled  []int
+}
+
+func (messages *flakyMessages) HandleMessages(update *tgbotapi.Update, bot *tgutils.Bot) error {
+	messages.mu.Lock()
+	defer messages.mu.Unlock()
+	messages.attempts++
+	if messages.failures > 0 {
+		messages.failures--
+		return errors.New("google is down")
+	}
+	messages.handled = append(messages.handled, update.UpdateID)
+	return nil
+}
+
+func (messages *flakyMessages) state() (int, []int) {
+	messages.mu.Lock()
+	defer messages.mu.Unlock()
+	return messages.attempts, slices.Clone(messages.handled)
+}
+
+type noCallbacks struct{}
+
+func (noCallbacks) HandleCallbacks(update *tgbotapi.Update, bot *tgutils.Bot) error {
+	return nil
+}
+
+type noInlineQueries struct{}
+
+func (noInlineQueries) HandleInlineQueries(update *tgbotapi.Update, bot *tgutils.Bot) error {
+	return nil
+}
+
+func openProcessedUpdates(t *testing.T) *sqlite.ProcessedUpdatesRepository {
+	_, file, _, _ := runtime.Caller(0)
+	t.Setenv("SQLITE_INIT_FILE", filepath.Join(filepath.Dir(file), "..", "..", "..", "..", "sql", "db_setup.sql"))
+	db, err := sql.Open("sqlite3", sqlite.Dsn(filepath.Join(t.TempDir(), "bot.db")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	err = sqlite.DatabaseInit(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sqlite.NewProcessedUpdatesRepository(db)
+}
+
+func eventually(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(stepTimeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("%s didn't happen in %s", what, stepTimeout)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func message(updateId int) tgbotapi.Update {
+	return tgbotapi.Update{UpdateID: updateId, Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: 200}, Text: "2"}}
+}
+
+func TestFailedUpdateIsHandledAgain(t *testing.T) {
+	processed := openProcessedUpdates(t)
+	// Handling of update 1 was interrupted by the previous stop of the bot
+	_, err := processed.MarkUpdate(context.Background(), 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := &flakyMessages{failures: 1}
+	source := &channelSource{updates: make(chan tgbotapi.Update)}
+	controller, err := bot.NewBotController(tgutils.NewBot(&tgbotapi.BotAPI{}), messages, noCallbacks{}, noInlineQueries{},
+		bot.NewDispatcher(1, 1), source, processed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		controller.Start(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	source.updates <- message(2)
+	eventually(t, "unmark of failed update", func() bool {
+		attempts, _ := messages.state()
+		lastUpdateId, err := processed.LastUpdateId(context.Background())
+		return attempts == 1 && err == nil && lastUpdateId == 0
+	})
+	source.updates <- message(2)
+	source.updates <- message(1)
+	// Finished update isn't handled again
+	source.updates <- message(2)
+	source.updates <- message(3)
+	eventually(t, "handling of the last update", func() bool {
+		_, handled := messages.state()
+		return slices.Contains(handled, 3)
+	})
+	if _, handled := messages.state(); !slices.Equal(handled, []int{2, 1, 3}) {
+		t.Errorf("handled updates %v, want failed and interrupted ones handled again once", handled)
+	}
+}
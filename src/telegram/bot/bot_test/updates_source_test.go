@@ -0,0 +1,65 @@
+package bottest
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/bot"
+	fakeapi "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/fake_api"
+)
+
+const webhookSecret = "secret"
+
+func freePort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestWebhookAcceptsOnlyPost(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+	tgBot, err := server.NewBot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := bot.WebhookConfig{Url: "https://example.com/webhook", Path: "/webhook", Port: freePort(t), Secret: webhookSecret}
+	source := bot.NewWebhookSource(tgBot, config)
+	_, err = source.Updates(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer source.Stop(context.Background())
+
+	url := "http://" + net.JoinHostPort("127.0.0.1", strconv.Itoa(config.Port)) + config.Path
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		req, err := http.NewRequest(method, url, strings.NewReader(`{"update_id":1}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("X-Telegram-Bot-Api-Secret-Token", webhookSecret)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != http.MethodPost {
+			t.Errorf("%s got status %d with allow %q, want 405 allowing POST", method, resp.StatusCode, resp.Header.Get("Allow"))
+		}
+	}
+	resp, err := http.Post(url, "application/json", strings.NewReader(`{"update_id":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("POST without secret got status %d, want 401", resp.StatusCode)
+	}
+}
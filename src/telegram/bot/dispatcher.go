@@ -0,0 +1,89 @@
+package bot
+
+import (
+	"context"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	DEFAULT_DISPATCHER_WORKERS    = 16
+	DEFAULT_DISPATCHER_QUEUE_SIZE = 64
+)
+
+// Dispatcher distributes updates between fixed amount of workers, sharding them by chat.
+// Updates of the same chat are always handled by the same worker, so they are processed one by one in order of receiving,
+// while updates of different chats are processed concurrently. When the queue of the worker is full, Dispatch blocks
+type Dispatcher struct {
+	queues []chan func()
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+func NewDispatcher(workers, queueSize int) *Dispatcher {
+	if workers <= 0 {
+		workers = DEFAULT_DISPATCHER_WORKERS
+	}
+	if queueSize <= 0 {
+		queueSize = DEFAULT_DISPATCHER_QUEUE_SIZE
+	}
+	queues := make([]chan func(), workers)
+	for i := range queues {
+		queues[i] = make(chan func(), queueSize)
+	}
+	return &Dispatcher{queues: queues}
+}
+
+func (dispatcher *Dispatcher) Start() {
+	for _, queue := range dispatcher.queues {
+		dispatcher.wg.Add(1)
+		go func(queue chan func()) {
+			defer dispatcher.wg.Done()
+			for job := range queue {
+				job()
+			}
+		}(queue)
+	}
+}
+
+// Dispatch enqueues job to the worker of the given key, waiting for free space in queue, unless context is done
+func (dispatcher *Dispatcher) Dispatch(ctx context.Context, key int64, job func()) error {
+	queue := dispatcher.queues[dispatcher.shard(key)]
+	select {
+	case queue <- job:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stop stops accepting new jobs and waits for already enqueued ones to finish
+func (dispatcher *Dispatcher) Stop() {
+	dispatcher.once.Do(func() {
+		for _, queue := range dispatcher.queues {
+			close(queue)
+		}
+	})
+	dispatcher.wg.Wait()
+}
+
+func (dispatcher *Dispatcher) shard(key int64) int {
+	shard := key % int64(len(dispatcher.queues))
+	if shard < 0 {
+		shard = -shard
+	}
+	return int(shard)
+}
+
+// UpdateKey returns the id of the chat, which update belongs to, so that updates of one chat are serialized.
+// Callbacks of inline messages have no chat, so they are serialized by the user
+func UpdateKey(update *tgbotapi.Update) int64 {
+	if chat := update.FromChat(); chat != nil {
+		return chat.ID
+	}
+	if user := update.SentFrom(); user != nil {
+		return user.ID
+	}
+	return 0
+}
@@ -1,14 +1,31 @@
 package bot
 
 import (
+	"context"
+	"time"
+
 	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
+// Services return error, if handling of the update failed, after they have reported it
 type MessagesService interface {
-	HandleMessages(update *tgbotapi.Update, bot *tgutils.Bot)
+	HandleMessages(update *tgbotapi.Update, bot *tgutils.Bot) error
 }
 
 type CallbacksService interface {
-	HandleCallbacks(update *tgbotapi.Update, bot *tgutils.Bot)
+	HandleCallbacks(update *tgbotapi.Update, bot *tgutils.Bot) error
+}
+
+type InlineQueriesService interface {
+	HandleInlineQueries(update *tgbotapi.Update, bot *tgutils.Bot) error
+}
+
+type ProcessedUpdatesRepository interface {
+	MarkUpdate(ctx context.Context, updateId int, callbackId string) (bool, error)
+	FinishUpdate(ctx context.Context, updateId int) error
+	UnmarkUpdate(ctx context.Context, updateId int) error
+	UnmarkUnfinished(ctx context.Context) error
+	LastUpdateId(ctx context.Context) (int, error)
+	DeleteBefore(ctx context.Context, before time.Time) error
 }
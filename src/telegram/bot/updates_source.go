@@ -0,0 +1,163 @@
+package bot
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/logging"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	POLLING_MODE = "polling"
+	WEBHOOK_MODE = "webhook"
+)
+
+var allowedUpdates = []string{"message", "callback_query", "inline_query"}
+
+// UpdatesSource delivers updates from telegram. Returned channel may be closed, if source can't deliver updates anymore
+type UpdatesSource interface {
+	Updates(ctx context.Context) (tgbotapi.UpdatesChannel, error)
+	Stop(ctx context.Context) error
+}
+
+type pollingSource struct {
+	bot       *tgutils.Bot
+	processed ProcessedUpdatesRepository
+}
+
+func NewPollingSource(bot *tgutils.Bot, processed ProcessedUpdatesRepository) *pollingSource {
+	return &pollingSource{bot: bot, processed: processed}
+}
+
+func (source *pollingSource) Updates(ctx context.Context) (tgbotapi.UpdatesChannel, error) {
+	// getUpdates doesn't work while webhook is set, so it may be left from the previous deploy in webhook mode
+	_, err := source.bot.RequestCtx(ctx, tgbotapi.DeleteWebhookConfig{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete webhook before polling: %w", err)
+	}
+	// Offset confirms all of the previous updates, so they are not delivered again after restart
+	lastUpdateId, err := source.processed.LastUpdateId(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last update id before polling: %w", err)
+	}
+	u := tgbotapi.NewUpdate(lastUpdateId + 1)
+	u.Timeout = 600
+	u.AllowedUpdates = allowedUpdates
+	return source.bot.GetUpdatesChan(u), nil
+}
+
+func (source *pollingSource) Stop(ctx context.Context) error {
+	source.bot.StopReceivingUpdates()
+	return nil
+}
+
+type WebhookConfig struct {
+	// Public url, which telegram sends updates to. Path of the url should match the Path, unless reverse proxy rewrites it
+	Url    string
+	Path   string
+	Port   int
+	Secret string
+}
+
+const webhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+type webhookSource struct {
+	bot     *tgutils.Bot
+	config  WebhookConfig
+	server  *http.Server
+	updates chan tgbotapi.Update
+	done    chan struct{}
+}
+
+func NewWebhookSource(bot *tgutils.Bot, config WebhookConfig) *webhookSource {
+	return &webhookSource{bot: bot, config: config}
+}
+
+func (source *webhookSource) Updates(ctx context.Context) (tgbotapi.UpdatesChannel, error) {
+	source.updates = make(chan tgbotapi.Update)
+	source.done = make(chan struct{})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(source.config.Path, source.handleUpdate)
+	source.server = &http.Server{
+		Addr:              net.JoinHostPort("", strconv.Itoa(source.config.Port)),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	listener, err := net.Listen("tcp", source.server.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for webhook on %s: %w", source.server.Addr, err)
+	}
+	go func() {
+		err := source.server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Error(fmt.Sprintf("webhook server stopped: %s", err.Error()))
+		}
+	}()
+
+	err = source.setWebhook()
+	if err != nil {
+		return nil, errors.Join(err, source.server.Close())
+	}
+	logging.Info(fmt.Sprintf("listening for webhook updates on %s%s", source.server.Addr, source.config.Path))
+	return source.updates, nil
+}
+
+func (source *webhookSource) setWebhook() error {
+	params := tgbotapi.Params{}
+	params.AddNonEmpty("url", source.config.Url)
+	params.AddNonEmpty("secret_token", source.config.Secret)
+	err := params.AddInterface("allowed_updates", allowedUpdates)
+	if err != nil {
+		return fmt.Errorf("failed to create set webhook params: %w", err)
+	}
+	_, err = source.bot.MakeRequest("setWebhook", params)
+	if err != nil {
+		return fmt.Errorf("failed to set webhook: %w", err)
+	}
+	return nil
+}
+
+func (source *webhookSource) handleUpdate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	secret := r.Header.Get(webhookSecretHeader)
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(source.config.Secret)) != 1 {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	update, err := source.bot.HandleUpdate(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	// Telegram waits for response before sending next updates, so blocking here passes backpressure of dispatcher to it
+	select {
+	case source.updates <- *update:
+		w.WriteHeader(http.StatusOK)
+	case <-source.done:
+		w.WriteHeader(http.StatusServiceUnavailable)
+	case <-r.Context().Done():
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+}
+
+// Stop shuts down the server, but leaves the webhook set, so that updates are queued by telegram until the next start
+func (source *webhookSource) Stop(ctx context.Context) error {
+	if source.server == nil {
+		return nil
+	}
+	close(source.done)
+	return source.server.Shutdown(ctx)
+}
@@ -0,0 +1,471 @@
+package fakeapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	TOKEN = "123456:fake-token"
+	// Max time, which getUpdates waits for updates, so that polling of the bot notices shutdown quickly
+	maxPollTimeout = time.Second
+	maxUploadSize  = 32 << 20
+	maxTextLength  = 4096
+)
+
+// Server is in-process fake of telegram Bot API. It delivers updates, pushed by tests, through getUpdates
+// and records requests of the bot, answering them like telegram does
+type Server struct {
+	*httptest.Server
+	Bot tgbotapi.User
+
+	mu sync.Mutex
+	// changed is closed and replaced, when updates are pushed or requests are recorded
+	changed        chan struct{}
+	closed         bool
+	updates        []tgbotapi.Update
+	calls          []*Call
+	files          map[string][]byte
+	lastUpdateId   int
+	lastMessageId  int
+	lastCallbackId int
+}
+
+func NewServer() *Server {
+	server := &Server{
+		Bot:     tgbotapi.User{ID: 1, IsBot: true, FirstName: "Queue", UserName: "fake_queue_bot"},
+		changed: make(chan struct{}),
+		files:   map[string][]byte{},
+	}
+	server.Server = httptest.NewServer(server)
+	return server
+}
+
+// NewBot returns bot, which sends requests to the server and downloads files from it
+func (server *Server) NewBot(opts ...func(*tgutils.Bot)) (*tgutils.Bot, error) {
+	api, err := tgbotapi.NewBotAPIWithAPIEndpoint(TOKEN, tgutils.APIEndpoint(server.URL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to fake bot api server: %w", err)
+	}
+	return tgutils.NewBot(api, append(opts, tgutils.WithFileEndpoint(tgutils.FileEndpoint(server.URL)))...), nil
+}
+
+// Close wakes up pending getUpdates requests, as httptest.Server waits for them to finish
+func (server *Server) Close() {
+	server.mu.Lock()
+	server.closed = true
+	server.notify()
+	server.mu.Unlock()
+	server.Server.Close()
+}
+
+// notify must be called with mu held
+func (server *Server) notify() {
+	close(server.changed)
+	server.changed = make(chan struct{})
+}
+
+// Call is a request of the bot to the server
+type Call struct {
+	Method string
+	Params map[string]string
+	Files  map[string][]byte
+	// Result is the message, returned to the bot, if the method sends or edits messages
+	Result *tgbotapi.Message
+
+	waited bool
+}
+
+func (call *Call) ChatId() int64 {
+	chatId, _ := strconv.ParseInt(call.Params["chat_id"], 10, 64)
+	return chatId
+}
+
+// Text returns text of the message or caption of the media
+func (call *Call) Text() string {
+	if text, ok := call.Params["text"]; ok {
+		return text
+	}
+	return call.Params["caption"]
+}
+
+// Markup returns inline keyboard of the message, which is empty, if there is none
+func (call *Call) Markup() tgbotapi.InlineKeyboardMarkup {
+	markup := tgbotapi.InlineKeyboardMarkup{}
+	if data, ok := call.Params["reply_markup"]; ok {
+		_ = json.Unmarshal([]byte(data), &markup)
+	}
+	return markup
+}
+
+// Button returns callback data of the inline button with given text
+func (call *Call) Button(text string) (string, bool) {
+	for _, row := range call.Markup().InlineKeyboard {
+		for _, button := range row {
+			if button.Text == text && button.CallbackData != nil {
+				return *button.CallbackData, true
+			}
+		}
+	}
+	return "", false
+}
+
+// IsSent checks, whether the call sends new message to the chat
+func (call *Call) IsSent(chatId int64) bool {
+	return strings.HasPrefix(call.Method, "send") && call.ChatId() == chatId
+}
+
+// PushUpdate queues update for delivery to the bot, assigning it the next update id
+func (server *Server) PushUpdate(update tgbotapi.Update) int {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.lastUpdateId++
+	update.UpdateID = server.lastUpdateId
+	server.updates = append(server.updates, update)
+	server.notify()
+	return update.UpdateID
+}
+
+func (server *Server) nextMessageId() int {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.lastMessageId++
+	return server.lastMessageId
+}
+
+// SendMessage delivers text message of the user in the private chat with the bot. Leading command is marked as bot_command
+func (server *Server) SendMessage(from tgbotapi.User, text string) tgbotapi.Message {
+	msg := server.userMessage(from)
+	msg.Text = text
+	if strings.HasPrefix(text, "/") {
+		command, _, _ := strings.Cut(text, " ")
+		msg.Entities = []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(command)}}
+	}
+	server.PushUpdate(tgbotapi.Update{Message: &msg})
+	return msg
+}
+
+// SendPhoto delivers photo with caption, which can be downloaded by the bot through getFile
+func (server *Server) SendPhoto(from tgbotapi.User, photo []byte, caption string) tgbotapi.Message {
+	msg := server.userMessage(from)
+	msg.Caption = caption
+	msg.Photo = []tgbotapi.PhotoSize{{FileID: server.AddFile(photo), FileSize: len(photo)}}
+	server.PushUpdate(tgbotapi.Update{Message: &msg})
+	return msg
+}
+
+func (server *Server) userMessage(from tgbotapi.User) tgbotapi.Message {
+	return tgbotapi.Message{
+		MessageID: server.nextMessageId(),
+		From:      &from,
+		Chat:      newChat(from.ID),
+		Date:      int(time.Now().Unix()),
+	}
+}
+
+// PressButton delivers callback query of the user, who pressed inline button with given data under the message
+func (server *Server) PressButton(from tgbotapi.User, msg tgbotapi.Message, data string) {
+	server.mu.Lock()
+	server.lastCallbackId++
+	id := server.lastCallbackId
+	server.mu.Unlock()
+	server.PushUpdate(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:           fmt.Sprintf("callback%d", id),
+		From:         &from,
+		Message:      &msg,
+		ChatInstance: strconv.FormatInt(msg.Chat.ID, 10),
+		Data:         data,
+	}})
+}
+
+// AddFile stores file, returning its id
+func (server *Server) AddFile(data []byte) string {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	fileId := fmt.Sprintf("file%d", len(server.files)+1)
+	server.files[fileId] = data
+	return fileId
+}
+
+// Calls returns all recorded requests of the bot
+func (server *Server) Calls() []Call {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	calls := make([]Call, 0, len(server.calls))
+	for _, call := range server.calls {
+		calls = append(calls, *call)
+	}
+	return calls
+}
+
+// WaitFor waits for the first request, matching the function, which wasn't returned by WaitFor before.
+// Requests are awaited in order of recording, so the same request can't satisfy two expectations
+func (server *Server) WaitFor(ctx context.Context, match func(*Call) bool) (Call, error) {
+	for {
+		server.mu.Lock()
+		for _, call := range server.calls {
+			if !call.waited && match(call) {
+				call.waited = true
+				server.mu.Unlock()
+				return *call, nil
+			}
+		}
+		changed := server.changed
+		server.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return Call{}, fmt.Errorf("no matching request among %d recorded: %w", len(server.Calls()), ctx.Err())
+		}
+	}
+}
+
+// WaitForButton waits for the message in the chat, which currently has inline button with given text, and returns
+// the message and callback data of the button. Edits of the markup are taken into account, so removed buttons aren't found
+func (server *Server) WaitForButton(ctx context.Context, chatId int64, text string) (tgbotapi.Message, string, error) {
+	for {
+		server.mu.Lock()
+		msg, data, ok := server.findButton(chatId, text)
+		changed := server.changed
+		server.mu.Unlock()
+		if ok {
+			return msg, data, nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return tgbotapi.Message{}, "", fmt.Errorf("no button %q in chat %d: %w", text, chatId, ctx.Err())
+		}
+	}
+}
+
+// findButton must be called with mu held. The most recently changed message wins
+func (server *Server) findButton(chatId int64, text string) (tgbotapi.Message, string, bool) {
+	// Telegram drops markup, when the message is edited without it
+	latest := map[int]*Call{}
+	order := []int{}
+	for _, call := range server.calls {
+		if call.ChatId() != chatId {
+			continue
+		}
+		if call.Method == "deleteMessage" {
+			msgId, _ := strconv.Atoi(call.Params["message_id"])
+			order = slices.DeleteFunc(order, func(id int) bool { return id == msgId })
+			continue
+		}
+		if call.Result == nil {
+			continue
+		}
+		msgId := call.Result.MessageID
+		order = slices.DeleteFunc(order, func(id int) bool { return id == msgId })
+		latest[msgId] = call
+		order = append(order, msgId)
+	}
+	for i := len(order) - 1; i >= 0; i-- {
+		call := latest[order[i]]
+		if data, ok := call.Button(text); ok {
+			return *call.Result, data, true
+		}
+	}
+	return tgbotapi.Message{}, "", false
+}
+
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	if filePath, ok := strings.CutPrefix(path, "file/bot"+TOKEN+"/"); ok {
+		server.serveFile(w, filePath)
+		return
+	}
+	method, ok := strings.CutPrefix(path, "bot"+TOKEN+"/")
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	call, err := parseCall(r, method)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	switch method {
+	case "getMe":
+		writeResult(w, server.Bot)
+	case "getUpdates":
+		writeResult(w, server.getUpdates(r.Context(), call))
+	case "sendMessage", "sendPhoto", "sendDocument", "sendVideo", "editMessageText", "editMessageReplyMarkup", "editMessageCaption":
+		if utf8.RuneCountInString(call.Params["text"]) > maxTextLength {
+			server.record(call)
+			writeError(w, http.StatusBadRequest, "Bad Request: message is too long")
+			return
+		}
+		call.Result = server.resultMessage(call)
+		server.record(call)
+		writeResult(w, call.Result)
+	case "getFile":
+		server.record(call)
+		fileId := call.Params["file_id"]
+		server.mu.Lock()
+		_, ok := server.files[fileId]
+		server.mu.Unlock()
+		if !ok {
+			writeError(w, http.StatusBadRequest, "Bad Request: invalid file_id")
+			return
+		}
+		writeResult(w, tgbotapi.File{FileID: fileId, FileUniqueID: fileId, FilePath: "files/" + fileId})
+	case "answerCallbackQuery", "answerInlineQuery", "deleteMessage", "deleteWebhook", "setWebhook", "setMyCommands", "deleteMyCommands",
+		"pinChatMessage", "unpinChatMessage":
+		server.record(call)
+		writeResult(w, true)
+	default:
+		server.record(call)
+		writeError(w, http.StatusNotFound, "Not Found: method "+method+" isn't supported by fake server")
+	}
+}
+
+func parseCall(r *http.Request, method string) (*Call, error) {
+	call := &Call{Method: method, Params: map[string]string{}, Files: map[string][]byte{}}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		err := r.ParseMultipartForm(maxUploadSize)
+		if err != nil {
+			return nil, err
+		}
+		for name, headers := range r.MultipartForm.File {
+			file, err := headers[0].Open()
+			if err != nil {
+				return nil, err
+			}
+			data, err := io.ReadAll(file)
+			file.Close()
+			if err != nil {
+				return nil, err
+			}
+			call.Files[name] = data
+		}
+	} else {
+		err := r.ParseForm()
+		if err != nil {
+			return nil, err
+		}
+	}
+	for name := range r.PostForm {
+		call.Params[name] = r.PostForm.Get(name)
+	}
+	return call, nil
+}
+
+func (server *Server) record(call *Call) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.calls = append(server.calls, call)
+	server.notify()
+}
+
+// getUpdates confirms updates before offset and long polls for the next ones
+func (server *Server) getUpdates(ctx context.Context, call *Call) []tgbotapi.Update {
+	offset, _ := strconv.Atoi(call.Params["offset"])
+	timeout, _ := strconv.Atoi(call.Params["timeout"])
+	deadline := time.NewTimer(min(time.Duration(timeout)*time.Second, maxPollTimeout))
+	defer deadline.Stop()
+	for {
+		server.mu.Lock()
+		pending := []tgbotapi.Update{}
+		for _, update := range server.updates {
+			if update.UpdateID >= offset {
+				pending = append(pending, update)
+			}
+		}
+		server.updates = pending
+		changed, closed := server.changed, server.closed
+		server.mu.Unlock()
+		if len(pending) > 0 || closed {
+			return pending
+		}
+
+		select {
+		case <-changed:
+		case <-deadline.C:
+			return pending
+		case <-ctx.Done():
+			return pending
+		}
+	}
+}
+
+func (server *Server) resultMessage(call *Call) *tgbotapi.Message {
+	msg := &tgbotapi.Message{
+		From:    &server.Bot,
+		Chat:    newChat(call.ChatId()),
+		Date:    int(time.Now().Unix()),
+		Text:    call.Params["text"],
+		Caption: call.Params["caption"],
+	}
+	if msgId, err := strconv.Atoi(call.Params["message_id"]); err == nil {
+		msg.MessageID = msgId
+	} else {
+		msg.MessageID = server.nextMessageId()
+	}
+	if markup := call.Markup(); markup.InlineKeyboard != nil {
+		msg.ReplyMarkup = &markup
+	}
+	if photo, ok := call.Files["photo"]; ok {
+		msg.Photo = []tgbotapi.PhotoSize{{FileID: server.AddFile(photo), FileSize: len(photo)}}
+	}
+	if document, ok := call.Files["document"]; ok {
+		msg.Document = &tgbotapi.Document{FileID: server.AddFile(document), FileSize: len(document)}
+	}
+	if video, ok := call.Files["video"]; ok {
+		msg.Video = &tgbotapi.Video{FileID: server.AddFile(video), FileSize: len(video)}
+	}
+	return msg
+}
+
+func (server *Server) serveFile(w http.ResponseWriter, filePath string) {
+	fileId, _ := strings.CutPrefix(filePath, "files/")
+	server.mu.Lock()
+	data, ok := server.files[fileId]
+	server.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_, _ = w.Write(data)
+}
+
+func newChat(chatId int64) *tgbotapi.Chat {
+	chatType := "private"
+	if chatId < 0 {
+		chatType = "group"
+	}
+	return &tgbotapi.Chat{ID: chatId, Type: chatType}
+}
+
+func writeResult(w http.ResponseWriter, result any) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Internal Server Error: "+err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(tgbotapi.APIResponse{Ok: true, Result: data})
+}
+
+func writeError(w http.ResponseWriter, code int, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(tgbotapi.APIResponse{Ok: false, ErrorCode: code, Description: description})
+}
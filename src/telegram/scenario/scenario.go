@@ -0,0 +1,358 @@
+package scenario
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	iisEntities "github.com/aCrYoZPS/bsuir_queue_bot/src/iis_api/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/ioc"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces/mocks"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
+	fakeapi "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/fake_api"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	OWNER_ID     = 1000
+	STEP_TIMEOUT = 5 * time.Second
+	// Format of lessons on buttons of lesson mode and in lists of requests
+	LESSON_TIME_FORMAT = "02.01.2006 15:04"
+)
+
+// Harness runs the bot, wired by ioc, against fake telegram server, temporary sqlite database and mocks of google and iis apis.
+// Container of ioc is global, so tests, using harness, can't run in parallel
+type Harness struct {
+	Server          *fakeapi.Server
+	DB              *sql.DB
+	Sheets          *mocks.SheetsApiMock
+	Users           *sqlite.UsersRepository
+	Groups          *sqlite.GroupsRepository
+	Lessons         *sqlite.LessonsRepository
+	LessonsRequests *sqlite.LessonsRequestsRepository
+
+	t           testing.TB
+	lastGroupId int
+}
+
+func New(t testing.TB) *Harness {
+	t.Helper()
+	t.Setenv("SQLITE_INIT_FILE", initFile())
+	t.Setenv("OWNERS", strconv.Itoa(OWNER_ID))
+	t.Setenv("BOT_MODE", "")
+	// Queue messages and sheets of outcomes are refreshed right away, so that tests don't wait for the throttle
+	t.Setenv("QUEUE_REFRESH_INTERVAL", "0")
+	t.Setenv("OUTCOMES_REFRESH_INTERVAL", "0")
+
+	// Test seeds the database, while the bot writes to it, so it's opened the same way as in production
+	db, err := sql.Open("sqlite3", sqlite.Dsn(filepath.Join(t.TempDir(), "bot.db")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sqlite.DatabaseInit(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups, err := sqlite.NewGroupsRepository(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	harness := &Harness{
+		Server:          fakeapi.NewServer(),
+		DB:              db,
+		Sheets:          mocks.NewSheetsApiMock(),
+		Users:           sqlite.NewUsersRepository(db),
+		Groups:          groups,
+		Lessons:         sqlite.NewLessonsRepository(db),
+		LessonsRequests: sqlite.NewLessonsRequestsRepository(db),
+		t:               t,
+	}
+	// The same options as in ioc, except for rate limits, which would slow down tests
+	bot, err := harness.Server.NewBot(tgutils.WithMarkupsStore(sqlite.NewFlowMarkupsRepository(db)), tgutils.WithLanguagesStore(harness.Users))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ioc.Reset()
+	ioc.StubSqliteConnection(db)
+	ioc.StubTgBot(bot)
+	ioc.StubGroupsService(groups)
+	ioc.StubSheetsApiService(harness.Sheets)
+	ioc.StubDriveApiService(mocks.NewDriveApiMock())
+	ioc.StubLessonsService(mocks.NewLessonsServiceMock(harness.Lessons, harness.Sheets))
+	controller := ioc.UseBotController()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		controller.Start(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		if err := ioc.ValidateRoutes(); err != nil {
+			t.Errorf("handlers saved undeclared states: %v", err)
+		}
+		harness.Server.Close()
+		ioc.Reset()
+		db.Close()
+	})
+	return harness
+}
+
+// initFile returns path of the database schema, regardless of the directory of the test
+func initFile() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "..", "sql", "db_setup.sql")
+}
+
+// T returns text of the key in default language, which is used for users without language
+func T(key i18n.Key, args ...any) string {
+	return i18n.T(context.Background(), key, args...)
+}
+
+// AddGroup adds the group, as if it was loaded from iis api
+func (harness *Harness) AddGroup(name string) int64 {
+	harness.t.Helper()
+	harness.lastGroupId++
+	err := harness.Groups.Add(context.Background(), &iisEntities.Group{Id: harness.lastGroupId, Name: name})
+	if err != nil {
+		harness.t.Fatal(err)
+	}
+	return int64(harness.lastGroupId)
+}
+
+// LessonStart returns the time of labworks, added by AddLabwork on the date
+func LessonStart(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 9, 0, 0, 0, time.Local)
+}
+
+// LessonTime returns the time of labwork on the date, as the bot shows it on buttons
+func LessonTime(date time.Time) string {
+	return LessonStart(date).Format(LESSON_TIME_FORMAT)
+}
+
+// AddLabwork adds labwork of the whole group at 9:00 of the date and returns its id
+func (harness *Harness) AddLabwork(groupName, subject string, date time.Time) int64 {
+	harness.t.Helper()
+	ctx := context.Background()
+	group, err := harness.Groups.GetByName(ctx, groupName)
+	if err != nil {
+		harness.t.Fatal(err)
+	}
+	dateTime := LessonStart(date)
+	err = harness.Lessons.Add(ctx, &persistence.Lesson{GroupId: int64(group.Id), LessonType: iisEntities.Labwork, Subject: subject,
+		SubgroupNumber: iisEntities.AllSubgroups, DateTime: dateTime})
+	if err != nil {
+		harness.t.Fatal(err)
+	}
+	lessons, err := harness.Lessons.GetNext(ctx, subject, int64(group.Id))
+	if err != nil {
+		harness.t.Fatal(err)
+	}
+	for _, lesson := range lessons {
+		if lesson.DateTime.Equal(dateTime) {
+			return lesson.Id
+		}
+	}
+	harness.t.Fatalf("labwork %s of %s at %s isn't offered for submission", subject, groupName, dateTime)
+	return 0
+}
+
+// AcceptedRequest submits the labwork of the student through the bot and lets the admin accept it
+func (harness *Harness) AcceptedRequest(admin, student *User, subject string, date time.Time, labworkNumber int) {
+	harness.t.Helper()
+	student.SubmitsLabwork(subject, date, labworkNumber)
+	admin.Expects(student.fullName).Presses(T(i18n.ACCEPT_BUTTON))
+	student.Expects(T(i18n.LABWORK_REQUEST_ACCEPTED_TEXT))
+}
+
+// Enqueue puts accepted request of the student right into the queue of the lesson and returns its id.
+// Unlike AcceptedRequest, it works for lessons of today, which aren't offered for submission
+func (harness *Harness) Enqueue(lessonId int64, student *User) int64 {
+	harness.t.Helper()
+	ctx := context.Background()
+	err := harness.LessonsRequests.Add(ctx, entities.NewLessonRequest(lessonId, student.ID, 0, student.ID, 1))
+	if err != nil {
+		harness.t.Fatal(err)
+	}
+	requests, err := harness.LessonsRequests.GetLessonRequests(ctx, lessonId)
+	if err != nil {
+		harness.t.Fatal(err)
+	}
+	for _, request := range requests {
+		if request.UserId == student.ID {
+			err = harness.LessonsRequests.SetAccepted(ctx, request.Id)
+			if err != nil {
+				harness.t.Fatal(err)
+			}
+			return request.Id
+		}
+	}
+	harness.t.Fatalf("request of user %d isn't in lesson %d", student.ID, lessonId)
+	return 0
+}
+
+// Requested waits for the request of the method in the chat, e.g. pinChatMessage, which doesn't send messages
+func (harness *Harness) Requested(method string, chatId int64) fakeapi.Call {
+	harness.t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), STEP_TIMEOUT)
+	defer cancel()
+	call, err := harness.Server.WaitFor(ctx, func(call *fakeapi.Call) bool {
+		return call.Method == method && call.ChatId() == chatId
+	})
+	if err != nil {
+		harness.t.Fatalf("bot didn't request %s in chat %d: %v", method, chatId, err)
+	}
+	return call
+}
+
+// Eventually waits for the condition, e.g. for changes, which the bot makes in the background, failing the test after STEP_TIMEOUT
+func (harness *Harness) Eventually(what string, condition func() bool) {
+	harness.t.Helper()
+	deadline := time.Now().Add(STEP_TIMEOUT)
+	for !condition() {
+		if time.Now().After(deadline) {
+			harness.t.Fatalf("%s didn't happen in %s", what, STEP_TIMEOUT)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// User returns user, who hasn't joined any group
+func (harness *Harness) User(tgId int64) *User {
+	return &User{User: tgbotapi.User{ID: tgId, FirstName: "User" + strconv.FormatInt(tgId, 10)}, harness: harness}
+}
+
+// Student returns user, who is a member of the group
+func (harness *Harness) Student(tgId int64, fullName, groupName string) *User {
+	return harness.addUser(entities.NewUser(fullName, groupName, tgId))
+}
+
+// Admin returns admin of the group
+func (harness *Harness) Admin(tgId int64, fullName, groupName string) *User {
+	return harness.addUser(entities.NewUser(fullName, groupName, tgId, entities.WithAdminRole()))
+}
+
+// Owner returns the owner of the bot, who approves admins
+func (harness *Harness) Owner() *User {
+	return harness.User(OWNER_ID)
+}
+
+func (harness *Harness) addUser(user *entities.User) *User {
+	harness.t.Helper()
+	err := harness.Users.Add(context.Background(), user)
+	if err != nil {
+		harness.t.Fatal(err)
+	}
+	added := harness.User(user.TgId)
+	added.fullName = user.FullName
+	return added
+}
+
+// User acts in the private chat with the bot. Every step waits for the bot, failing the test after STEP_TIMEOUT
+type User struct {
+	tgbotapi.User
+	harness  *Harness
+	fullName string
+}
+
+func (user *User) Sends(text string) *User {
+	user.harness.Server.SendMessage(user.User, text)
+	return user
+}
+
+func (user *User) SendsPhoto(photo []byte, caption string) *User {
+	user.harness.Server.SendPhoto(user.User, photo, caption)
+	return user
+}
+
+// SubmitsLabwork submits the labwork of the subject on the date with photo "proof" and waits until the request is sent to admins
+func (user *User) SubmitsLabwork(subject string, date time.Time, labworkNumber int) *User {
+	user.harness.t.Helper()
+	return user.Sends(constants.SUBMIT_COMMAND).
+		Expects(T(i18n.CHOOSE_DISCIPLINE_AND_DATE_TEXT)).
+		Presses(subject).
+		Presses(date.Format("02/01/2006")).
+		Expects(T(i18n.ENTER_LABWORK_NUMBER_TEXT)).
+		Sends(strconv.Itoa(labworkNumber)).
+		Expects(T(i18n.ENTER_LABWORK_PROOF_TEXT)).
+		SendsPhoto([]byte("proof"), "").
+		Expects(T(i18n.LABWORK_REQUEST_SENT_TEXT))
+}
+
+// Presses presses inline button with given text under the latest message, which has it
+func (user *User) Presses(text string) *User {
+	user.harness.t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), STEP_TIMEOUT)
+	defer cancel()
+	msg, data, err := user.harness.Server.WaitForButton(ctx, user.ID, text)
+	if err != nil {
+		user.harness.t.Fatalf("user %d can't press button: %v", user.ID, err)
+	}
+	user.harness.Server.PressButton(user.User, msg, data)
+	return user
+}
+
+// Expects waits for the message to the user, which contains given text. Edits of messages count too
+func (user *User) Expects(text string) *User {
+	user.harness.t.Helper()
+	user.Received(text)
+	return user
+}
+
+// Received is Expects, which returns the request of the bot
+func (user *User) Received(text string) fakeapi.Call {
+	user.harness.t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), STEP_TIMEOUT)
+	defer cancel()
+	call, err := user.harness.Server.WaitFor(ctx, func(call *fakeapi.Call) bool {
+		return call.Result != nil && call.ChatId() == user.ID && strings.Contains(call.Text(), text)
+	})
+	if err != nil {
+		user.harness.t.Fatalf("user %d didn't receive %q: %v", user.ID, text, err)
+	}
+	return call
+}
+
+// InlineQuery types the query in inline mode of the bot and returns texts of the offered articles
+func (user *User) InlineQuery(query string) []string {
+	user.harness.t.Helper()
+	id := strconv.FormatInt(time.Now().UnixNano(), 10)
+	user.harness.Server.PushUpdate(tgbotapi.Update{InlineQuery: &tgbotapi.InlineQuery{ID: id, From: &user.User, Query: query}})
+	ctx, cancel := context.WithTimeout(context.Background(), STEP_TIMEOUT)
+	defer cancel()
+	call, err := user.harness.Server.WaitFor(ctx, func(call *fakeapi.Call) bool {
+		return call.Method == "answerInlineQuery" && call.Params["inline_query_id"] == id
+	})
+	if err != nil {
+		user.harness.t.Fatalf("bot didn't answer inline query of user %d: %v", user.ID, err)
+	}
+	var results []struct {
+		Content struct {
+			Text string `json:"message_text"`
+		} `json:"input_message_content"`
+	}
+	err = json.Unmarshal([]byte(call.Params["results"]), &results)
+	if err != nil {
+		user.harness.t.Fatalf("bot answered inline query with %q: %v", call.Params["results"], err)
+	}
+	texts := make([]string, 0, len(results))
+	for _, result := range results {
+		texts = append(texts, result.Content.Text)
+	}
+	return texts
+}
@@ -0,0 +1,658 @@
+package scenariotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/cron"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces/mocks"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite"
+	fakeapi "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/fake_api"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/scenario"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	groupName = "353502"
+	subject   = "ООП"
+)
+
+// Labworks of today aren't offered for submission
+var labworkDate = time.Now().AddDate(0, 0, 3)
+
+func TestAcceptLabwork(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	labworkId := harness.AddLabwork(groupName, subject, labworkDate)
+	admin := harness.Admin(100, "Admin Adminov", groupName)
+	student := harness.Student(200, "Ivanov Ivan", groupName)
+
+	harness.AcceptedRequest(admin, student, subject, labworkDate, 2)
+
+	rows := harness.Sheets.Labworks()
+	if len(rows) != 1 || rows[0].FullName != "Ivanov Ivan" || rows[0].DisciplineName != subject || rows[0].LabworkNumber != 2 {
+		t.Errorf("sheets got %+v, want labwork 2 of Ivanov Ivan", rows)
+	}
+	queue, err := harness.LessonsRequests.GetLabworkQueue(context.Background(), labworkId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queue) != 1 || queue[0].TgId != student.ID {
+		t.Errorf("queue is %+v, want only the student", queue)
+	}
+}
+
+func TestJoinAndSubmitLabwork(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	harness.AddLabwork(groupName, subject, labworkDate)
+	admin := harness.Admin(100, "Admin Adminov", groupName)
+	student := harness.User(200)
+
+	student.Sends(constants.JOIN_GROUP_COMMAND).
+		Expects(scenario.T(i18n.ENTER_GROUP_NAME_TEXT)).
+		Sends(groupName).
+		Expects(scenario.T(i18n.ENTER_FULL_NAME_TEXT)).
+		Sends("Ivanov Ivan").
+		Expects(scenario.T(i18n.GROUP_REQUEST_SENT_TEXT))
+	admin.Expects("Ivanov Ivan").Presses(scenario.T(i18n.ACCEPT_BUTTON))
+	student.Expects(scenario.T(i18n.REQUEST_APPROVED_TEXT))
+
+	student.SubmitsLabwork(subject, labworkDate, 2)
+	labworkRequest := admin.Received("Ivanov Ivan")
+	if string(labworkRequest.Files["photo"]) != "proof" {
+		t.Errorf("admin got photo %q, want the proof", labworkRequest.Files["photo"])
+	}
+	// Requests, waiting for approval, aren't in the queue yet
+	if texts := student.InlineQuery(""); len(texts) != 1 || !strings.Contains(texts[0], scenario.T(i18n.INLINE_QUEUE_EMPTY)) {
+		t.Errorf("queues before acceptance are %q, want the empty one", texts)
+	}
+
+	admin.Presses(scenario.T(i18n.ACCEPT_BUTTON))
+	student.Expects(scenario.T(i18n.LABWORK_REQUEST_ACCEPTED_TEXT))
+	if texts := student.InlineQuery(""); len(texts) != 1 || !strings.Contains(texts[0], "1 Ivanov Ivan\n") {
+		t.Errorf("queues after acceptance are %q, want the student first", texts)
+	}
+}
+
+func TestInlineQueueTruncated(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	labworkId := harness.AddLabwork(groupName, subject, labworkDate)
+	student := harness.Student(200, "Ivanov Ivan", groupName)
+	harness.Enqueue(labworkId, student)
+	for i := range 100 {
+		name := fmt.Sprintf("Student %03d %s", i, strings.Repeat("Long", 10))
+		harness.Enqueue(labworkId, harness.Student(int64(300+i), name, groupName))
+	}
+
+	texts := student.InlineQuery("")
+	if len(texts) != 1 {
+		t.Fatalf("bot offered %d queues, want 1", len(texts))
+	}
+	if length := utf8.RuneCountInString(texts[0]); length > 4096 {
+		t.Errorf("queue has %d characters, more than telegram allows", length)
+	}
+	if !strings.Contains(texts[0], "1 Ivanov Ivan\n") || !strings.HasSuffix(texts[0], "…") {
+		t.Errorf("queue is %q, want its beginning cut with ellipsis", texts[0])
+	}
+}
+
+func TestDeclineLabwork(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	labworkId := harness.AddLabwork(groupName, subject, labworkDate)
+	admin := harness.Admin(100, "Admin Adminov", groupName)
+	student := harness.Student(200, "Ivanov Ivan", groupName)
+
+	student.SubmitsLabwork(subject, labworkDate, 2)
+	admin.Expects("Ivanov Ivan").Presses(scenario.T(i18n.DECLINE_BUTTON))
+	student.Expects(scenario.T(i18n.REQUEST_DECLINED_TEXT))
+
+	if rows := harness.Sheets.Labworks(); len(rows) != 0 {
+		t.Errorf("sheets got %+v after decline", rows)
+	}
+	queue, err := harness.LessonsRequests.GetLabworkQueue(context.Background(), labworkId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queue) != 0 {
+		t.Errorf("queue is %+v after decline, want empty", queue)
+	}
+}
+
+func TestDecideLabworkOnce(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	labworkId := harness.AddLabwork(groupName, subject, labworkDate)
+	admin := harness.Admin(100, "Admin Adminov", groupName)
+	other := harness.Admin(101, "Petrov Petr", groupName)
+	student := harness.Student(200, "Ivanov Ivan", groupName)
+
+	student.SubmitsLabwork(subject, labworkDate, 2)
+	other.Expects("Ivanov Ivan")
+	ctx, cancel := context.WithTimeout(context.Background(), scenario.STEP_TIMEOUT)
+	defer cancel()
+	copyMsg, declineData, err := harness.Server.WaitForButton(ctx, other.ID, scenario.T(i18n.DECLINE_BUTTON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin.Expects("Ivanov Ivan").Presses(scenario.T(i18n.ACCEPT_BUTTON))
+	student.Expects(scenario.T(i18n.LABWORK_REQUEST_ACCEPTED_TEXT))
+
+	harness.Server.PressButton(other.User, copyMsg, declineData)
+	_, err = harness.Server.WaitFor(ctx, func(call *fakeapi.Call) bool {
+		return call.Method == "answerCallbackQuery" && call.Params["text"] == scenario.T(i18n.ALREADY_PROCESSED_TEXT)
+	})
+	if err != nil {
+		t.Fatalf("decline of the accepted request copy wasn't rejected: %v", err)
+	}
+	queue, err := harness.LessonsRequests.GetLabworkQueue(ctx, labworkId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queue) != 1 || queue[0].TgId != student.ID {
+		t.Errorf("queue is %+v, want only the student", queue)
+	}
+}
+
+func TestOldFormatButtons(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	harness.AddLabwork(groupName, subject, labworkDate)
+	admin := harness.Admin(100, "Admin Adminov", groupName)
+	student := harness.Student(200, "Ivanov Ivan", groupName)
+
+	student.SubmitsLabwork(subject, labworkDate, 2)
+	admin.Expects("Ivanov Ivan")
+	ctx, cancel := context.WithTimeout(context.Background(), scenario.STEP_TIMEOUT)
+	defer cancel()
+	msg, _, err := harness.Server.WaitForButton(ctx, admin.ID, scenario.T(i18n.ACCEPT_BUTTON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Buttons, sent before the callback codec was introduced, carry data without version
+	harness.Server.PressButton(admin.User, msg, fmt.Sprintf("%s%d|%d", constants.LABWORK_ACCEPT_CALLBACKS, student.ID, msg.MessageID))
+	_, err = harness.Server.WaitFor(ctx, func(call *fakeapi.Call) bool {
+		return call.Method == "answerCallbackQuery" && call.Params["text"] == scenario.T(i18n.BUTTONS_EXPIRED_TEXT)
+	})
+	if err != nil {
+		t.Fatalf("button of the old format wasn't answered as expired: %v", err)
+	}
+	for _, call := range harness.Server.Calls() {
+		if call.Params["text"] == scenario.T(i18n.CRASH_TEXT) {
+			t.Errorf("button of the old format was reported as crash to %s", call.Params["chat_id"])
+		}
+	}
+}
+
+func TestAssignAdmin(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	user := harness.User(300)
+
+	user.Sends(constants.ASSIGN_COMMAND).
+		Expects(scenario.T(i18n.ENTER_FULL_NAME_TEXT)).
+		Sends("Petrov Petr").
+		Expects(scenario.T(i18n.ENTER_IIS_GROUP_TEXT)).
+		Sends("000000").
+		Expects(scenario.T(i18n.INVALID_IIS_GROUP_TEXT)).
+		Sends(groupName).
+		Expects(scenario.T(i18n.ENTER_ADMIN_PROOF_TEXT)).
+		SendsPhoto([]byte("student card"), "")
+	owner := harness.Owner().Expects("Petrov Petr")
+	user.Sends("?").Expects(scenario.T(i18n.ADMIN_REQUEST_PENDING_TEXT))
+	owner.Presses(scenario.T(i18n.ACCEPT_BUTTON))
+	user.Expects(scenario.T(i18n.ADMIN_REQUEST_APPROVED_TEXT, mocks.MOCK_SHEET_URL))
+
+	admins, err := harness.Groups.GetAdmins(context.Background(), groupName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(admins) != 1 || admins[0].TgId != user.ID {
+		t.Errorf("admins of the group are %+v, want the user", admins)
+	}
+	if sheets := harness.Sheets.Sheets(); len(sheets) != 1 || sheets[0] != groupName {
+		t.Errorf("created sheets %v, want sheet of %s", sheets, groupName)
+	}
+}
+
+func TestAdminCallbacksOfStudent(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	labworkId := harness.AddLabwork(groupName, subject, labworkDate)
+	student := harness.Student(200, "Ivanov Ivan", groupName)
+
+	// Buttons of admin flows can be forged, as well as left from the time, when the user was admin
+	msg := tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: student.ID, Type: "private"}}
+	harness.Server.PressButton(student.User, msg, fmt.Sprintf("%s%d", constants.DELETE_REQUEST_TIME_CALLBACK, labworkId))
+	ctx, cancel := context.WithTimeout(context.Background(), scenario.STEP_TIMEOUT)
+	defer cancel()
+	_, err := harness.Server.WaitFor(ctx, func(call *fakeapi.Call) bool {
+		return call.Method == "answerCallbackQuery" && call.Params["text"] == scenario.T(i18n.NOT_ADMIN_TEXT)
+	})
+	if err != nil {
+		t.Fatalf("admin callback of the student wasn't rejected: %v", err)
+	}
+}
+
+func TestAdminCallbacksOfAnotherGroup(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	harness.AddGroup("353503")
+	labworkId := harness.AddLabwork(groupName, subject, labworkDate)
+	admin := harness.Admin(100, "Admin Adminov", groupName)
+	other := harness.Admin(101, "Petrov Petr", "353503")
+	student := harness.Student(200, "Ivanov Ivan", groupName)
+	student.SubmitsLabwork(subject, labworkDate, 2)
+	admin.Expects("Ivanov Ivan").Presses(scenario.T(i18n.ACCEPT_BUTTON))
+	student.Expects(scenario.T(i18n.LABWORK_REQUEST_ACCEPTED_TEXT))
+
+	// Lessons are referenced by ids, so buttons of one group can be forged by admin of another one
+	data := tgutils.NewCallbackCodec[struct{ LessonId int64 }](constants.DELETE_REQUEST_TIME_CALLBACK, 1).
+		MustEncode(struct{ LessonId int64 }{LessonId: labworkId})
+	msg := tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: other.ID, Type: "private"}}
+	harness.Server.PressButton(other.User, msg, data)
+	ctx, cancel := context.WithTimeout(context.Background(), scenario.STEP_TIMEOUT)
+	defer cancel()
+	_, err := harness.Server.WaitFor(ctx, func(call *fakeapi.Call) bool {
+		return call.Method == "answerCallbackQuery" && call.Params["text"] == scenario.T(i18n.NOT_ADMIN_TEXT)
+	})
+	if err != nil {
+		t.Fatalf("callback of another group wasn't rejected: %v", err)
+	}
+	for _, call := range harness.Server.Calls() {
+		if call.Params["chat_id"] == fmt.Sprint(other.ID) && strings.Contains(call.Params["text"], "Ivanov Ivan") {
+			t.Errorf("admin of another group got requests of the lesson: %q", call.Params["text"])
+		}
+	}
+}
+
+func TestLiveQueue(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	harness.AddLabwork(groupName, subject, labworkDate)
+	admin := harness.Admin(100, "Admin Adminov", groupName)
+	follower := harness.Student(200, "Ivanov Ivan", groupName)
+	student := harness.Student(201, "Petrov Petr", groupName)
+
+	follower.Sends(constants.QUEUE_COMMAND).
+		Expects(scenario.T(i18n.CHOOSE_DISCIPLINE_TEXT)).
+		Presses(subject).
+		Presses(labworkDate.Format("02/01/2006")).
+		Expects(scenario.T(i18n.QUEUE_EMPTY_TEXT)).
+		Presses(scenario.T(i18n.QUEUE_SUBSCRIBE_PIN_BUTTON))
+	harness.Requested("pinChatMessage", follower.ID)
+
+	student.SubmitsLabwork(subject, labworkDate, 2)
+	admin.Expects("Petrov Petr").Presses(scenario.T(i18n.ACCEPT_BUTTON))
+	follower.Expects("1 Petrov Petr")
+
+	follower.Presses(scenario.T(i18n.QUEUE_UNSUBSCRIBE_BUTTON))
+	harness.Requested("unpinChatMessage", follower.ID)
+}
+
+func TestLanguageOfStudentCommands(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	student := harness.Student(200, "Ivanov Ivan", groupName)
+
+	student.Sends(constants.LANGUAGE_COMMAND).
+		Presses(i18n.Name(i18n.EN)).
+		Expects(i18n.Translate(i18n.EN, i18n.LANGUAGE_CHANGED_TEXT))
+	ctx, cancel := context.WithTimeout(context.Background(), scenario.STEP_TIMEOUT)
+	defer cancel()
+	_, err := harness.Server.WaitFor(ctx, func(call *fakeapi.Call) bool {
+		return call.Method == "setMyCommands" && strings.Contains(call.Params["scope"], `"chat_id":200`) &&
+			strings.Contains(call.Params["commands"], i18n.Translate(i18n.EN, i18n.LANGUAGE_DESCRIPTION))
+	})
+	if err != nil {
+		t.Fatalf("commands of the student weren't set in english: %v", err)
+	}
+}
+
+func TestLongQueueMessage(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	labworkId := harness.AddLabwork(groupName, subject, labworkDate)
+	follower := harness.Student(200, "Ivanov Ivan", groupName)
+	harness.Enqueue(labworkId, follower)
+	for i := range 150 {
+		name := fmt.Sprintf("Student %03d %s", i, strings.Repeat("Long", 10))
+		harness.Enqueue(labworkId, harness.Student(int64(300+i), name, groupName))
+	}
+
+	follower.Sends(constants.QUEUE_COMMAND).
+		Expects(scenario.T(i18n.CHOOSE_DISCIPLINE_TEXT)).
+		Presses(subject).
+		Presses(labworkDate.Format("02/01/2006")).
+		Expects("1 Ivanov Ivan\n").
+		Expects("151 Student 149").
+		Presses(scenario.T(i18n.QUEUE_SUBSCRIBE_BUTTON))
+	ctx, cancel := context.WithTimeout(context.Background(), scenario.STEP_TIMEOUT)
+	defer cancel()
+	_, err := harness.Server.WaitFor(ctx, func(call *fakeapi.Call) bool {
+		return call.Method == "editMessageText" && strings.Contains(call.Text(), "1 Ivanov Ivan\n") && call.Markup().InlineKeyboard != nil &&
+			strings.Contains(call.Markup().InlineKeyboard[0][0].Text, scenario.T(i18n.QUEUE_UNSUBSCRIBE_BUTTON))
+	})
+	if err != nil {
+		t.Fatalf("subscribed queue wasn't edited: %v", err)
+	}
+	for _, call := range harness.Server.Calls() {
+		if length := utf8.RuneCountInString(call.Text()); length > 4096 {
+			t.Errorf("bot requested %s with %d characters, more than telegram allows", call.Method, length)
+		}
+	}
+}
+
+func TestWithdrawRequest(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	labworkId := harness.AddLabwork(groupName, subject, labworkDate)
+	admin := harness.Admin(100, "Admin Adminov", groupName)
+	student := harness.Student(200, "Ivanov Ivan", groupName)
+
+	harness.AcceptedRequest(admin, student, subject, labworkDate, 2)
+	student.Sends(constants.MY_REQUESTS_COMMAND).
+		Expects(scenario.T(i18n.MY_REQUESTS_TITLE)).
+		Presses(scenario.T(i18n.MY_REQUEST_WITHDRAW_BUTTON, 1)).
+		Presses(scenario.T(i18n.MY_REQUEST_CONFIRM_WITHDRAW_BUTTON)).
+		Expects(scenario.T(i18n.MY_REQUESTS_EMPTY_TEXT))
+
+	if rows := harness.Sheets.Labworks(); len(rows) != 0 {
+		t.Errorf("sheets got %+v after withdrawal", rows)
+	}
+	queue, err := harness.LessonsRequests.GetLabworkQueue(context.Background(), labworkId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queue) != 0 {
+		t.Errorf("queue is %+v after withdrawal, want empty", queue)
+	}
+}
+
+func TestEditRequest(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	harness.AddLabwork(groupName, subject, labworkDate)
+	nextDate := labworkDate.AddDate(0, 0, 7)
+	nextLabworkId := harness.AddLabwork(groupName, subject, nextDate)
+	nextLessonTime := scenario.LessonTime(nextDate)
+	admin := harness.Admin(100, "Admin Adminov", groupName)
+	student := harness.Student(200, "Ivanov Ivan", groupName)
+
+	harness.AcceptedRequest(admin, student, subject, labworkDate, 2)
+	student.Sends(constants.MY_REQUESTS_COMMAND).
+		Presses(scenario.T(i18n.MY_REQUEST_EDIT_BUTTON, 1)).
+		Presses(scenario.T(i18n.MY_REQUEST_CHANGE_NUMBER_BUTTON)).
+		Expects(scenario.T(i18n.MY_REQUEST_ENTER_NUMBER_TEXT)).
+		Sends("5").
+		Expects(scenario.T(i18n.MY_REQUEST_UPDATED_TEXT)).
+		Presses(scenario.T(i18n.MY_REQUEST_EDIT_BUTTON, 1)).
+		Presses(scenario.T(i18n.MY_REQUEST_MOVE_BUTTON)).
+		Presses(nextLessonTime).
+		Expects(scenario.T(i18n.MY_REQUEST_LINE, 1, subject, nextLessonTime, 5, 1))
+
+	queue, err := harness.LessonsRequests.GetLabworkQueue(context.Background(), nextLabworkId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queue) != 1 || queue[0].TgId != student.ID {
+		t.Errorf("queue of the next lesson is %+v, want the moved request", queue)
+	}
+	rows := harness.Sheets.Labworks()
+	if len(rows) != 1 || rows[0].LabworkNumber != 5 || !time.Time(rows[0].RequestedDate).Equal(scenario.LessonStart(nextDate)) {
+		t.Errorf("sheets got %+v, want only labwork 5 at %s", rows, nextLessonTime)
+	}
+}
+
+func TestMoveRequestWhenSheetsFail(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	labworkId := harness.AddLabwork(groupName, subject, labworkDate)
+	nextDate := labworkDate.AddDate(0, 0, 7)
+	nextLabworkId := harness.AddLabwork(groupName, subject, nextDate)
+	nextLessonTime := scenario.LessonTime(nextDate)
+	admin := harness.Admin(100, "Admin Adminov", groupName)
+	student := harness.Student(200, "Ivanov Ivan", groupName)
+
+	harness.AcceptedRequest(admin, student, subject, labworkDate, 2)
+
+	harness.Sheets.FailAppends(errors.New("google is down"))
+	student.Sends(constants.MY_REQUESTS_COMMAND).
+		Presses(scenario.T(i18n.MY_REQUEST_EDIT_BUTTON, 1)).
+		Presses(scenario.T(i18n.MY_REQUEST_MOVE_BUTTON)).
+		Presses(nextLessonTime).
+		Expects(scenario.T(i18n.CRASH_TEXT))
+	// Failed move is reverted, so the database and the sheet still agree on the old lesson
+	queue, err := harness.LessonsRequests.GetLabworkQueue(context.Background(), labworkId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queue) != 1 || queue[0].TgId != student.ID {
+		t.Errorf("queue of the lesson is %+v after failed move, want the request", queue)
+	}
+	if rows := harness.Sheets.Labworks(); len(rows) != 1 || rows[0].LabworkNumber != 2 {
+		t.Errorf("sheets got %+v after failed move, want the old row", rows)
+	}
+
+	harness.Sheets.FailAppends(nil)
+	student.Sends(constants.MY_REQUESTS_COMMAND).
+		Presses(scenario.T(i18n.MY_REQUEST_EDIT_BUTTON, 1)).
+		Presses(scenario.T(i18n.MY_REQUEST_MOVE_BUTTON)).
+		Presses(nextLessonTime).
+		Expects(scenario.T(i18n.MY_REQUEST_LINE, 1, subject, nextLessonTime, 2, 1))
+	queue, err = harness.LessonsRequests.GetLabworkQueue(context.Background(), nextLabworkId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queue) != 1 || queue[0].TgId != student.ID {
+		t.Errorf("queue of the next lesson is %+v after retry, want the moved request", queue)
+	}
+	if rows := harness.Sheets.Labworks(); len(rows) != 1 {
+		t.Errorf("sheets got %+v after retry, want only the moved row", rows)
+	}
+}
+
+func TestLessonMode(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	labworkId := harness.AddLabwork(groupName, subject, labworkDate)
+	lessonTime := scenario.LessonTime(labworkDate)
+	admin := harness.Admin(100, "Admin Adminov", groupName)
+	student := harness.Student(200, "Ivanov Ivan", groupName)
+
+	harness.AcceptedRequest(admin, student, subject, labworkDate, 2)
+
+	admin.Sends(constants.LESSON_MODE_COMMAND).
+		Expects(scenario.T(i18n.LESSON_MODE_CHOOSE_SUBJECT_TEXT)).
+		Presses(subject).
+		Presses(lessonTime).
+		Presses(scenario.T(i18n.LESSON_MODE_NEXT_BUTTON)).
+		Expects(scenario.T(i18n.LESSON_MODE_CURRENT_TEXT, "Ivanov Ivan"))
+	student.Expects(scenario.T(i18n.LESSON_MODE_YOUR_TURN_TEXT, 2, subject))
+	admin.Presses(scenario.T(i18n.LESSON_MODE_PASSED_BUTTON)).
+		Expects(scenario.T(i18n.LESSON_MODE_FINISHED_TEXT))
+
+	queue, err := harness.LessonsRequests.GetLessonQueue(context.Background(), labworkId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queue) != 1 || queue[0].Outcome != entities.Passed {
+		t.Errorf("queue is %+v, want the request of the student passed", queue)
+	}
+	if outcome := harness.Sheets.Outcome("Ivanov Ivan", 2); outcome != entities.Passed {
+		t.Errorf("sheets got outcome %d, want passed", outcome)
+	}
+}
+
+func TestOutcomeHistory(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	harness.AddLabwork(groupName, subject, labworkDate)
+	lessonTime := scenario.LessonTime(labworkDate)
+	admin := harness.Admin(100, "Admin Adminov", groupName)
+	student := harness.Student(200, "Ivanov Ivan", groupName)
+
+	harness.AcceptedRequest(admin, student, subject, labworkDate, 2)
+
+	admin.Sends(constants.LESSON_MODE_COMMAND).
+		Presses(subject).
+		Presses(lessonTime).
+		Presses(scenario.T(i18n.LESSON_MODE_NEXT_BUTTON)).
+		Presses(scenario.T(i18n.LESSON_MODE_FAILED_BUTTON)).
+		Presses(scenario.T(i18n.LESSON_MODE_GRADE_BUTTON, "Ivanov Ivan")).
+		Expects(scenario.T(i18n.LESSON_MODE_ENTER_GRADE_TEXT, "Ivanov Ivan", 2)).
+		Sends("11").
+		Expects(scenario.T(i18n.LESSON_MODE_INVALID_GRADE_TEXT)).
+		Sends("3 Нет отчёта").
+		Expects(scenario.T(i18n.LESSON_MODE_GRADE_SAVED_TEXT))
+
+	student.Sends(constants.RESULTS_COMMAND).
+		Expects(scenario.T(i18n.RESULTS_LINE, 2, scenario.T(i18n.OUTCOME_FAILED)) + scenario.T(i18n.RESULTS_GRADE, 3) +
+			scenario.T(i18n.RESULTS_COMMENT, "Нет отчёта") + scenario.T(i18n.RESULTS_MARKED_BY, "Admin Adminov", time.Now().Format("02.01.2006")))
+
+	// The sheet of outcomes is rewritten in the background
+	harness.Eventually("writing of the graded outcome to the sheet", func() bool {
+		history := harness.Sheets.History(groupName)
+		return len(history) == 1 && history[0].Outcome == entities.Failed && history[0].Grade == 3 && history[0].FullName == "Ivanov Ivan"
+	})
+}
+
+func TestCorrectOutcome(t *testing.T) {
+	harness := scenario.New(t)
+	groupId := harness.AddGroup(groupName)
+	labworkId := harness.AddLabwork(groupName, subject, labworkDate)
+	student := harness.Student(200, "Ivanov Ivan", groupName)
+	requestId := harness.Enqueue(labworkId, student)
+	ctx := context.Background()
+	outcomes := sqlite.NewLabworkOutcomesRepository(harness.DB)
+	mark := func(outcome entities.Outcome) int64 {
+		t.Helper()
+		id, err := outcomes.Save(ctx, &entities.LabworkOutcome{RequestId: requestId, LessonId: labworkId, UserId: student.ID,
+			GroupId: groupId, Subject: subject, LabworkNumber: 1, Outcome: outcome, MarkedBy: 100, MarkedAt: time.Now()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	id := mark(entities.Failed)
+	err := outcomes.SetGrade(ctx, id, 3, "Нет отчёта")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mark(entities.Failed) != id {
+		t.Fatal("repeated mark added another outcome")
+	}
+	if mark(entities.Passed) != id {
+		t.Fatal("correction added another outcome")
+	}
+	history, err := outcomes.GetUserOutcomes(ctx, student.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Outcome != entities.Passed || history[0].Grade != 0 || history[0].Comment != "" {
+		t.Errorf("history is %+v, want only the passed labwork without the grade of the failed one", history)
+	}
+	queue, err := harness.LessonsRequests.GetLessonQueue(ctx, labworkId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queue) != 1 || queue[0].Outcome != entities.Passed {
+		t.Errorf("queue is %+v, want the request with its outcome", queue)
+	}
+}
+
+func TestTurnNotifications(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	labworkId := harness.AddLabwork(groupName, subject, time.Now())
+	lessonTime := scenario.LessonTime(time.Now())
+	admin := harness.Admin(100, "Admin Adminov", groupName)
+	first := harness.Student(200, "Ivanov Ivan", groupName)
+	second := harness.Student(201, "Petrov Petr", groupName)
+	third := harness.Student(202, "Sidorov Sidor", groupName)
+	for _, student := range []*scenario.User{first, second, third} {
+		harness.Enqueue(labworkId, student)
+	}
+
+	third.Sends(constants.SETTINGS_COMMAND).
+		Presses(scenario.T(i18n.TURN_NOTIFICATIONS_OFF_BUTTON)).
+		Expects(scenario.T(i18n.SETTINGS_TEXT, scenario.T(i18n.TURN_NOTIFICATIONS_DISABLED)))
+
+	admin.Sends(constants.LESSON_MODE_COMMAND).
+		Presses(subject).
+		Presses(lessonTime).
+		Presses(scenario.T(i18n.LESSON_MODE_NEXT_BUTTON)).
+		Expects(scenario.T(i18n.LESSON_MODE_CURRENT_TEXT, "Ivanov Ivan"))
+	first.Expects(scenario.T(i18n.LESSON_MODE_YOUR_TURN_TEXT, 1, subject))
+	second.Expects(scenario.T(i18n.TURN_NEXT_TEXT, 1, subject))
+
+	admin.Presses(scenario.T(i18n.LESSON_MODE_PASSED_BUTTON)).
+		Expects(scenario.T(i18n.LESSON_MODE_CURRENT_TEXT, "Petrov Petr"))
+	second.Expects(scenario.T(i18n.LESSON_MODE_YOUR_TURN_TEXT, 1, subject))
+
+	// The third student turned notifications off, so only the call of the admin reaches them
+	for _, call := range harness.Server.Calls() {
+		if call.ChatId() == third.ID && (strings.Contains(call.Text(), scenario.T(i18n.TURN_NEXT_TEXT, 1, subject)) ||
+			strings.Contains(call.Text(), scenario.T(i18n.TURN_POSITION_TEXT, 1, subject, 2))) {
+			t.Errorf("student, who turned notifications off, got %q", call.Text())
+		}
+	}
+}
+
+func TestTurnNotificationsAfterReminder(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	labworkId := harness.AddLabwork(groupName, subject, time.Now())
+	first := harness.Student(200, "Ivanov Ivan", groupName)
+	second := harness.Student(201, "Petrov Petr", groupName)
+	requestId := harness.Enqueue(labworkId, first)
+	harness.Enqueue(labworkId, second)
+
+	// The first student confirms in the reminder, that they have passed the labwork, and leaves the queue
+	reminder := tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: first.ID}}
+	harness.Server.PressButton(first.User, reminder, cron.ReminderCallbackCodec.MustEncode(cron.ReminderCallback{Accepted: true, RequestId: requestId}))
+	second.Expects(scenario.T(i18n.TURN_NEXT_TEXT, 1, subject))
+}
+
+func TestReminderOfAnotherStudent(t *testing.T) {
+	harness := scenario.New(t)
+	harness.AddGroup(groupName)
+	labworkId := harness.AddLabwork(groupName, subject, time.Now())
+	student := harness.Student(200, "Ivanov Ivan", groupName)
+	other := harness.Student(201, "Petrov Petr", groupName)
+	requestId := harness.Enqueue(labworkId, student)
+
+	// Reminder in the group chat can be pressed by other members, but they can't answer it for the student
+	reminder := tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: -1, Type: "group"}}
+	data := cron.ReminderCallbackCodec.MustEncode(cron.ReminderCallback{Accepted: true, RequestId: requestId})
+	harness.Server.PressButton(other.User, reminder, data)
+	ctx, cancel := context.WithTimeout(context.Background(), scenario.STEP_TIMEOUT)
+	defer cancel()
+	_, err := harness.Server.WaitFor(ctx, func(call *fakeapi.Call) bool {
+		return call.Method == "answerCallbackQuery" && call.Params["text"] == scenario.T(i18n.FOREIGN_CALLBACK_TEXT)
+	})
+	if err != nil {
+		t.Fatalf("bot didn't reject reminder answer of another student: %v", err)
+	}
+	if history := harness.Sheets.History(groupName); len(history) != 0 {
+		t.Errorf("outcomes %+v were recorded by another student", history)
+	}
+
+	harness.Server.PressButton(student.User, reminder, data)
+	harness.Eventually("removal of the answered request from the queue", func() bool {
+		queue, err := harness.LessonsRequests.GetLabworkQueue(context.Background(), labworkId)
+		return err == nil && len(queue) == 0
+	})
+}
@@ -5,10 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"slices"
-	"strconv"
 	"strings"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
@@ -25,21 +25,26 @@ type DeleteLessonCallbackHandler struct {
 	cache   interfaces.HandlersCache
 	users   UsersRepository
 	bot     *tgutils.Bot
-	lessons LessonsRepository
+	lessons  LessonsRepository
+	payloads *tgutils.CallbackPayloads
 }
 
 func NewDeleteLessonCallbackHandler(cache interfaces.HandlersCache, users UsersRepository, bot *tgutils.Bot, 
-	lessons LessonsRepository) *DeleteLessonCallbackHandler {
+	lessons LessonsRepository, payloads *tgutils.CallbackPayloads) *DeleteLessonCallbackHandler {
 	return &DeleteLessonCallbackHandler{
-		cache:   cache,
-		users:   users,
-		bot:     bot,
-		lessons: lessons,
+		cache:    cache,
+		users:    users,
+		bot:      bot,
+		lessons:  lessons,
+		payloads: payloads,
 	}
 }
 
 func (state *DeleteLessonCallbackHandler) HandleCallback(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) error {
-	lessonName := parseLessonCallbackData(update.CallbackData())
+	lessonName, err := parseLessonCallbackData(ctx, state.payloads, update.CallbackData())
+	if err != nil {
+		return err
+	}
 	user, err := state.users.GetByTgId(ctx, update.CallbackQuery.From.ID)
 	if err != nil {
 		return fmt.Errorf("failed to get user in delete lesson reequest callback: %w", err)
@@ -48,6 +53,13 @@ func (state *DeleteLessonCallbackHandler) HandleCallback(ctx context.Context, up
 	if err != nil {
 		return fmt.Errorf("failed to get next lessons in delete lesson request callback: %w", err)
 	}
+	if len(lessons) == 0 {
+		_, err = bot.RequestCtx(ctx, tgbotapi.NewCallbackWithAlert(update.CallbackQuery.ID, i18n.T(ctx, i18n.NO_LESSONS_TEXT)))
+		if err != nil {
+			return fmt.Errorf("failed to answer delete lesson request callback without lessons: %w", err)
+		}
+		return nil
+	}
 
 	jsonedInfo, err := state.cache.GetInfo(ctx, update.FromChat().ChatConfig().ChatID)
 	if err != nil {
@@ -72,23 +84,34 @@ func (state *DeleteLessonCallbackHandler) HandleCallback(ctx context.Context, up
 const markupLen = 4
 func (state *DeleteLessonCallbackHandler) createMarkup(lessons []persistence.Lesson) tgbotapi.InlineKeyboardMarkup {
 	keyboard := tgbotapi.InlineKeyboardMarkup{}
-	row := []tgbotapi.InlineKeyboardButton{}
 	for chunk := range slices.Chunk(lessons, markupLen) {
+		row := []tgbotapi.InlineKeyboardButton{}
 		for _, lesson := range chunk {
 			row = append(row, tgbotapi.NewInlineKeyboardButtonData(lesson.Subject+fmt.Sprintf(" (%s)",
 				lesson.DateTime.Format("02.01.2006")), createTimeCallbackData(lesson.Id)))
 		}
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, row)
 	}
-	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, row)
 	return keyboard
 }
 
+type timeCallback struct {
+	LessonId int64
+}
+
+var timeCodec = tgutils.NewCallbackCodec[timeCallback](constants.DELETE_REQUEST_TIME_CALLBACK, 1)
+
 func createTimeCallbackData(lessonId int64) string {
-	return constants.DELETE_REQUEST_TIME_CALLBACK + "|" + fmt.Sprint(lessonId)
+	return timeCodec.MustEncode(timeCallback{LessonId: lessonId})
 }
 
-func parseTimeCallbackData(callbackData string) (int64, error) {
-	return strconv.ParseInt(strings.TrimPrefix(callbackData, constants.DELETE_REQUEST_TIME_CALLBACK+"|"), 10, 64)
+// ParseTimeCallbackData returns id of the lesson, which requests are chosen for deletion
+func ParseTimeCallbackData(callbackData string) (int64, error) {
+	data, err := timeCodec.Decode(callbackData)
+	if err != nil {
+		return 0, err
+	}
+	return data.LessonId, nil
 }
 
 type LessonRequestsRepository interface {
@@ -108,7 +131,7 @@ func NewDeleteTimeCallbackHandler(cache interfaces.HandlersCache, bot *tgutils.B
 }
 
 func (state *DeleteTimeCallbackHandler) HandleCallback(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) error {
-	lessonId, err := parseTimeCallbackData(update.CallbackData())
+	lessonId, err := ParseTimeCallbackData(update.CallbackData())
 	if err != nil {
 		return fmt.Errorf("failed to parse callback data: %w", err)
 	}
@@ -117,16 +140,41 @@ func (state *DeleteTimeCallbackHandler) HandleCallback(ctx context.Context, upda
 	if err != nil {
 		return fmt.Errorf("failed to get requests in delete request time callback handler: %w", err)
 	}
-	users := make([]entities.User, len(requests))
+	if len(requests) == 0 {
+		_, err = bot.RequestCtx(ctx, tgbotapi.NewCallbackWithAlert(update.CallbackQuery.ID, i18n.T(ctx, i18n.DELETE_REQUEST_EMPTY_TEXT)))
+		if err != nil {
+			return fmt.Errorf("failed to answer delete request time callback without requests: %w", err)
+		}
+		return nil
+	}
+	users := make([]entities.User, 0, len(requests))
 	for _, request := range requests {
-		user, err := state.users.Get(ctx, request.UserId)
+		// Requests store telegram ids of their authors
+		user, err := state.users.GetByTgId(ctx, request.UserId)
 		if err != nil {
 			return fmt.Errorf("failed to get user by id in delete request time callback handler: %w", err)
 		}
-		users = append(users, user)
+		users = append(users, *user)
+	}
+
+	storedInfo, err := state.cache.GetInfo(ctx, update.FromChat().ID)
+	if err != nil {
+		return fmt.Errorf("failed to get info in delete request time callback handler: %w", err)
+	}
+	var info DeleteStatesInfo
+	err = json.Unmarshal([]byte(storedInfo), &info)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal json into info in delete request time callback handler: %w", err)
+	}
+	info.Requests = requests
+
+	_, err = state.bot.SendCtx(ctx, tgbotapi.NewEditMessageReplyMarkup(update.FromChat().ID, info.SentMsgId,
+		tgbotapi.NewInlineKeyboardMarkup(make([]tgbotapi.InlineKeyboardButton, 0))))
+	if err != nil {
+		return fmt.Errorf("failed to remove markup in delete request time callback handler: %w", err)
 	}
 
-	jsonedInfo, err := json.Marshal(&DeleteStatesInfo{Requests: requests})
+	jsonedInfo, err := json.Marshal(&info)
 	if err != nil {
 		return fmt.Errorf("failed to convert info into json in request delete time callback handler: %w", err)
 	}
@@ -135,7 +183,7 @@ func (state *DeleteTimeCallbackHandler) HandleCallback(ctx context.Context, upda
 		return fmt.Errorf("failed to save info in delete request time callback handler: %w", err)
 	}
 
-	_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(update.FromChat().ID, formatOutput(requests, users)))
+	_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(update.FromChat().ID, formatOutput(ctx, requests, users)))
 	if err != nil {
 		return fmt.Errorf("failed to send response to user in delete request time callback handler: %w", err)
 	}
@@ -147,11 +195,11 @@ func (state *DeleteTimeCallbackHandler) HandleCallback(ctx context.Context, upda
 	return nil
 }
 
-func formatOutput(requests []entities.LessonRequest, users []entities.User) string {
+func formatOutput(ctx context.Context, requests []entities.LessonRequest, users []entities.User) string {
 	var out strings.Builder
-	fmt.Fprintf(&out, "Введите число, представляющее заявку, для удаления\n")
+	out.WriteString(i18n.T(ctx, i18n.DELETE_REQUEST_LIST_TEXT))
 	for i := range requests {
-		fmt.Fprintf(&out, "%d. %s, лабораторная: %d\n", i+1, users[i].FullName, requests[i].LabworkNumber)
+		out.WriteString(i18n.T(ctx, i18n.DELETE_REQUEST_LINE, i+1, users[i].FullName, requests[i].LabworkNumber))
 	}
 	return out.String()
 }
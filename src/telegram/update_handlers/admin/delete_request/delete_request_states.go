@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
 	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
@@ -19,9 +20,18 @@ type RequestsRepository interface {
 	Delete(ctx context.Context, id int64) error
 }
 
+// QueueRefresher edits messages, which are subscribed to the queue of the lesson
+type QueueRefresher interface {
+	Refresh(lessonId int64)
+}
+
+// TurnNotifier tells the students, waiting in the queue of the lesson, their changed places
+type TurnNotifier interface {
+	Notify(ctx context.Context, lessonId int64)
+}
+
 type UsersRepository interface {
-	GetByTgId(ctx context.Context, tgId int64) (entities.User, error)
-	Get(ctx context.Context, id int64) (entities.User, error)
+	GetByTgId(ctx context.Context, tgId int64) (*entities.User, error)
 }
 
 type DeleteStartState struct {
@@ -30,13 +40,14 @@ type DeleteStartState struct {
 	requests RequestsRepository
 	users    UsersRepository
 	lessons  LessonsRepository
+	payloads *tgutils.CallbackPayloads
 }
 
 const SubjectsChunk = 3
 
 func NewDeleteStartState(bot *tgutils.Bot, cache interfaces.HandlersCache, requests RequestsRepository, users UsersRepository,
-	 lessons LessonsRepository) *DeleteStartState {
-	return &DeleteStartState{cache: cache, bot: bot, requests: requests, users: users, lessons: lessons}
+	 lessons LessonsRepository, payloads *tgutils.CallbackPayloads) *DeleteStartState {
+	return &DeleteStartState{cache: cache, bot: bot, requests: requests, users: users, lessons: lessons, payloads: payloads}
 }
 
 type DeleteStatesInfo struct {
@@ -45,7 +56,7 @@ type DeleteStatesInfo struct {
 }
 
 func (state *DeleteStartState) Handle(ctx context.Context, message *tgbotapi.Message) error {
-	responseText := "Выберите предмет для удаления"
+	responseText := i18n.T(ctx, i18n.DELETE_REQUEST_CHOOSE_SUBJECT_TEXT)
 	user, err := state.users.GetByTgId(ctx, message.From.ID)
 	if err != nil {
 		return fmt.Errorf("failed to get current user in request delete start state: %w", err)
@@ -54,13 +65,28 @@ func (state *DeleteStartState) Handle(ctx context.Context, message *tgbotapi.Mes
 	if err != nil {
 		return fmt.Errorf("failed to get subjects for the group in request delete start state: %w", err)
 	}
-	markup := tgbotapi.InlineKeyboardMarkup{InlineKeyboard: make([][]tgbotapi.InlineKeyboardButton, len(subjects)/SubjectsChunk)}
-	i := 0
+	if len(subjects) == 0 {
+		_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.NO_SUBJECTS_TEXT)))
+		if err != nil {
+			return fmt.Errorf("failed to send no subjects response in request delete start state: %w", err)
+		}
+		err = state.cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, constants.IDLE_STATE))
+		if err != nil {
+			return fmt.Errorf("failed to save idle state during request delete start state: %w", err)
+		}
+		return nil
+	}
+	markup := tgbotapi.InlineKeyboardMarkup{}
 	for chunk := range slices.Chunk(subjects, SubjectsChunk) {
+		row := []tgbotapi.InlineKeyboardButton{}
 		for _, item := range chunk {
-			markup.InlineKeyboard[i] = append(markup.InlineKeyboard[i], tgbotapi.NewInlineKeyboardButtonData(item, createLessonCallbackData(item)))
+			data, err := createLessonCallbackData(ctx, state.payloads, item)
+			if err != nil {
+				return err
+			}
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(item, data))
 		}
-		i++
+		markup.InlineKeyboard = append(markup.InlineKeyboard, row)
 	}
 	response := tgbotapi.NewMessage(message.Chat.ID, responseText)
 	response.ReplyMarkup = markup
@@ -77,23 +103,39 @@ func (state *DeleteStartState) Handle(ctx context.Context, message *tgbotapi.Mes
 	if err != nil {
 		return fmt.Errorf("failed to save jsoned info in delete start state: %w", err)
 	}
-	err = state.cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, constants.DELETE_WAITING_STATE))
+	err = state.cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, constants.DELETE_REQUEST_WAITING))
 	if err != nil {
 		return fmt.Errorf("failed to save state during request delete start state: %w", err)
 	}
 	return nil
 }
 
-func (state *DeleteStartState) Revert(ctx context.Context, msg tgbotapi.Message) error {
+func (state *DeleteStartState) Revert(ctx context.Context, msg *tgbotapi.Message) error {
+	err := state.cache.RemoveInfo(ctx, msg.Chat.ID)
+	if err != nil {
+		return fmt.Errorf("failed to remove info during request delete start state reversal: %w", err)
+	}
 	return nil
 }
 
-func createLessonCallbackData(lessonName string) string {
-	return constants.DELETE_REQUEST_LESSON_CALLBACK + lessonName
+type lessonPayload struct {
+	LessonName string `json:"lesson"`
 }
-func parseLessonCallbackData(callbackData string) string {
-	after, _ := strings.CutPrefix(callbackData, constants.DELETE_REQUEST_LESSON_CALLBACK)
-	return after
+
+func createLessonCallbackData(ctx context.Context, payloads *tgutils.CallbackPayloads, lessonName string) (string, error) {
+	data, err := tgutils.PutPayload(ctx, payloads, constants.DELETE_REQUEST_LESSON_CALLBACK, lessonPayload{LessonName: lessonName})
+	if err != nil {
+		return "", fmt.Errorf("failed to create delete request lesson callback: %w", err)
+	}
+	return data, nil
+}
+
+func parseLessonCallbackData(ctx context.Context, payloads *tgutils.CallbackPayloads, callbackData string) (string, error) {
+	payload, err := tgutils.GetPayload[lessonPayload](ctx, payloads, constants.DELETE_REQUEST_LESSON_CALLBACK, callbackData)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse delete request lesson callback: %w", err)
+	}
+	return payload.LessonName, nil
 }
 
 type DeleteWaitingState struct {
@@ -106,7 +148,7 @@ func NewDeleteWaitingState(bot *tgutils.Bot, cache interfaces.HandlersCache) *De
 }
 
 func (state *DeleteWaitingState) Handle(ctx context.Context, message *tgbotapi.Message) error {
-	_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, "Выберите пару для удаления заявки"))
+	_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.DELETE_REQUEST_CHOOSE_LESSON_TEXT)))
 	if err != nil {
 		return fmt.Errorf("failed to send response in request delete waiting state: %w", err)
 	}
@@ -146,19 +188,23 @@ type DeleteChooseState struct {
 	bot      *tgutils.Bot
 	machine  StateMachine
 	requests RequestsRepository
+	queues   QueueRefresher
+	turns    TurnNotifier
 }
 
-func NewDeleteChooseState(cache interfaces.HandlersCache, bot *tgutils.Bot, machine StateMachine) *DeleteChooseState {
-	return &DeleteChooseState{cache: cache, bot: bot, machine: machine}
+func NewDeleteChooseState(cache interfaces.HandlersCache, bot *tgutils.Bot, machine StateMachine, requests RequestsRepository,
+	queues QueueRefresher, turns TurnNotifier) *DeleteChooseState {
+	return &DeleteChooseState{cache: cache, bot: bot, machine: machine, requests: requests, queues: queues, turns: turns}
 }
 
 func (state *DeleteChooseState) Handle(ctx context.Context, message *tgbotapi.Message) error {
-	num, err := strconv.ParseInt(message.Text, 10, 64)
+	num, err := strconv.ParseInt(strings.TrimSpace(message.Text), 10, 64)
 	if err != nil {
-		_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, "Введите корректный номер заявки"))
+		_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.DELETE_REQUEST_INVALID_NUMBER_TEXT)))
 		if err != nil {
 			return fmt.Errorf("failed to send incorrect number response in delete request choose state: %w", err)
 		}
+		return nil
 	}
 	jsonedInfo, err := state.cache.GetInfo(ctx, message.Chat.ID)
 	if err != nil {
@@ -170,22 +216,33 @@ func (state *DeleteChooseState) Handle(ctx context.Context, message *tgbotapi.Me
 	if err != nil {
 		return fmt.Errorf("failed to unmarshal jsoned info into states info: %w", err)
 	}
-	if num-1 < 0 || num-1 < int64(len(info.Requests)) {
+	if num-1 < 0 || num-1 >= int64(len(info.Requests)) {
 		_, err := state.bot.SendCtx(ctx, 
-			tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Пожалуйста, введите валидное число в пределах от 1 до %d", len(info.Requests))))
+			tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.DELETE_REQUEST_NUMBER_RANGE_TEXT, len(info.Requests))))
 		if err != nil {
 			return fmt.Errorf("failed to send invalid len response in delete request choose state: %w", err)
 		}
+		return nil
 	}
 
 	err = state.requests.Delete(ctx, info.Requests[num-1].Id)
 	if err != nil {
 		return fmt.Errorf("failed to delete request %d in delete request choose state: %w", info.Requests[num-1].Id, err)
 	}
+	state.queues.Refresh(info.Requests[num-1].LessonId)
+	state.turns.Notify(ctx, info.Requests[num-1].LessonId)
 
 	err = state.cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, constants.IDLE_STATE))
 	if err != nil {
-		return fmt.Errorf("failed to save %s during %s: %w", constants.IDLE_STATE, constants.DELETE_CHOOSE_STATE, err)
+		return fmt.Errorf("failed to save %s during %s: %w", constants.IDLE_STATE, constants.DELETE_REQUEST_CHOOSE_STATE, err)
+	}
+	err = state.cache.RemoveInfo(ctx, message.Chat.ID)
+	if err != nil {
+		return fmt.Errorf("failed to remove info in delete request choose state: %w", err)
+	}
+	_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.DELETE_REQUEST_DELETED_TEXT)))
+	if err != nil {
+		return fmt.Errorf("failed to send response in delete request choose state: %w", err)
 	}
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
 	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
@@ -17,9 +18,14 @@ import (
 type StudentsRepository interface {
 	GetStudents(ctx context.Context, groupname string) ([]entities.User, error)
 	GetByTgId(ctx context.Context, tgId int64) (*entities.User, error)
+	GetById(ctx context.Context, id int64) (*entities.User, error)
 	Delete(ctx context.Context, id int64) error
 }
 
+type CommandsMenu interface {
+	Refresh(ctx context.Context, tgId int64) error
+}
+
 type DeleteStartState struct {
 	bot      *tgutils.Bot
 	students StudentsRepository
@@ -59,7 +65,7 @@ func (state *DeleteStartState) Handle(ctx context.Context, message *tgbotapi.Mes
 		return fmt.Errorf("failed to save delete start state info: %w", err)
 	}
 
-	resp := tgbotapi.NewMessage(message.Chat.ID, "Выберите студента для удаления из списка:\n"+state.formatStudentsOutput(students))
+	resp := tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.DELETE_USER_CHOOSE_TEXT)+state.formatStudentsOutput(students))
 	_, err = state.bot.SendCtx(ctx, resp)
 	if err != nil {
 		return fmt.Errorf("failed to send response during delete start state: %w", err)
@@ -87,16 +93,17 @@ type DeleteChooseState struct {
 	bot      *tgutils.Bot
 	cache    interfaces.HandlersCache
 	students StudentsRepository
+	menu     CommandsMenu
 }
 
-func NewDeleteChooseState(bot *tgutils.Bot, cache interfaces.HandlersCache, students StudentsRepository) *DeleteChooseState {
-	return &DeleteChooseState{bot: bot, cache: cache, students: students}
+func NewDeleteChooseState(bot *tgutils.Bot, cache interfaces.HandlersCache, students StudentsRepository, menu CommandsMenu) *DeleteChooseState {
+	return &DeleteChooseState{bot: bot, cache: cache, students: students, menu: menu}
 }
 
 func (state *DeleteChooseState) Handle(ctx context.Context, message *tgbotapi.Message) error {
 	num, err := strconv.Atoi(message.Text)
 	if err != nil {
-		_, sendErr := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, "Введите конкретную цифру с участником группы"))
+		_, sendErr := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.DELETE_USER_ENTER_NUMBER_TEXT)))
 		if sendErr != nil {
 			return fmt.Errorf("failed to send response in delete choose state: %w", sendErr)
 		}
@@ -114,17 +121,26 @@ func (state *DeleteChooseState) Handle(ctx context.Context, message *tgbotapi.Me
 	}
 	student, exists := info.Students[num]
 	if !exists {
-		_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, "Введите корректный номер с участником группы"))
+		_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.DELETE_USER_INVALID_NUMBER_TEXT)))
 		if err != nil {
 			return fmt.Errorf("failed to send response in delete choose state: %w", err)
 		}
 		return fmt.Errorf("failed to parse number from user input in delete choose state: %w", err)
 	}
 
+	deleted, err := state.students.GetById(ctx, student)
+	if err != nil {
+		return fmt.Errorf("failed to get user %d in delete choose state: %w", student, err)
+	}
 	err = state.students.Delete(ctx, student)
 	if err != nil {
 		return fmt.Errorf("failed to delete user %d: %w", student, err)
 	}
+	// Removed admins lose their scoped commands
+	err = state.menu.Refresh(ctx, deleted.TgId)
+	if err != nil {
+		return fmt.Errorf("failed to refresh commands of deleted user %d: %w", student, err)
+	}
 
 	err = state.cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, constants.IDLE_STATE))
 	if err != nil {
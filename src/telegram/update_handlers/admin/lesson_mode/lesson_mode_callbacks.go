@@ -0,0 +1,379 @@
+package lessonmode
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/labworks"
+	datetime "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/date_time"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+type LessonQueueRepository interface {
+	GetLessonQueue(ctx context.Context, lessonId int64) ([]entities.QueuedRequest, error)
+}
+
+type SheetsService interface {
+	SetLabworkOutcome(ctx context.Context, req *labworks.AppendedLabwork, outcome entities.Outcome) error
+}
+
+// OutcomesRecorder keeps the history of outcomes, grades and comments are added to the recorded outcomes later
+type OutcomesRecorder interface {
+	Record(ctx context.Context, outcome *entities.LabworkOutcome) (int64, error)
+	Get(ctx context.Context, id int64) (*entities.LabworkOutcome, error)
+	SetGrade(ctx context.Context, id int64, grade int8, comment string) error
+}
+
+// TurnNotifier tells the students after the called one their places in the queue
+type TurnNotifier interface {
+	Advance(ctx context.Context, lessonId, calledRequestId int64)
+}
+
+type panelAction int8
+
+const (
+	actionShow panelAction = iota
+	actionNext
+	actionSkip
+	actionPassed
+	actionFailed
+	actionAbsent
+)
+
+var actionOutcomes = map[panelAction]entities.Outcome{
+	actionPassed: entities.Passed,
+	actionFailed: entities.Failed,
+	actionAbsent: entities.Absent,
+}
+
+var outcomeMarks = map[entities.Outcome]string{
+	entities.NoOutcome: "⏳",
+	entities.Passed:    "✅",
+	entities.Failed:    "❌",
+	entities.Absent:    "🚫",
+}
+
+const CURRENT_MARK = "▶️"
+
+// panelCallback carries the called request, so that the panel doesn't keep any state besides outcomes in the database
+type panelCallback struct {
+	Action    panelAction
+	LessonId  int64
+	RequestId int64
+}
+
+var PanelCodec = tgutils.NewCallbackCodec[panelCallback](constants.LESSON_MODE_PANEL_CALLBACKS, 1)
+
+type gradeCallback struct {
+	OutcomeId int64
+}
+
+var GradeCodec = tgutils.NewCallbackCodec[gradeCallback](constants.LESSON_MODE_GRADE_CALLBACKS, 1)
+
+// markedOutcome is the outcome, which was just recorded, so that the panel offers to grade it
+type markedOutcome struct {
+	Id       int64
+	FullName string
+}
+
+type LessonModeCallbackHandler struct {
+	cache    interfaces.HandlersCache
+	users    UsersRepository
+	lessons  LessonsRepository
+	requests LessonQueueRepository
+	sheets   SheetsService
+	outcomes OutcomesRecorder
+	turns    TurnNotifier
+	payloads *tgutils.CallbackPayloads
+}
+
+func NewLessonModeCallbackHandler(cache interfaces.HandlersCache, users UsersRepository, lessons LessonsRepository,
+	requests LessonQueueRepository, sheets SheetsService, outcomes OutcomesRecorder, turns TurnNotifier,
+	payloads *tgutils.CallbackPayloads) *LessonModeCallbackHandler {
+	return &LessonModeCallbackHandler{cache: cache, users: users, lessons: lessons, requests: requests, sheets: sheets, outcomes: outcomes,
+		turns: turns, payloads: payloads}
+}
+
+// HandleSubject replaces subjects with upcoming lessons of the chosen one
+func (handler *LessonModeCallbackHandler) HandleSubject(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) error {
+	user, err := handler.admin(ctx, update, bot)
+	if err != nil || user == nil {
+		return err
+	}
+	payload, err := tgutils.GetPayload[subjectPayload](ctx, handler.payloads, constants.LESSON_MODE_SUBJECT_CALLBACKS, update.CallbackData())
+	if err != nil {
+		return fmt.Errorf("failed to parse lesson mode subject callback: %w", err)
+	}
+	lessons, err := handler.lessons.GetNext(ctx, payload.Subject, user.GroupId)
+	if err != nil {
+		return fmt.Errorf("failed to get next lessons during lesson mode subject callback handling: %w", err)
+	}
+	if len(lessons) == 0 {
+		_, err = bot.RequestCtx(ctx, tgbotapi.NewCallbackWithAlert(update.CallbackQuery.ID, i18n.T(ctx, i18n.NO_LESSONS_TEXT)))
+		if err != nil {
+			return fmt.Errorf("failed to answer lesson mode subject callback without lessons: %w", err)
+		}
+		return nil
+	}
+	markup := [][]tgbotapi.InlineKeyboardButton{}
+	for _, lesson := range lessons {
+		markup = append(markup, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+			lesson.DateTime.Format(LESSON_DATE_FORMAT), PanelCodec.MustEncode(panelCallback{Action: actionShow, LessonId: lesson.Id}))))
+	}
+	return handler.edit(ctx, update, bot, i18n.T(ctx, i18n.LESSON_MODE_CHOOSE_LESSON_TEXT), tgbotapi.NewInlineKeyboardMarkup(markup...))
+}
+
+// HandlePanel records outcome of the called request and calls the next one. Only admins of the group of the lesson can use the panel
+func (handler *LessonModeCallbackHandler) HandlePanel(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot,
+	data *panelCallback) error {
+	user, err := handler.admin(ctx, update, bot)
+	if err != nil || user == nil {
+		return err
+	}
+	lesson, err := handler.lessons.Get(ctx, data.LessonId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to get lesson during lesson mode panel callback handling: %w", err)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		_, err = bot.RequestCtx(ctx, tgbotapi.NewCallbackWithAlert(update.CallbackQuery.ID, i18n.T(ctx, i18n.NO_LESSONS_TEXT)))
+		if err != nil {
+			return fmt.Errorf("failed to answer lesson mode panel callback of unknown lesson: %w", err)
+		}
+		return nil
+	}
+	if lesson.GroupId != user.GroupId {
+		_, err = bot.RequestCtx(ctx, tgbotapi.NewCallbackWithAlert(update.CallbackQuery.ID, i18n.T(ctx, i18n.NOT_ADMIN_TEXT)))
+		if err != nil {
+			return fmt.Errorf("failed to answer lesson mode panel callback of another group: %w", err)
+		}
+		return nil
+	}
+	queue, err := handler.requests.GetLessonQueue(ctx, lesson.Id)
+	if err != nil {
+		return fmt.Errorf("failed to get queue during lesson mode panel callback handling: %w", err)
+	}
+
+	// Called request could have been withdrawn or deleted since the panel was rendered
+	current := slices.IndexFunc(queue, func(request entities.QueuedRequest) bool { return request.Id == data.RequestId })
+	called := current
+	var marked *markedOutcome
+	switch data.Action {
+	case actionNext:
+		called = nextWaiting(queue, -1)
+		if called == -1 {
+			_, err = bot.RequestCtx(ctx, tgbotapi.NewCallbackWithAlert(update.CallbackQuery.ID, strings.TrimSpace(i18n.T(ctx, i18n.LESSON_MODE_FINISHED_TEXT))))
+			if err != nil {
+				return fmt.Errorf("failed to answer lesson mode next callback of finished queue: %w", err)
+			}
+			return nil
+		}
+	case actionSkip:
+		if current != -1 {
+			called = nextWaiting(queue, current)
+			if called == -1 {
+				called = current
+			}
+		}
+	case actionPassed, actionFailed, actionAbsent:
+		if current != -1 {
+			outcome := actionOutcomes[data.Action]
+			id, err := handler.setOutcome(ctx, user, lesson, queue[current], outcome)
+			if err != nil {
+				return err
+			}
+			queue[current].Outcome = outcome
+			marked = &markedOutcome{Id: id, FullName: queue[current].FullName}
+			called = nextWaiting(queue, -1)
+		}
+	}
+
+	if called != -1 && called != current {
+		handler.notify(ctx, bot, lesson, queue[called])
+	}
+	if data.Action != actionShow {
+		calledId := int64(0)
+		if called != -1 {
+			calledId = queue[called].Id
+		}
+		handler.turns.Advance(ctx, lesson.Id, calledId)
+	}
+	text, keyboard := renderPanel(ctx, lesson, queue, called, marked)
+	return handler.edit(ctx, update, bot, text, keyboard)
+}
+
+// HandleGrade waits for the grade and the comment of the recorded outcome in LESSON_MODE_GRADE_STATE. The panel is kept as is,
+// so that the admin can go on with the queue after grading
+func (handler *LessonModeCallbackHandler) HandleGrade(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot,
+	data *gradeCallback) error {
+	user, err := handler.admin(ctx, update, bot)
+	if err != nil || user == nil {
+		return err
+	}
+	outcome, err := handler.outcomes.Get(ctx, data.OutcomeId)
+	if err != nil {
+		return fmt.Errorf("failed to get outcome during lesson mode grade callback handling: %w", err)
+	}
+	if outcome == nil || outcome.GroupId != user.GroupId {
+		_, err = bot.RequestCtx(ctx, tgbotapi.NewCallbackWithAlert(update.CallbackQuery.ID, i18n.T(ctx, i18n.NOT_ADMIN_TEXT)))
+		if err != nil {
+			return fmt.Errorf("failed to answer lesson mode grade callback of another group: %w", err)
+		}
+		return nil
+	}
+	chatId := update.FromChat().ID
+	err = handler.cache.SaveInfo(ctx, chatId, fmt.Sprint(outcome.Id))
+	if err != nil {
+		return fmt.Errorf("failed to save outcome id during lesson mode grade callback handling: %w", err)
+	}
+	err = handler.cache.SaveState(ctx, *interfaces.NewCachedInfo(chatId, constants.LESSON_MODE_GRADE_STATE))
+	if err != nil {
+		return fmt.Errorf("failed to save grade state during lesson mode grade callback handling: %w", err)
+	}
+	_, err = bot.SendCtx(ctx, tgbotapi.NewMessage(chatId, i18n.T(ctx, i18n.LESSON_MODE_ENTER_GRADE_TEXT, outcome.FullName,
+		outcome.LabworkNumber)))
+	if err != nil {
+		return fmt.Errorf("failed to ask for grade during lesson mode grade callback handling: %w", err)
+	}
+	_, err = bot.RequestCtx(ctx, tgbotapi.NewCallback(update.CallbackQuery.ID, ""))
+	if err != nil {
+		return fmt.Errorf("failed to answer lesson mode grade callback: %w", err)
+	}
+	return nil
+}
+
+// setOutcome writes the outcome to the sheet first, so that the button can be pressed again, if google fails.
+// Returns id of the outcome in the history
+func (handler *LessonModeCallbackHandler) setOutcome(ctx context.Context, admin *entities.User, lesson persistence.Lesson,
+	request entities.QueuedRequest, outcome entities.Outcome) (int64, error) {
+	err := handler.sheets.SetLabworkOutcome(ctx, &labworks.AppendedLabwork{
+		RequestedDate:  datetime.DateOnly(lesson.DateTime),
+		SentProofTime:  datetime.DateTime(request.SubmitTime),
+		DisciplineName: lesson.Subject,
+		GroupName:      admin.GroupName,
+		FullName:       request.FullName,
+		SubgroupNumber: int8(lesson.SubgroupNumber),
+		LabworkNumber:  request.LabworkNumber,
+	}, outcome)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write outcome of labwork to sheets: %w", err)
+	}
+	return handler.outcomes.Record(ctx, &entities.LabworkOutcome{
+		RequestId:     request.Id,
+		LessonId:      lesson.Id,
+		UserId:        request.UserId,
+		GroupId:       lesson.GroupId,
+		Subject:       lesson.Subject,
+		LabworkNumber: request.LabworkNumber,
+		Outcome:       outcome,
+		MarkedBy:      admin.TgId,
+	})
+}
+
+// notify pings the called student in the chat of the request. Failure doesn't stop the lesson, the student can be skipped
+func (handler *LessonModeCallbackHandler) notify(ctx context.Context, bot *tgutils.Bot, lesson persistence.Lesson, request entities.QueuedRequest) {
+	ctx = bot.Localize(ctx, request.UserId, "")
+	_, err := bot.SendCtx(ctx, tgbotapi.NewMessage(request.ChatId, i18n.T(ctx, i18n.LESSON_MODE_YOUR_TURN_TEXT, request.LabworkNumber, lesson.Subject)))
+	if err != nil {
+		slog.Error(fmt.Errorf("failed to notify user %d about their turn: %w", request.UserId, err).Error())
+	}
+}
+
+// admin returns the user, who pressed the button, or nil, if the user isn't an admin anymore
+func (handler *LessonModeCallbackHandler) admin(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) (*entities.User, error) {
+	user, err := handler.users.GetByTgId(ctx, update.SentFrom().ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user during lesson mode callback handling: %w", err)
+	}
+	if !slices.Contains(user.Roles, entities.Admin) {
+		_, err = bot.RequestCtx(ctx, tgbotapi.NewCallbackWithAlert(update.CallbackQuery.ID, i18n.T(ctx, i18n.NOT_ADMIN_TEXT)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to answer lesson mode callback of not admin: %w", err)
+		}
+		return nil, nil
+	}
+	return user, nil
+}
+
+func (handler *LessonModeCallbackHandler) edit(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot, text string,
+	keyboard tgbotapi.InlineKeyboardMarkup) error {
+	msg := update.CallbackQuery.Message
+	_, err := bot.SendCtx(ctx, tgbotapi.NewEditMessageTextAndMarkup(msg.Chat.ID, msg.MessageID, text, keyboard))
+	if err != nil {
+		return fmt.Errorf("failed to edit lesson mode message: %w", err)
+	}
+	_, err = bot.RequestCtx(ctx, tgbotapi.NewCallback(update.CallbackQuery.ID, ""))
+	if err != nil {
+		return fmt.Errorf("failed to answer lesson mode callback: %w", err)
+	}
+	return nil
+}
+
+// nextWaiting returns index of the first request without outcome after the given one, wrapping around the queue.
+// The given request itself is never returned, -1 is returned, if there are no other waiting requests
+func nextWaiting(queue []entities.QueuedRequest, after int) int {
+	for i := 1; i <= len(queue); i++ {
+		index := (after + i) % len(queue)
+		if index != after && queue[index].Outcome == entities.NoOutcome {
+			return index
+		}
+	}
+	return -1
+}
+
+func renderPanel(ctx context.Context, lesson persistence.Lesson, queue []entities.QueuedRequest, called int, marked *markedOutcome) (string,
+	tgbotapi.InlineKeyboardMarkup) {
+	var builder strings.Builder
+	builder.WriteString(i18n.T(ctx, i18n.LESSON_MODE_TITLE, lesson.Subject, lesson.DateTime.Format(LESSON_DATE_FORMAT)))
+	if len(queue) == 0 {
+		builder.WriteString(i18n.T(ctx, i18n.INLINE_QUEUE_EMPTY))
+	}
+	for i, request := range queue {
+		mark := outcomeMarks[request.Outcome]
+		if i == called {
+			mark = CURRENT_MARK
+		}
+		builder.WriteString(i18n.T(ctx, i18n.LESSON_MODE_LINE, mark, i+1, request.FullName, request.LabworkNumber))
+	}
+
+	button := func(key i18n.Key, action panelAction) tgbotapi.InlineKeyboardButton {
+		data := panelCallback{Action: action, LessonId: lesson.Id}
+		if called != -1 {
+			data.RequestId = queue[called].Id
+		}
+		return tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, key), PanelCodec.MustEncode(data))
+	}
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if called == -1 {
+		if len(queue) != 0 && nextWaiting(queue, -1) == -1 {
+			builder.WriteString(i18n.T(ctx, i18n.LESSON_MODE_FINISHED_TEXT))
+		}
+		// New requests can be accepted during the lesson, so the panel is never closed
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(button(i18n.LESSON_MODE_NEXT_BUTTON, actionNext)))
+	} else {
+		builder.WriteString(i18n.T(ctx, i18n.LESSON_MODE_CURRENT_TEXT, queue[called].FullName))
+		rows = append(rows,
+			tgbotapi.NewInlineKeyboardRow(
+				button(i18n.LESSON_MODE_PASSED_BUTTON, actionPassed),
+				button(i18n.LESSON_MODE_FAILED_BUTTON, actionFailed),
+				button(i18n.LESSON_MODE_ABSENT_BUTTON, actionAbsent),
+			),
+			tgbotapi.NewInlineKeyboardRow(button(i18n.LESSON_MODE_SKIP_BUTTON, actionSkip)),
+		)
+	}
+	if marked != nil {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+			i18n.T(ctx, i18n.LESSON_MODE_GRADE_BUTTON, marked.FullName), GradeCodec.MustEncode(gradeCallback{OutcomeId: marked.Id}))))
+	}
+	return builder.String(), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
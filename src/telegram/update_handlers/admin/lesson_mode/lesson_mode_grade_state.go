@@ -0,0 +1,95 @@
+package lessonmode
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	MIN_GRADE = 1
+	MAX_GRADE = 10
+)
+
+// LessonModeGradeState saves the grade and the comment of the outcome, which id is saved into info by the grade callback
+type LessonModeGradeState struct {
+	bot      *tgutils.Bot
+	cache    interfaces.HandlersCache
+	outcomes OutcomesRecorder
+}
+
+func NewLessonModeGradeState(bot *tgutils.Bot, cache interfaces.HandlersCache, outcomes OutcomesRecorder) *LessonModeGradeState {
+	return &LessonModeGradeState{bot: bot, cache: cache, outcomes: outcomes}
+}
+
+func (state *LessonModeGradeState) Handle(ctx context.Context, msg *tgbotapi.Message) error {
+	grade, comment, ok := parseGrade(msg.Text)
+	if !ok {
+		_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, i18n.T(ctx, i18n.LESSON_MODE_INVALID_GRADE_TEXT)))
+		if err != nil {
+			return fmt.Errorf("failed to send invalid grade message during lesson mode grade state: %w", err)
+		}
+		return nil
+	}
+	info, err := state.cache.GetInfo(ctx, msg.Chat.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get info during lesson mode grade state: %w", err)
+	}
+	outcomeId, err := strconv.ParseInt(info, 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse outcome id during lesson mode grade state: %w", err)
+	}
+	err = state.outcomes.SetGrade(ctx, outcomeId, grade, comment)
+	if err != nil {
+		return fmt.Errorf("failed to save grade during lesson mode grade state: %w", err)
+	}
+	err = state.finish(ctx, msg)
+	if err != nil {
+		return err
+	}
+	_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, i18n.T(ctx, i18n.LESSON_MODE_GRADE_SAVED_TEXT)))
+	if err != nil {
+		return fmt.Errorf("failed to send grade saved message during lesson mode grade state: %w", err)
+	}
+	return nil
+}
+
+func (state *LessonModeGradeState) Revert(ctx context.Context, msg *tgbotapi.Message) error {
+	return state.finish(ctx, msg)
+}
+
+func (state *LessonModeGradeState) finish(ctx context.Context, msg *tgbotapi.Message) error {
+	err := state.cache.RemoveInfo(ctx, msg.Chat.ID)
+	if err != nil {
+		return fmt.Errorf("failed to remove info during lesson mode grade state: %w", err)
+	}
+	err = state.cache.SaveState(ctx, *interfaces.NewCachedInfo(msg.Chat.ID, constants.IDLE_STATE))
+	if err != nil {
+		return fmt.Errorf("failed to save idle state during lesson mode grade state: %w", err)
+	}
+	return nil
+}
+
+// parseGrade splits text like "8 Good work" into the grade and the comment. Text without the leading number is the comment only
+func parseGrade(text string) (int8, string, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, "", false
+	}
+	first, rest, _ := strings.Cut(text, " ")
+	grade, err := strconv.Atoi(first)
+	if err != nil {
+		return 0, text, true
+	}
+	if grade < MIN_GRADE || grade > MAX_GRADE {
+		return 0, "", false
+	}
+	return int8(grade), strings.TrimSpace(rest), true
+}
@@ -0,0 +1,92 @@
+package lessonmode
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const LESSON_DATE_FORMAT = "02.01.2006 15:04"
+
+type LessonsRepository interface {
+	GetCurrentSubjects(ctx context.Context, groupId int64) ([]string, error)
+	GetNext(ctx context.Context, subject string, groupId int64) ([]persistence.Lesson, error)
+	Get(ctx context.Context, id int64) (persistence.Lesson, error)
+}
+
+type UsersRepository interface {
+	GetByTgId(ctx context.Context, tgId int64) (*entities.User, error)
+}
+
+// LessonModeStartState offers subjects of the group, the flow ends right away, since the panel works from idle state
+type LessonModeStartState struct {
+	bot      *tgutils.Bot
+	cache    interfaces.HandlersCache
+	users    UsersRepository
+	lessons  LessonsRepository
+	payloads *tgutils.CallbackPayloads
+}
+
+func NewLessonModeStartState(bot *tgutils.Bot, cache interfaces.HandlersCache, users UsersRepository, lessons LessonsRepository,
+	payloads *tgutils.CallbackPayloads) *LessonModeStartState {
+	return &LessonModeStartState{bot: bot, cache: cache, users: users, lessons: lessons, payloads: payloads}
+}
+
+func (state *LessonModeStartState) Handle(ctx context.Context, msg *tgbotapi.Message) error {
+	err := state.cache.SaveState(ctx, *interfaces.NewCachedInfo(msg.Chat.ID, constants.IDLE_STATE))
+	if err != nil {
+		return fmt.Errorf("failed to save idle state during lesson mode start state: %w", err)
+	}
+	user, err := state.users.GetByTgId(ctx, msg.From.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get user during lesson mode start state: %w", err)
+	}
+	// Lessons of today are the ones, which lesson mode is usually needed for
+	subjects, err := state.lessons.GetCurrentSubjects(ctx, user.GroupId)
+	if err != nil {
+		return fmt.Errorf("failed to get subjects during lesson mode start state: %w", err)
+	}
+	if len(subjects) == 0 {
+		_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, i18n.T(ctx, i18n.NO_SUBJECTS_TEXT)))
+		if err != nil {
+			return fmt.Errorf("failed to send no subjects response during lesson mode start state: %w", err)
+		}
+		return nil
+	}
+
+	var markup tgbotapi.InlineKeyboardMarkup
+	for chunk := range slices.Chunk(subjects, 3) {
+		var row []tgbotapi.InlineKeyboardButton
+		for _, subject := range chunk {
+			callback, err := tgutils.PutPayload(ctx, state.payloads, constants.LESSON_MODE_SUBJECT_CALLBACKS, subjectPayload{Subject: subject})
+			if err != nil {
+				return fmt.Errorf("failed to create subject callback during lesson mode start state: %w", err)
+			}
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(subject, callback))
+		}
+		markup.InlineKeyboard = append(markup.InlineKeyboard, row)
+	}
+	resp := tgbotapi.NewMessage(msg.Chat.ID, i18n.T(ctx, i18n.LESSON_MODE_CHOOSE_SUBJECT_TEXT))
+	resp.ReplyMarkup = markup
+	_, err = state.bot.SendCtx(ctx, resp)
+	if err != nil {
+		return fmt.Errorf("failed to send subjects during lesson mode start state: %w", err)
+	}
+	return nil
+}
+
+func (state *LessonModeStartState) Revert(ctx context.Context, msg *tgbotapi.Message) error {
+	return nil
+}
+
+type subjectPayload struct {
+	Subject string `json:"subject"`
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
 	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
@@ -12,20 +13,26 @@ import (
 )
 
 type ReorderLessonCallbackHandler struct {
-	bot   *tgutils.Bot
-	cache interfaces.HandlersCache
+	bot      *tgutils.Bot
+	cache    interfaces.HandlersCache
+	payloads *tgutils.CallbackPayloads
 }
 
-func NewReorderLessonCallbackHandler(bot *tgutils.Bot) *ReorderLessonCallbackHandler {
+func NewReorderLessonCallbackHandler(bot *tgutils.Bot, cache interfaces.HandlersCache, payloads *tgutils.CallbackPayloads) *ReorderLessonCallbackHandler {
 	return &ReorderLessonCallbackHandler{
-		bot: bot,
+		bot:      bot,
+		cache:    cache,
+		payloads: payloads,
 	}
 }
 
-func (handler *ReorderLessonCallbackHandler) Handle(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) error {
-	subject := parseLessonCallback(update.CallbackData())
+func (handler *ReorderLessonCallbackHandler) HandleCallback(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) error {
+	subject, err := parseLessonCallback(ctx, handler.payloads, update.CallbackData())
+	if err != nil {
+		return err
+	}
 	if subject == "" {
-		_, err := bot.SendCtx(ctx, tgbotapi.NewMessage(update.FromChat().ChatConfig().ChatID, "Выберите корректное название предмета"))
+		_, err := bot.SendCtx(ctx, tgbotapi.NewMessage(update.FromChat().ChatConfig().ChatID, i18n.T(ctx, i18n.REORDER_INVALID_SUBJECT_TEXT)))
 		if err != nil {
 			return fmt.Errorf("failed to send no lesson response during reorder lesson callback handling: %w", err)
 		}
@@ -59,7 +66,7 @@ func (handler *ReorderLessonCallbackHandler) Handle(ctx context.Context, update
 		return fmt.Errorf("failed to remove reply markup from message in reorder lesson callback handler: %w", err)
 	}
 	_, err = bot.SendCtx(ctx, tgbotapi.NewMessage(update.FromChat().ChatConfig().ChatID,
-		"Хотите ли вы применить данные правила ко всем предметам? Введите \"Да\"/любую другую последовательность символов"))
+		i18n.T(ctx, i18n.REORDER_APPLY_ALL_TEXT)))
 	if err != nil {
 		return fmt.Errorf("failed to send response during reorder lesson callback handler: %w", err)
 	}
@@ -79,7 +86,7 @@ func NewReorderConcreteLessonCallbackHandler(bot *tgutils.Bot, cache interfaces.
 	return &ReorderConcreteLessonCallbackHandler{bot: bot, cache: cache}
 }
 
-func (handler *ReorderConcreteLessonCallbackHandler) Handle(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) error {
+func (handler *ReorderConcreteLessonCallbackHandler) HandleCallback(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) error {
 	jsonedInfo, err := handler.cache.GetInfo(ctx, update.FromChat().ID)
 	if err != nil {
 		return fmt.Errorf("failed to get jsoned info from cache during concrete lesson callback handler: %w", err)
@@ -90,7 +97,7 @@ func (handler *ReorderConcreteLessonCallbackHandler) Handle(ctx context.Context,
 		return fmt.Errorf("failed to unmarshal jsoned info during reorder concrete lesson callback handler: %w", err)
 	}
 
-	lessonId, err := parseLessonConcreteCallback(update.CallbackData())
+	lessonId, err := ParseLessonConcreteCallback(update.CallbackData())
 	if err != nil {
 		return fmt.Errorf("failed to parse lesson concrete callback data: %w", err)
 	}
@@ -108,6 +115,10 @@ func (handler *ReorderConcreteLessonCallbackHandler) Handle(ctx context.Context,
 	if err != nil {
 		return fmt.Errorf("failed to remove reply markup during reorder concrete lesson callback handler: %w", err)
 	}
+	_, err = handler.bot.SendCtx(ctx, orderationMessage(ctx, update.FromChat().ID))
+	if err != nil {
+		return fmt.Errorf("failed to send response during reorder concrete lesson callback handler: %w", err)
+	}
 
 	err = handler.cache.SaveState(ctx, *interfaces.NewCachedInfo(update.FromChat().ID, constants.REORDER_REQUEST_METHOD_STATE))
 	if err != nil {
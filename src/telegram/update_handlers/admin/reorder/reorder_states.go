@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	iis_api_entities "github.com/aCrYoZPS/bsuir_queue_bot/src/iis_api/entities"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
@@ -24,7 +25,7 @@ type LessonsRepository interface {
 }
 
 type UsersRepository interface {
-	GetByTgId(ctx context.Context, tgId int64) (entities.User, error)
+	GetByTgId(ctx context.Context, tgId int64) (*entities.User, error)
 }
 
 type GroupsRepository interface {
@@ -36,11 +37,13 @@ type ReorderStartState struct {
 	bot     *tgutils.Bot
 	lessons LessonsRepository
 	users   UsersRepository
-	groups  GroupsRepository
+	groups   GroupsRepository
+	payloads *tgutils.CallbackPayloads
 }
 
-func NewReorderStartState(cache interfaces.HandlersCache, bot *tgutils.Bot, users UsersRepository, lessons LessonsRepository, groups GroupsRepository) *ReorderStartState {
-	return &ReorderStartState{cache: cache, bot: bot, users: users, lessons: lessons, groups: groups}
+func NewReorderStartState(cache interfaces.HandlersCache, bot *tgutils.Bot, users UsersRepository, lessons LessonsRepository, groups GroupsRepository,
+	payloads *tgutils.CallbackPayloads) *ReorderStartState {
+	return &ReorderStartState{cache: cache, bot: bot, users: users, lessons: lessons, groups: groups, payloads: payloads}
 }
 
 type ReorderInfo struct {
@@ -50,6 +53,7 @@ type ReorderInfo struct {
 	//Should equal 0, if all lessons flag is set
 	LessonId  int64  `json:"lesson_id,omitempty"`
 	GroupName string `json:"groupname,omitempty"`
+	GroupId   int64  `json:"group_id,omitempty"`
 }
 
 func (state *ReorderStartState) Handle(ctx context.Context, message *tgbotapi.Message) error {
@@ -67,15 +71,32 @@ func (state *ReorderStartState) Handle(ctx context.Context, message *tgbotapi.Me
 	if err != nil {
 		return fmt.Errorf("failed to get subjects during reorder start state: %w", err)
 	}
-	markup := state.markupFromSubjects(subjects)
-	resp := tgbotapi.NewMessage(message.Chat.ID, "Выберите предмет для изменения порядка очереди")
+	if len(subjects) == 0 {
+		_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.NO_SUBJECTS_TEXT)))
+		if err != nil {
+			return fmt.Errorf("failed to send no subjects response during reorder start state: %w", err)
+		}
+		err = state.cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, constants.IDLE_STATE))
+		if err != nil {
+			return fmt.Errorf("failed to save idle state during reorder start state: %w", err)
+		}
+		return nil
+	}
+	markup, err := state.markupFromSubjects(ctx, subjects)
+	if err != nil {
+		return fmt.Errorf("failed to create subjects markup during reorder start state: %w", err)
+	}
+	resp := tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.REORDER_CHOOSE_SUBJECT_TEXT))
 	resp.ReplyMarkup = markup
 	sended, err := state.bot.SendCtx(ctx, resp)
 	if err != nil {
 		return fmt.Errorf("failed to send response during reorder start state: %w", err)
 	}
 
-	jsonedInfo, err := json.Marshal(&ReorderInfo{MarkupMessageId: sended.MessageID, GroupName: userGroup.Name})
+	jsonedInfo, err := json.Marshal(&ReorderInfo{MarkupMessageId: sended.MessageID, GroupName: userGroup.Name, GroupId: user.GroupId})
+	if err != nil {
+		return fmt.Errorf("failed to marshal info during request reorder start state: %w", err)
+	}
 	err = state.cache.SaveInfo(ctx, message.Chat.ID, string(jsonedInfo))
 	if err != nil {
 		return fmt.Errorf("failed to save info during request reorder start state %w", err)
@@ -95,24 +116,40 @@ func (state *ReorderStartState) Revert(ctx context.Context, message *tgbotapi.Me
 	return nil
 }
 
-func (state *ReorderStartState) markupFromSubjects(subjects []string) tgbotapi.InlineKeyboardMarkup {
+func (state *ReorderStartState) markupFromSubjects(ctx context.Context, subjects []string) (tgbotapi.InlineKeyboardMarkup, error) {
 	var markup tgbotapi.InlineKeyboardMarkup
 	for chunk := range slices.Chunk(subjects, 3) {
 		var row []tgbotapi.InlineKeyboardButton
 		for _, subject := range chunk {
-			row = append(row, tgbotapi.NewInlineKeyboardButtonData(subject, lessonCallback(subject)))
+			callback, err := lessonCallback(ctx, state.payloads, subject)
+			if err != nil {
+				return markup, err
+			}
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(subject, callback))
 		}
 		markup.InlineKeyboard = append(markup.InlineKeyboard, row)
 	}
-	return markup
+	return markup, nil
 }
 
-func lessonCallback(subject string) (callback string) {
-	return constants.REORDER_LESSON_NAME_CALLBACK + "|" + subject
+type subjectPayload struct {
+	Subject string `json:"subject"`
 }
 
-func parseLessonCallback(callback string) (subject string) {
-	return strings.TrimPrefix(callback, constants.REORDER_LESSON_NAME_CALLBACK+"|")
+func lessonCallback(ctx context.Context, payloads *tgutils.CallbackPayloads, subject string) (callback string, err error) {
+	callback, err = tgutils.PutPayload(ctx, payloads, constants.REORDER_LESSON_NAME_CALLBACK, subjectPayload{Subject: subject})
+	if err != nil {
+		return "", fmt.Errorf("failed to create reorder lesson callback: %w", err)
+	}
+	return callback, nil
+}
+
+func parseLessonCallback(ctx context.Context, payloads *tgutils.CallbackPayloads, callback string) (subject string, err error) {
+	payload, err := tgutils.GetPayload[subjectPayload](ctx, payloads, constants.REORDER_LESSON_NAME_CALLBACK, callback)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse reorder lesson callback: %w", err)
+	}
+	return payload.Subject, nil
 }
 
 type StateMachine interface {
@@ -125,12 +162,12 @@ type ReorderWaitingState struct {
 	machine StateMachine
 }
 
-func NewReorderWaitingState(cache interfaces.HandlersCache, bot *tgutils.Bot) *ReorderWaitingState {
-	return &ReorderWaitingState{cache: cache, bot: bot}
+func NewReorderWaitingState(cache interfaces.HandlersCache, bot *tgutils.Bot, machine StateMachine) *ReorderWaitingState {
+	return &ReorderWaitingState{cache: cache, bot: bot, machine: machine}
 }
 
 func (state *ReorderWaitingState) Handle(ctx context.Context, message *tgbotapi.Message) error {
-	_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, "Пожалуйста, выберите предмет для изменения порядка очередности"))
+	_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.REORDER_CHOOSE_SUBJECT_AGAIN_TEXT)))
 	if err != nil {
 		return fmt.Errorf("failed to send response during reorder waiting state: %w", err)
 	}
@@ -175,17 +212,12 @@ func NewReorderChooseState(bot *tgutils.Bot, cache interfaces.HandlersCache, mac
 	return &ReorderChooseAllState{bot: bot, cache: cache, lessons: lessons, machine: machine, users: users}
 }
 
-func orderationMessage(chatId int64) tgbotapi.MessageConfig {
-	text := "Выберите способы сортировки данных,через запятую (порядок важен, сортировка будет применена в указанном порядке).\n1 - по времени отправки. 2 - по номеру лабораторной. Добавьте префикс + к номеру, если хотите установить сортировку по убыванию"
+func orderationMessage(ctx context.Context, chatId int64) tgbotapi.MessageConfig {
+	text := i18n.T(ctx, i18n.REORDER_CHOOSE_ORDER_TEXT)
 	return tgbotapi.NewMessage(chatId, text)
 }
 
-// WRITE CHOOSE LESSON STATE, FOR CASES WHEN WE WANT TO ORDERATE A SPECIFIC ONE
 func (state *ReorderChooseAllState) Handle(ctx context.Context, message *tgbotapi.Message) error {
-	err := state.cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, constants.REORDER_REQUEST_METHOD_STATE))
-	if err != nil {
-		return fmt.Errorf("failed to save state during reorder choose state handling: %w", err)
-	}
 	jsonedInfo, err := state.cache.GetInfo(ctx, message.Chat.ID)
 	if err != nil {
 		return fmt.Errorf("failed to get jsoned info during reorder choose state: %w", err)
@@ -195,7 +227,7 @@ func (state *ReorderChooseAllState) Handle(ctx context.Context, message *tgbotap
 	if err != nil {
 		return fmt.Errorf("failed to unmarshal json into reorder info: %w", err)
 	}
-	if message.Text == "Да" {
+	if message.Text == i18n.T(ctx, i18n.YES) {
 		info.AllLessons = true
 		jsonInfo, err := json.Marshal(info)
 		if err != nil {
@@ -205,6 +237,14 @@ func (state *ReorderChooseAllState) Handle(ctx context.Context, message *tgbotap
 		if err != nil {
 			return fmt.Errorf("failed to sace info during reorder choose state: %w", err)
 		}
+		err = state.cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, constants.REORDER_REQUEST_METHOD_STATE))
+		if err != nil {
+			return fmt.Errorf("failed to save state during reorder choose state handling: %w", err)
+		}
+		_, err = state.bot.SendCtx(ctx, orderationMessage(ctx, message.Chat.ID))
+		if err != nil {
+			return fmt.Errorf("faield to send response during reorder choose state: %w", err)
+		}
 	} else {
 		usr, err := state.users.GetByTgId(ctx, message.From.ID)
 		if err != nil {
@@ -214,6 +254,17 @@ func (state *ReorderChooseAllState) Handle(ctx context.Context, message *tgbotap
 		if err != nil {
 			return fmt.Errorf("failed to get next lessons during reorder choose all state: %w", err)
 		}
+		if len(next) == 0 {
+			_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.NO_LESSONS_TEXT)))
+			if err != nil {
+				return fmt.Errorf("failed to send no lessons response during reorder choose all state: %w", err)
+			}
+			err = state.cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, constants.IDLE_STATE))
+			if err != nil {
+				return fmt.Errorf("failed to save idle state during reorder choose all state: %w", err)
+			}
+			return nil
+		}
 		var keyboard tgbotapi.InlineKeyboardMarkup
 
 		for chunk := range slices.Chunk(next, 3) {
@@ -223,7 +274,7 @@ func (state *ReorderChooseAllState) Handle(ctx context.Context, message *tgbotap
 				if lesson.SubgroupNumber != 0 {
 					buttonVisual += fmt.Sprintf(" (%d)", lesson.SubgroupNumber)
 				}
-				row = append(row, tgbotapi.NewInlineKeyboardButtonData(buttonVisual, constants.REORDER_LESSON_CONCRETE_CALLBACK))
+				row = append(row, tgbotapi.NewInlineKeyboardButtonData(buttonVisual, createLessonConcreteCallback(lesson)))
 			}
 			keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, row)
 		}
@@ -233,7 +284,7 @@ func (state *ReorderChooseAllState) Handle(ctx context.Context, message *tgbotap
 			return fmt.Errorf("failed to save choose lesson state during reorder choose state: %w", err)
 		}
 
-		resp := tgbotapi.NewMessage(message.Chat.ID, "Выберите занятие для изменения сортировки")
+		resp := tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.REORDER_CHOOSE_LESSON_TEXT))
 		resp.ReplyMarkup = keyboard
 		sentResponse, err := state.bot.SendCtx(ctx, resp)
 		if err != nil {
@@ -250,19 +301,26 @@ func (state *ReorderChooseAllState) Handle(ctx context.Context, message *tgbotap
 			return fmt.Errorf("failed to save info during requesr reorder choose all state: %w", err)
 		}
 	}
-	_, err = state.bot.SendCtx(ctx, orderationMessage(message.Chat.ID))
-	if err != nil {
-		return fmt.Errorf("faield to send response during reorder choose state: %w", err)
-	}
 	return nil
 }
 
+type lessonConcreteCallback struct {
+	LessonId int64
+}
+
+var lessonConcreteCodec = tgutils.NewCallbackCodec[lessonConcreteCallback](constants.REORDER_LESSON_CONCRETE_CALLBACK, 1)
+
 func createLessonConcreteCallback(lesson persistence.Lesson) string {
-	return fmt.Sprintf("%s|%d", constants.REORDER_LESSON_CONCRETE_CALLBACK, lesson.Id)
+	return lessonConcreteCodec.MustEncode(lessonConcreteCallback{LessonId: lesson.Id})
 }
 
-func parseLessonConcreteCallback(callback string) (id int64, err error) {
-	return strconv.ParseInt(callback, 10, 64)
+// ParseLessonConcreteCallback returns id of the lesson, which queue is reordered
+func ParseLessonConcreteCallback(callback string) (id int64, err error) {
+	data, err := lessonConcreteCodec.Decode(callback)
+	if err != nil {
+		return 0, err
+	}
+	return data.LessonId, nil
 }
 
 func (state *ReorderChooseAllState) Revert(ctx context.Context, message *tgbotapi.Message) error {
@@ -290,7 +348,7 @@ func NewReorderChooseLessonState(cache interfaces.HandlersCache, bot *tgutils.Bo
 }
 
 func (state *ReorderChooseLessonState) Handle(ctx context.Context, msg *tgbotapi.Message) error {
-	_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, "Пожалуйста, выберите занятие для изменения сортировки"))
+	_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, i18n.T(ctx, i18n.REORDER_CHOOSE_LESSON_AGAIN_TEXT)))
 	if err != nil {
 		return fmt.Errorf("failed to send response during reorder choose lesson state: %w", err)
 	}
@@ -313,6 +371,14 @@ func (state *ReorderChooseLessonState) Revert(ctx context.Context, msg *tgbotapi
 	if err != nil {
 		return fmt.Errorf("failed to remove reply markup during reorder choose lesson state: %w", err)
 	}
+	_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, i18n.T(ctx, i18n.REORDER_APPLY_ALL_TEXT)))
+	if err != nil {
+		return fmt.Errorf("failed to send response during reorder choose lesson state reversal: %w", err)
+	}
+	err = state.cache.SaveState(ctx, *interfaces.NewCachedInfo(msg.Chat.ID, constants.REORDER_CHOOSE_STATE))
+	if err != nil {
+		return fmt.Errorf("failed to save state during reorder choose lesson state reversal: %w", err)
+	}
 	return nil
 }
 
@@ -328,21 +394,33 @@ type ReorderMethodState struct {
 	sheets   SheetsApi
 	lessons  LessonsRepository
 	machine  StateMachine
+	queues   QueueRefresher
 }
 
 type LessonRequestsRepository interface {
-	ChangeOrderation(context.Context, []entities.OrderType, int64) error
-	ChangeSubjectOrderation(context.Context, []entities.OrderType, string) error
+	ChangeOrderation(ctx context.Context, orderTypes []entities.OrderType, lessonId int64) error
+	ChangeOrderationSubject(ctx context.Context, orderTypes []entities.OrderType, subject string, groupId int64) error
+}
+
+// QueueRefresher edits messages, which are subscribed to queues of the reordered lessons
+type QueueRefresher interface {
+	Refresh(lessonId int64)
+	RefreshSubject(ctx context.Context, subject string, groupId int64)
 }
 
-func NewReorderMethodState(cache interfaces.HandlersCache, bot *tgutils.Bot, requests LessonRequestsRepository, sheets SheetsApi) *ReorderMethodState {
-	return &ReorderMethodState{cache: cache, bot: bot, requests: requests, sheets: sheets}
+func NewReorderMethodState(cache interfaces.HandlersCache, bot *tgutils.Bot, requests LessonRequestsRepository, sheets SheetsApi,
+	lessons LessonsRepository, machine StateMachine, queues QueueRefresher) *ReorderMethodState {
+	return &ReorderMethodState{cache: cache, bot: bot, requests: requests, sheets: sheets, lessons: lessons, machine: machine, queues: queues}
 }
 
 func (state *ReorderMethodState) Handle(ctx context.Context, message *tgbotapi.Message) error {
 	methods, err := state.parseMessage(message)
 	if err != nil {
-		return fmt.Errorf("failed to parse reorder method message: %w", err)
+		_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.REORDER_INVALID_ORDER_TEXT)))
+		if err != nil {
+			return fmt.Errorf("failed to send invalid order response during reorder method state: %w", err)
+		}
+		return nil
 	}
 	orderTypes := []entities.OrderType{}
 	for _, method := range methods {
@@ -359,10 +437,11 @@ func (state *ReorderMethodState) Handle(ctx context.Context, message *tgbotapi.M
 	}
 
 	if info.AllLessons {
-		err = state.requests.ChangeSubjectOrderation(ctx, orderTypes, info.Subject)
+		err = state.requests.ChangeOrderationSubject(ctx, orderTypes, info.Subject, info.GroupId)
 		if err != nil {
 			return fmt.Errorf("failed to change orderation of subject in db during reorder method state: %w", err)
 		}
+		state.queues.RefreshSubject(ctx, info.Subject, info.GroupId)
 		err = state.sheets.ReorderLessons(ctx, orderTypes, info.GroupName, info.Subject)
 		if err != nil {
 			return fmt.Errorf("failed to reorder subject in google sheets during reorder method state: %w", err)
@@ -372,13 +451,14 @@ func (state *ReorderMethodState) Handle(ctx context.Context, message *tgbotapi.M
 		if err != nil {
 			return fmt.Errorf("failed to change orderation of lesson in db during reorder method state: %w", err)
 		}
+		state.queues.Refresh(info.LessonId)
 
 		lesson, err := state.lessons.Get(ctx, info.LessonId)
 		if err != nil {
 			return fmt.Errorf("failed to get lesson by id during reorder method state: %w", err)
 		}
 
-		err = state.sheets.ReorderLesson(ctx,orderTypes, info.GroupName, lesson)
+		err = state.sheets.ReorderLesson(ctx, orderTypes, info.GroupName, lesson)
 		if err != nil {
 			return fmt.Errorf("failed to reorder lesson in google sheets during reorder method state: %w", err)
 		}
@@ -388,7 +468,14 @@ func (state *ReorderMethodState) Handle(ctx context.Context, message *tgbotapi.M
 	if err != nil {
 		return fmt.Errorf("failed to save idle state during reorder method state: %w", err)
 	}
-
+	err = state.cache.RemoveInfo(ctx, message.Chat.ID)
+	if err != nil {
+		return fmt.Errorf("failed to remove info during reorder method state: %w", err)
+	}
+	_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.REORDER_DONE_TEXT)))
+	if err != nil {
+		return fmt.Errorf("failed to send response during reorder method state: %w", err)
+	}
 	return nil
 }
 
@@ -410,11 +497,14 @@ func (state *ReorderMethodState) parseMessage(message *tgbotapi.Message) ([]stru
 	}{}
 	parts := strings.Split(message.Text, ",")
 	for _, part := range parts {
-		after, found := strings.CutPrefix(part, "+")
+		after, found := strings.CutPrefix(strings.TrimSpace(part), "+")
 		order, err := strconv.ParseInt(after, 10, 8)
 		if err != nil {
 			return nil, err
 		}
+		if entities.OrderField(order) != entities.BySubmission && entities.OrderField(order) != entities.ByLabworkNumber {
+			return nil, fmt.Errorf("unknown order type %d", order)
+		}
 		returned = append(returned, struct {
 			orderation int8
 			ascending  bool
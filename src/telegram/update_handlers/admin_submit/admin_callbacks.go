@@ -2,6 +2,7 @@ package admin
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	adminInterfaces "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/admin_submit/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
@@ -25,15 +27,17 @@ type AdminCallbackHandler struct {
 	requests  interfaces.AdminRequestsRepository
 	cache     interfaces.HandlersCache
 	lessons   adminInterfaces.LessonsService
+	menu      adminInterfaces.CommandsMenu
 }
 
 func NewAdminCallbackHandler(usersRepo interfaces.UsersRepository, cache interfaces.HandlersCache,
-	 requests interfaces.AdminRequestsRepository, lessons adminInterfaces.LessonsService) *AdminCallbackHandler {
+	 requests interfaces.AdminRequestsRepository, lessons adminInterfaces.LessonsService, menu adminInterfaces.CommandsMenu) *AdminCallbackHandler {
 	return &AdminCallbackHandler{
 		usersRepo: usersRepo,
 		cache:     cache,
 		requests:  requests,
 		lessons:   lessons,
+		menu:      menu,
 	}
 }
 
@@ -80,13 +84,17 @@ func (handler *AdminCallbackHandler) handleAcceptCallback(ctx context.Context, m
 	if err != nil {
 		return err
 	}
+	err = handler.menu.Refresh(ctx, form.UserId)
+	if err != nil {
+		return fmt.Errorf("failed to refresh commands of new admin during admin accept callback handling: %w", err)
+	}
 
 	url, err := handler.lessons.AddGroupLessons(ctx, form.Group)
 	if err != nil {
 		return err
 	}
 
-	resp := tgbotapi.NewMessage(form.UserId, fmt.Sprintf("Ваша заявка была одобрена. Ссылка на гугл-таблицу: %s", url))
+	resp := tgbotapi.NewMessage(form.UserId, i18n.T(bot.Localize(ctx, form.UserId, ""), i18n.ADMIN_REQUEST_APPROVED_TEXT, url))
 
 	user, err := handler.usersRepo.GetByTgId(ctx, form.UserId)
 	if err != nil {
@@ -151,8 +159,8 @@ func (handler *AdminCallbackHandler) handleDeclineCallback(ctx context.Context,
 	if err != nil {
 		return err
 	}
-	resp := tgbotapi.NewMessage(form.UserId, "Ваша заявка была отклонена")
-	_, err = bot.Send(resp)
+	resp := tgbotapi.NewMessage(form.UserId, i18n.T(bot.Localize(ctx, form.UserId, ""), i18n.REQUEST_DECLINED_TEXT))
+	_, err = bot.SendCtx(ctx, resp)
 	return err
 }
 
@@ -170,7 +178,7 @@ func (handler *AdminCallbackHandler) RemoveMarkupFromOwners(ctx context.Context,
 		if err != nil {
 			return err
 		}
-		_, err := bot.Send(tgbotapi.NewEditMessageReplyMarkup(request.ChatId, int(request.MsgId), 
+		_, err := bot.SendCtx(ctx, tgbotapi.NewEditMessageReplyMarkup(request.ChatId, int(request.MsgId), 
 		tgbotapi.NewInlineKeyboardMarkup([]tgbotapi.InlineKeyboardButton{})))
 		if err != nil {
 			return err
@@ -178,3 +186,21 @@ func (handler *AdminCallbackHandler) RemoveMarkupFromOwners(ctx context.Context,
 	}
 	return nil
 }
+
+// RequestCallbackKey keys accept and decline callbacks by the admin request uuid, shared by its copies sent to every owner
+func RequestCallbackKey(requests interfaces.AdminRequestsRepository) tgutils.CallbackKeyFunc {
+	return func(ctx context.Context, update *tgbotapi.Update) (string, error) {
+		msg := update.CallbackQuery.Message
+		if msg == nil {
+			return "", nil
+		}
+		request, err := requests.GetByMsg(ctx, int64(msg.MessageID), msg.Chat.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", tgutils.ErrCallbackProcessed
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to get admin request of the callback: %w", err)
+		}
+		return constants.ADMIN_CALLBACKS + request.UUID, nil
+	}
+}
@@ -6,15 +6,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"os"
 	"slices"
-	"strconv"
-	"strings"
 	"text/template"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
 	stateErrors "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/errors"
@@ -31,9 +27,6 @@ type adminSubmitForm struct {
 	AdditionalInfo string `json:"info,omitempty"`
 }
 
-const infoTemplate = "(ЗАЯВКА НА РОЛЬ АДМИНИСТРАТОРА)\nИмя: {{.Name}} \nГруппа: {{.Group}}\nИмя пользователя: @{{.TgName}} \n" + 
-"{{if .AdditionalInfo}}Доп информация: {{.AdditionalInfo}} {{end}}"
-
 type adminSubmitStartState struct {
 	cache           interfaces.HandlersCache
 	usersRepository interfaces.UsersRepository
@@ -52,7 +45,7 @@ func (state *adminSubmitStartState) Handle(ctx context.Context, message *tgbotap
 	}
 	if slices.Contains(user.Roles, entities.Admin) {
 		err = state.TransitionAndSend(ctx, interfaces.NewCachedInfo(message.Chat.ID, constants.IDLE_STATE), 
-		tgbotapi.NewMessage(message.Chat.ID, "Вы уже админ группы"))
+		tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.ALREADY_ADMIN_TEXT)))
 		return err
 	}
 	if user.Id != 0 {
@@ -72,7 +65,7 @@ func (state *adminSubmitStartState) Handle(ctx context.Context, message *tgbotap
 		return nil
 	}
 	err = state.TransitionAndSend(ctx, interfaces.NewCachedInfo(message.Chat.ID, constants.ADMIN_SUBMITTING_NAME_STATE),
-		tgbotapi.NewMessage(message.Chat.ID, "Введите ваши фамилию и имя (Пример формата: Иванов Иван)"))
+		tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.ENTER_FULL_NAME_TEXT)))
 	return err
 }
 
@@ -133,7 +126,7 @@ func (state *adminSubmittingNameState) Handle(ctx context.Context, message *tgbo
 	if err != nil {
 		return fmt.Errorf("failed to save state during transition from admin submitting name to submitting group: %w", err)
 	}
-	msg := tgbotapi.NewMessage(message.Chat.ID, "Введите ваш номер группы, указанный в ИИСе")
+	msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.ENTER_IIS_GROUP_TEXT))
 	_, err = state.bot.SendCtx(ctx, msg)
 	if err != nil {
 		return fmt.Errorf("failed to send message during admin submitting name: %w", err)
@@ -176,7 +169,7 @@ func (state *adminSubmitingGroupState) Handle(ctx context.Context, message *tgbo
 		return fmt.Errorf("failed to check if group exists during admin submitting group state: %w", err)
 	}
 	if !exists {
-		msg := tgbotapi.NewMessage(message.Chat.ID, "Введите номер существующей в ИИСе группы")
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.INVALID_IIS_GROUP_TEXT))
 		_, err := state.bot.SendCtx(ctx, msg)
 		if err != nil {
 			return fmt.Errorf("failed to send group not exists message during admin submitting group: %w", err)
@@ -208,7 +201,7 @@ func (state *adminSubmitingGroupState) Handle(ctx context.Context, message *tgbo
 		return fmt.Errorf("failed save new state during transitioning from admin submitting group to admin submitting proof")
 	}
 	msg := tgbotapi.NewMessage(message.Chat.ID, 
-		"Предоставьте доказательство вверенных группой полномочий (в виде фото, с дополнительной текстовой информацией по усмотрению)")
+		i18n.T(ctx, i18n.ENTER_ADMIN_PROOF_TEXT))
 	_, err = state.bot.SendCtx(ctx, msg)
 	if err != nil {
 		return fmt.Errorf("failed to send message at the end of admin submitting group")
@@ -221,7 +214,7 @@ func (state *adminSubmitingGroupState) Revert(ctx context.Context, msg *tgbotapi
 	if err != nil {
 		return fmt.Errorf("failed to save admin submitting name state during admin submitting group state reversal: %w", err)
 	}
-	_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, "Введите ваши фамилию и имя (Пример формата: Иванов Иван)"))
+	_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, i18n.T(ctx, i18n.ENTER_FULL_NAME_TEXT)))
 	if err != nil {
 		return fmt.Errorf("failed to send reversal message during admin submitting group state: %w", err)
 	}
@@ -242,7 +235,7 @@ func NewAdminSubmitingProofState(cache interfaces.HandlersCache, bot *tgutils.Bo
 
 func (state *adminSubmittingProofState) Handle(ctx context.Context, message *tgbotapi.Message) error {
 	if message.Photo == nil {
-		_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, "Отправьте фото как часть сообщения"))
+		_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.SEND_PHOTO_TEXT)))
 		if err != nil {
 			return fmt.Errorf("failed to send no photo message during admin submitting proof: %w", err)
 		}
@@ -261,7 +254,7 @@ func (state *adminSubmittingProofState) Handle(ctx context.Context, message *tgb
 	form.AdditionalInfo = message.Caption
 
 	maxSizeId := selectMaxSizedPhoto(message.Photo)
-	fileBytes, err := state.getFileBytes(maxSizeId)
+	fileBytes, err := state.bot.DownloadFile(ctx, maxSizeId)
 	if err != nil {
 		return fmt.Errorf("failed to get file bytes for photo during admin submitting proof: %w", err)
 	}
@@ -271,7 +264,8 @@ func (state *adminSubmittingProofState) Handle(ctx context.Context, message *tgb
 		return fmt.Errorf("failed to save state during transitioning from admin proof to admin waiting")
 	}
 
-	msg := state.createTemplateResponse(message.Chat.ID, form, fileBytes)
+	// Owners aren't necessarily members of any group, so requests are sent to them in the default locale
+	msg := state.createTemplateResponse(i18n.WithLocale(ctx, i18n.DEFAULT_LOCALE), message.Chat.ID, form, fileBytes)
 	return state.sendPhotoToOwners(ctx, message.Chat.ID, *msg, state.bot)
 }
 
@@ -295,7 +289,7 @@ func NewAdminWaitingProofState(cache interfaces.HandlersCache, bot *tgutils.Bot)
 }
 
 func (state *adminWaitingState) Handle(ctx context.Context, message *tgbotapi.Message) error {
-	msg := tgbotapi.NewMessage(message.From.ID, "Подождите,ваш запрос на роль администратора ещё обрабатывается")
+	msg := tgbotapi.NewMessage(message.From.ID, i18n.T(ctx, i18n.ADMIN_REQUEST_PENDING_TEXT))
 	_, err := state.bot.SendCtx(ctx, msg)
 	if err != nil {
 		return fmt.Errorf("failed to send message to user during admin waiting state: %w", err)
@@ -307,11 +301,12 @@ func (state *adminWaitingState) Revert(ctx context.Context, msg *tgbotapi.Messag
 	return nil
 }
 
-func createMarkupKeyboard(form *adminSubmitForm) *tgbotapi.InlineKeyboardMarkup {
+func createMarkupKeyboard(ctx context.Context, form *adminSubmitForm) *tgbotapi.InlineKeyboardMarkup {
 	row := []tgbotapi.InlineKeyboardButton{}
-	acceptData := constants.ADMIN_CALLBACKS + "accept" + fmt.Sprint(form.UserId)
-	declineData := constants.ADMIN_CALLBACKS + "decline" + fmt.Sprint(form.UserId)
-	row = append(row, tgbotapi.NewInlineKeyboardButtonData("Accept", acceptData), tgbotapi.NewInlineKeyboardButtonData("Decline", declineData))
+	acceptData := constants.ADMIN_ACCEPT_CALLBACKS + fmt.Sprint(form.UserId)
+	declineData := constants.ADMIN_DECLINE_CALLBACKS + fmt.Sprint(form.UserId)
+	row = append(row, tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ACCEPT_BUTTON), acceptData),
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.DECLINE_BUTTON), declineData))
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(row)
 	return &keyboard
 }
@@ -327,58 +322,35 @@ func selectMaxSizedPhoto(sizes []tgbotapi.PhotoSize) string {
 	return maxSizeId
 }
 
-func (state *adminSubmittingProofState) createTemplateResponse(chatId int64, form *adminSubmitForm, 
+func (state *adminSubmittingProofState) createTemplateResponse(ctx context.Context, chatId int64, form *adminSubmitForm, 
 	fileBytes []byte) *tgbotapi.PhotoConfig {
 	msg := tgbotapi.NewPhoto(chatId, tgbotapi.FileBytes{Name: "rnd_name", Bytes: fileBytes})
 	var buf bytes.Buffer
-	tmpl := template.Must(template.New("tmpl").Parse(infoTemplate))
+	tmpl := template.Must(template.New("tmpl").Parse(i18n.T(ctx, i18n.ADMIN_REQUEST_TEMPLATE)))
 	tmpl.Execute(&buf, form)
 	msg.Caption = buf.String()
-	msg.ReplyMarkup = createMarkupKeyboard(form)
+	msg.ReplyMarkup = createMarkupKeyboard(ctx, form)
 	return &msg
 }
 
-func (state *adminSubmittingProofState) getFileBytes(fileId string) ([]byte, error) {
-	file, err := state.bot.GetFile(tgbotapi.FileConfig{FileID: fileId})
-	if err != nil {
-		return nil, err
-	}
-	link := file.Link(os.Getenv("BOT_TOKEN"))
-	resp, err := http.Get(link)
-	if err != nil && err != io.EOF {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	bytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	return bytes, nil
-}
-
 func (state *adminSubmittingProofState) sendPhotoToOwners(ctx context.Context, senderChatId int64, msg tgbotapi.PhotoConfig, 
 	bot *tgutils.Bot) error {
-	owners := strings.Split(os.Getenv("OWNERS"), ",")
-
-	for _, owner := range owners {
-		chatId, err := strconv.ParseInt(owner, 10, 64)
-		if err != nil {
-			return errors.Join(err, fmt.Errorf("invalid owner id value %s", owner))
-		}
-		msg.ChatID = chatId
+	reqUUID := uuid.NewString()
+	for _, owner := range tgutils.GetOwners() {
+		msg.ChatID = owner
 		sentMsg, err := bot.SendCtx(ctx, msg)
 		if err != nil {
 			if errors.Is(err, tgutils.ErrMsgInvalidLen) {
 				_, err := bot.SendCtx(ctx,
-					 tgbotapi.NewMessage(senderChatId, "Ваша заявка превысила допустимый лимит длины. Пожалуйста,перепишите её и отправьте снова"))
+					 tgbotapi.NewMessage(senderChatId, i18n.T(ctx, i18n.MESSAGE_TOO_LONG_TEXT)))
 				if err != nil {
 					return fmt.Errorf("failed to send too long response during admin submitting proof state: %w", err)
 				}
 				return nil
 			}
-			return fmt.Errorf("failed to send msg to owner id %s during admin proof submit: %w", owner, err)
+			return fmt.Errorf("failed to send msg to owner id %d during admin proof submit: %w", owner, err)
 		}
-		err = state.requests.SaveRequest(ctx, interfaces.NewAdminRequest(int64(sentMsg.MessageID), sentMsg.Chat.ID, uuid.NewString()))
+		err = state.requests.SaveRequest(ctx, interfaces.NewAdminRequest(int64(sentMsg.MessageID), sentMsg.Chat.ID, reqUUID))
 		if err != nil {
 			return err
 		}
@@ -6,4 +6,8 @@ import (
 
 type LessonsService interface {
 	AddGroupLessons(ctx context.Context, groupName string) (url string, err error)
+}
+
+type CommandsMenu interface {
+	Refresh(ctx context.Context, tgId int64) error
 }
\ No newline at end of file
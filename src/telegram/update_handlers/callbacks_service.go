@@ -12,14 +12,16 @@ import (
 )
 
 type CallbacksService struct {
-	cache   interfaces.HandlersCache
-	handler CallbackHandler
+	cache    interfaces.HandlersCache
+	handler  CallbackHandler
+	reporter *CrashReporter
 }
 
-func NewCallbackService(cache interfaces.HandlersCache, handler CallbackHandler) *CallbacksService {
+func NewCallbackService(cache interfaces.HandlersCache, handler CallbackHandler, reporter *CrashReporter) *CallbacksService {
 	return &CallbacksService{
-		cache:   cache,
-		handler: handler,
+		cache:    cache,
+		handler:  handler,
+		reporter: reporter,
 	}
 }
 
@@ -27,34 +29,40 @@ type CallbackHandler interface {
 	HandleCallback(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) error
 }
 
-func (serv *CallbacksService) HandleCallbacks(update *tgbotapi.Update, bot *tgutils.Bot) {
+func (serv *CallbacksService) HandleCallbacks(update *tgbotapi.Update, bot *tgutils.Bot) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			slog.Error("Recovered from panic", "err", r)
-			debug.PrintStack()
+			err = serv.reporter.ReportPanic(update, r, debug.Stack())
 		}
 	}()
 	ctx, cancel := context.WithTimeout(context.Background(), constants.DEFAULT_TIMEOUT)
 	defer cancel()
 	if update.CallbackQuery == nil {
 		slog.Error("no callback in update")
-		return
+		return nil
 	}
 	msg := update.CallbackQuery.Message
 	if msg == nil {
-		return
+		return nil
 	}
 	mu := serv.cache.AcquireLock(ctx, msg.Chat.ID, update.CallbackData())
 	locked := mu.TryLock()
+	// The same callback is already being handled
 	if !locked {
-		return
+		return nil
 	}
 
 	defer mu.Unlock()
 	defer serv.cache.ReleaseLock(ctx, msg.Chat.ID, update.CallbackData())
+	// Callbacks hold the lock of the chat too, so that expiration of stale flows doesn't reset the flow in the middle of the callback
+	chatMu := serv.cache.AcquireLock(ctx, msg.Chat.ID, "")
+	chatMu.Lock()
+	defer chatMu.Unlock()
+	defer serv.cache.ReleaseLock(ctx, msg.Chat.ID, "")
 
-	err := serv.handler.HandleCallback(ctx, update, bot)
+	err = serv.handler.HandleCallback(ctx, update, bot)
 	if err != nil {
-		slog.Error(err.Error())
+		serv.reporter.Report(update, err)
 	}
+	return err
 }
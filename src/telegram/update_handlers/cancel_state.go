@@ -0,0 +1,46 @@
+package update_handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// cancelState is called by mux after the flow of the chat was reset by cancel command
+type cancelState struct {
+	bot   *tgutils.Bot
+	users interfaces.UsersRepository
+}
+
+func NewCancelState(bot *tgutils.Bot, users interfaces.UsersRepository) *cancelState {
+	return &cancelState{bot: bot, users: users}
+}
+
+func (state *cancelState) Handle(ctx context.Context, message *tgbotapi.Message) error {
+	// Reply keyboard would be shown to all of the members of group chat
+	if !message.Chat.IsPrivate() {
+		_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.CANCELLED_TEXT)))
+		if err != nil {
+			return fmt.Errorf("failed to send cancel message during handling cancel command: %w", err)
+		}
+		return nil
+	}
+	user, err := state.users.GetByTgId(ctx, message.From.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get user by id during handling cancel command: %w", err)
+	}
+	msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.CANCELLED_TEXT))
+	err = tgutils.CreateStartReplyMarkup(ctx, &msg, user, state.bot)
+	if err != nil {
+		return fmt.Errorf("failed to create start reply markup during cancel command: %w", err)
+	}
+	return nil
+}
+
+func (state *cancelState) Revert(ctx context.Context, message *tgbotapi.Message) error {
+	return nil
+}
@@ -0,0 +1,146 @@
+package update_handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// SetDefaults makes a request per user with scoped commands, so it takes much longer than handling of updates
+const SET_DEFAULTS_TIMEOUT = 10 * time.Minute
+
+type MenuUsersRepository interface {
+	GetByTgId(ctx context.Context, tgId int64) (*entities.User, error)
+	GetAll(ctx context.Context) ([]entities.User, error)
+	GetLanguage(ctx context.Context, tgId int64) (string, error)
+}
+
+// CommandsMenu sets commands, shown by telegram clients in "/" menu. Students get commands of all private chats
+// in language of their client, while admins, owners and users, who have chosen language, get commands,
+// scoped to their private chats with the bot
+type CommandsMenu struct {
+	bot   *tgutils.Bot
+	users MenuUsersRepository
+}
+
+func NewCommandsMenu(bot *tgutils.Bot, users MenuUsersRepository) *CommandsMenu {
+	return &CommandsMenu{bot: bot, users: users}
+}
+
+// SetDefaults sets commands of private and group chats for every locale and refreshes scoped commands of the users
+func (menu *CommandsMenu) SetDefaults(ctx context.Context) error {
+	for _, locale := range i18n.Locales {
+		err := menu.setDefaults(ctx, locale, string(locale))
+		if err != nil {
+			return err
+		}
+	}
+	// Commands without language code are shown to clients with languages, which aren't supported
+	err := menu.setDefaults(ctx, i18n.DEFAULT_LOCALE, "")
+	if err != nil {
+		return err
+	}
+
+	users, err := menu.users.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get users during setting default commands: %w", err)
+	}
+	scoped := tgutils.GetOwners()
+	for _, user := range users {
+		if slices.Contains(scoped, user.TgId) {
+			continue
+		}
+		language, err := menu.users.GetLanguage(ctx, user.TgId)
+		if err != nil {
+			return fmt.Errorf("failed to get language of user %d during setting default commands: %w", user.TgId, err)
+		}
+		if slices.Contains(user.Roles, entities.Admin) || language != "" {
+			scoped = append(scoped, user.TgId)
+		}
+	}
+	// Failed refresh of one user doesn't stop refreshes of the others
+	var errs []error
+	for _, tgId := range scoped {
+		errs = append(errs, menu.Refresh(ctx, tgId))
+	}
+	return errors.Join(errs...)
+}
+
+func (menu *CommandsMenu) setDefaults(ctx context.Context, locale i18n.Locale, languageCode string) error {
+	_, err := menu.bot.RequestCtx(ctx, tgbotapi.NewSetMyCommandsWithScopeAndLanguage(tgbotapi.NewBotCommandScopeAllPrivateChats(),
+		languageCode, GetUserCommands(locale)...))
+	if err != nil {
+		return fmt.Errorf("failed to set commands of private chats for %s locale: %w", locale, err)
+	}
+	_, err = menu.bot.RequestCtx(ctx, tgbotapi.NewSetMyCommandsWithScopeAndLanguage(tgbotapi.NewBotCommandScopeAllGroupChats(),
+		languageCode, GetGroupChatCommands(locale)...))
+	if err != nil {
+		return fmt.Errorf("failed to set commands of group chats for %s locale: %w", locale, err)
+	}
+	return nil
+}
+
+// Refresh sets commands of the private chat with the user according to their current roles and language.
+// Should be called, whenever user's role or language changes
+func (menu *CommandsMenu) Refresh(ctx context.Context, tgId int64) error {
+	user, err := menu.users.GetByTgId(ctx, tgId)
+	if err != nil {
+		return fmt.Errorf("failed to get user %d during commands refreshing: %w", tgId, err)
+	}
+	// Deleted users lose their roles
+	if user == nil {
+		user = &entities.User{TgId: tgId}
+	}
+	language, err := menu.users.GetLanguage(ctx, tgId)
+	if err != nil {
+		return fmt.Errorf("failed to get language of user %d during commands refreshing: %w", tgId, err)
+	}
+	locale, chosen := i18n.Parse(language)
+	scope := tgbotapi.NewBotCommandScopeChat(tgId)
+	if !slices.Contains(user.Roles, entities.Admin) && !tgutils.IsOwner(tgId) && !chosen {
+		// Students see commands of all private chats in language of their client
+		_, err = menu.bot.RequestCtx(ctx, tgbotapi.NewDeleteMyCommandsWithScope(scope))
+		if err != nil {
+			return fmt.Errorf("failed to delete commands of user %d: %w", tgId, err)
+		}
+		return nil
+	}
+	if !chosen {
+		locale = i18n.DEFAULT_LOCALE
+	}
+	_, err = menu.bot.RequestCtx(ctx, tgbotapi.NewSetMyCommandsWithScope(scope, GetCommands(locale, user, tgutils.IsOwner(tgId))...))
+	if err != nil {
+		return fmt.Errorf("failed to set commands of user %d: %w", tgId, err)
+	}
+	return nil
+}
+
+// GetCommands returns all commands, which are available to the user
+func GetCommands(locale i18n.Locale, user *entities.User, isOwner bool) []tgbotapi.BotCommand {
+	commands := GetUserCommands(locale)
+	if slices.Contains(user.Roles, entities.Admin) {
+		commands = append(commands, GetAdminCommands(locale)...)
+	}
+	if isOwner {
+		commands = append(commands, GetOwnerCommands(locale)...)
+	}
+	return commands
+}
+
+func GetGroupChatCommands(locale i18n.Locale) []tgbotapi.BotCommand {
+	commands := []command{}
+	for _, command := range userCommands {
+		if slices.Contains(constants.GROUP_CHAT_COMMANDS, command.Command) {
+			commands = append(commands, command)
+		}
+	}
+	return translateCommands(locale, commands)
+}
@@ -5,11 +5,26 @@ const (
 )
 
 const (
-	GROUP_CALLBACKS = "group"
+	LANGUAGE_CALLBACKS = "language"
 )
 
 const (
-	ADMIN_CALLBACKS = "admin"
+	SETTINGS_CALLBACKS = "settings"
+)
+
+const (
+	GROUP_CALLBACKS         = "group"
+	GROUP_ACCEPT_CALLBACKS  = GROUP_CALLBACKS + "accept"
+	GROUP_DECLINE_CALLBACKS = GROUP_CALLBACKS + "decline"
+)
+
+const (
+	ADMIN_CALLBACKS         = "admin"
+	ADMIN_ACCEPT_CALLBACKS  = ADMIN_CALLBACKS + "accept"
+	ADMIN_DECLINE_CALLBACKS = ADMIN_CALLBACKS + "decline"
+
+	// Callbacks of flows, which only admins of the group can go through
+	ADMIN_FLOW_CALLBACKS = ADMIN_CALLBACKS + "_"
 )
 
 const (
@@ -39,22 +54,40 @@ const (
 )
 
 const (
-	QUEUE_CALLBACKS            = "que"
-	QUEUE_DISCIPLINE_CALLBACKS = QUEUE_CALLBACKS + "_discipline"
-	QUEUE_TIME_CALLBACKS       = QUEUE_CALLBACKS + "_time"
-	QUEUE_CANCEL_CALLBACKS     = QUEUE_CALLBACKS + "_cancel"
+	QUEUE_CALLBACKS             = "que"
+	QUEUE_DISCIPLINE_CALLBACKS  = QUEUE_CALLBACKS + "_discipline"
+	QUEUE_TIME_CALLBACKS        = QUEUE_CALLBACKS + "_time"
+	QUEUE_CANCEL_CALLBACKS      = QUEUE_CALLBACKS + "_cancel"
+	QUEUE_SUBSCRIBE_CALLBACKS   = QUEUE_CALLBACKS + "_subscribe"
+	QUEUE_UNSUBSCRIBE_CALLBACKS = QUEUE_CALLBACKS + "_unsubscribe"
 )
 
 const (
-	DELETE_REQUEST_CALLBACKS = "del_req"
+	DELETE_REQUEST_CALLBACKS = ADMIN_FLOW_CALLBACKS + "del_req"
 
 	DELETE_REQUEST_LESSON_CALLBACK = DELETE_REQUEST_CALLBACKS + "_les"
 	DELETE_REQUEST_TIME_CALLBACK   = DELETE_REQUEST_CALLBACKS + "_time"
 )
 
 const (
-	REORDER_LESSON_CALLBACKS     = ADMIN_CALLBACKS + "_ord"
+	REORDER_LESSON_CALLBACKS     = ADMIN_FLOW_CALLBACKS + "ord"
 	REORDER_LESSON_NAME_CALLBACK = REORDER_LESSON_CALLBACKS + "_les"
 
 	REORDER_LESSON_CONCRETE_CALLBACK = REORDER_LESSON_CALLBACKS + "_concr"
 )
+
+const (
+	MY_REQUESTS_CALLBACKS          = "my_req"
+	MY_REQUESTS_WITHDRAW_CALLBACKS = MY_REQUESTS_CALLBACKS + "_withdraw"
+	MY_REQUESTS_BACK_CALLBACKS     = MY_REQUESTS_CALLBACKS + "_back"
+	MY_REQUESTS_EDIT_CALLBACKS     = MY_REQUESTS_CALLBACKS + "_edit"
+	MY_REQUESTS_NUMBER_CALLBACKS   = MY_REQUESTS_CALLBACKS + "_number"
+	MY_REQUESTS_MOVE_CALLBACKS     = MY_REQUESTS_CALLBACKS + "_move"
+)
+
+const (
+	LESSON_MODE_CALLBACKS         = ADMIN_FLOW_CALLBACKS + "lm"
+	LESSON_MODE_SUBJECT_CALLBACKS = LESSON_MODE_CALLBACKS + "_subj"
+	LESSON_MODE_PANEL_CALLBACKS   = LESSON_MODE_CALLBACKS + "_panel"
+	LESSON_MODE_GRADE_CALLBACKS   = LESSON_MODE_CALLBACKS + "_grade"
+)
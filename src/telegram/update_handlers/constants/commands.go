@@ -1,14 +1,42 @@
 package constants
 
 const (
-	HELP_COMMAND        = "/help"
-	SUBMIT_COMMAND      = "/submit"
-	ASSIGN_COMMAND      = "/assign"
-	JOIN_GROUP_COMMAND  = "/join"
-	ADD_LABWORK_COMMAND = "/add"
-	START_COMMAND       = "/start"
-	QUEUE_COMMAND       = "/queue"
-	REVERT_COMMAND      = "/revert"
-	TABLE_COMMAND       = "/table"
-	DELETE_COMMAND      = "/delete"
+	HELP_COMMAND           = "/help"
+	SUBMIT_COMMAND         = "/submit"
+	ASSIGN_COMMAND         = "/assign"
+	JOIN_GROUP_COMMAND     = "/join"
+	ADD_LABWORK_COMMAND    = "/add"
+	START_COMMAND          = "/start"
+	QUEUE_COMMAND          = "/queue"
+	REVERT_COMMAND         = "/revert"
+	TABLE_COMMAND          = "/table"
+	DELETE_COMMAND         = "/delete"
+	CRASHES_COMMAND        = "/crashes"
+	CANCEL_COMMAND         = "/cancel"
+	LANGUAGE_COMMAND       = "/language"
+	REORDER_COMMAND        = "/reorder"
+	REMOVE_REQUEST_COMMAND = "/remove_request"
+	MY_REQUESTS_COMMAND    = "/my"
+	LESSON_MODE_COMMAND    = "/lesson"
+	SETTINGS_COMMAND       = "/settings"
+	RESULTS_COMMAND        = "/results"
 )
+
+// Commands, which are allowed in group chats. The rest of them are redirected to the private chat with the bot
+var GROUP_CHAT_COMMANDS = []string{HELP_COMMAND, QUEUE_COMMAND, TABLE_COMMAND, CANCEL_COMMAND}
+
+// Commands, which start flows from idle state, with start states of the flows
+var COMMAND_STATES = map[string]State{
+	ASSIGN_COMMAND:         ADMIN_SUBMIT_START_STATE,
+	QUEUE_COMMAND:          QUEUE_START_STATE,
+	MY_REQUESTS_COMMAND:    MY_REQUESTS_START_STATE,
+	SETTINGS_COMMAND:       SETTINGS_START_STATE,
+	RESULTS_COMMAND:        RESULTS_START_STATE,
+	JOIN_GROUP_COMMAND:     GROUP_SUBMIT_START_STATE,
+	SUBMIT_COMMAND:         LABWORK_SUBMIT_START_STATE,
+	ADD_LABWORK_COMMAND:    LABWORK_ADD_START_STATE,
+	DELETE_COMMAND:         DELETE_START_STATE,
+	REORDER_COMMAND:        REORDER_REQUEST_START_STATE,
+	LESSON_MODE_COMMAND:    LESSON_MODE_START_STATE,
+	REMOVE_REQUEST_COMMAND: DELETE_REQUEST_START,
+}
@@ -52,6 +52,25 @@ const (
 	QUEUE_WAITING_STATE State = QUEUE_STATES + "_wait"
 )
 
+const (
+	MY_REQUESTS_STATES State = "my_req"
+
+	MY_REQUESTS_START_STATE  State = MY_REQUESTS_STATES + "_start"
+	MY_REQUESTS_NUMBER_STATE State = MY_REQUESTS_STATES + "_number"
+)
+
+const (
+	SETTINGS_STATES State = "settings"
+
+	SETTINGS_START_STATE State = SETTINGS_STATES + "_start"
+)
+
+const (
+	RESULTS_STATES State = "results"
+
+	RESULTS_START_STATE State = RESULTS_STATES + "_start"
+)
+
 const ADMIN_STATES State = "admin"
 const (
 	DELETE_STATES State = ADMIN_STATES + "_del"
@@ -77,6 +96,12 @@ const (
 
 	REORDER_REQUEST_METHOD_STATE State = REORDER_REQUESTS_STATES + "_meth"
 	REORDER_REQUEST_ORDER_STATE State= REORDER_REQUESTS_STATES + "_ord"
-	REORDER_CHOOSE_LESSON_STATE State = REORDER_REQUESTS_STATES + "chs_lsn"
+	REORDER_CHOOSE_LESSON_STATE State = REORDER_REQUESTS_STATES + "_chs_lsn"
 )
 
+const (
+	LESSON_MODE_STATES State = ADMIN_STATES + "_lesson"
+
+	LESSON_MODE_START_STATE State = LESSON_MODE_STATES + "_start"
+	LESSON_MODE_GRADE_STATE State = LESSON_MODE_STATES + "_grade"
+)
@@ -5,4 +5,8 @@ import "time"
 const (
 	DEFAULT_TIMEOUT = 120 * time.Second
 	TG_TIMEOUT      = 10 * time.Second
+	// Time after which abandoned conversation is aborted and chat is returned to idle state
+	FLOW_TIMEOUT = 30 * time.Minute
+	// Time for which payloads of inline buttons are kept, after that buttons stop working
+	CALLBACK_PAYLOAD_TTL = 7 * 24 * time.Hour
 )
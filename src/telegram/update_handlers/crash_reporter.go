@@ -0,0 +1,106 @@
+package update_handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+	customErrors "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/errors"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+type CrashReporter struct {
+	crashes interfaces.CrashesRepository
+	cache   interfaces.HandlersCache
+	bot     *tgutils.Bot
+}
+
+func NewCrashReporter(crashes interfaces.CrashesRepository, cache interfaces.HandlersCache, bot *tgutils.Bot) *CrashReporter {
+	return &CrashReporter{crashes: crashes, cache: cache, bot: bot}
+}
+
+// ReportPanic is meant to be called with recovered value in deferred function of update handling goroutine.
+// Returns the reported error
+func (reporter *CrashReporter) ReportPanic(update *tgbotapi.Update, recovered any, stack []byte) error {
+	err := tgutils.NewPanicError(recovered, stack)
+	reporter.Report(update, err)
+	return err
+}
+
+// Report persists crash report for the update, resets the flow of the chat to idle state and notifies user about it.
+// Errors, caused by the user input, are ignored
+func (reporter *CrashReporter) Report(update *tgbotapi.Update, err error) {
+	if err == nil || errors.Is(err, errors.ErrUnsupported) || errors.As(err, new(*customErrors.ErrInvalidInput)) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), constants.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	if update != nil && update.FromChat() != nil && update.SentFrom() != nil {
+		ctx = interfaces.WithChatUser(ctx, update.FromChat().ID, update.SentFrom().ID)
+	}
+	if update != nil && update.SentFrom() != nil {
+		ctx = reporter.bot.Localize(ctx, update.SentFrom().ID, update.SentFrom().LanguageCode)
+	}
+	report := reporter.createReport(ctx, update, err)
+	slog.Error("crash during update handling", "err", report.Error, "chat_id", report.ChatId, "state", report.State, "callback", report.CallbackData)
+	if saveErr := reporter.crashes.Save(ctx, report); saveErr != nil {
+		slog.Error(fmt.Sprintf("failed to save crash report: %s", saveErr.Error()), "stack", report.Stack)
+	}
+	if report.ChatId == 0 {
+		return
+	}
+	if resetErr := reporter.resetFlow(ctx, report.ChatId); resetErr != nil {
+		slog.Error(resetErr.Error())
+	}
+	_, sendErr := reporter.bot.SendCtx(ctx, tgbotapi.NewMessage(report.ChatId, i18n.T(ctx, i18n.CRASH_TEXT)))
+	if sendErr != nil {
+		slog.Error(fmt.Sprintf("failed to send crash reply: %s", sendErr.Error()))
+	}
+}
+
+func (reporter *CrashReporter) createReport(ctx context.Context, update *tgbotapi.Update, err error) *interfaces.CrashReport {
+	report := &interfaces.CrashReport{Time: time.Now(), Error: err.Error()}
+	if panicErr := (*tgutils.PanicError)(nil); errors.As(err, &panicErr) {
+		report.Stack = string(panicErr.Stack)
+	}
+	if update == nil {
+		return report
+	}
+	report.UpdateId = update.UpdateID
+	if chat := update.FromChat(); chat != nil {
+		report.ChatId = chat.ID
+	}
+	if user := update.SentFrom(); user != nil {
+		report.UserId = user.ID
+	}
+	if update.Message != nil {
+		report.MessageText = update.Message.Text
+	}
+	report.CallbackData = update.CallbackData()
+	if report.ChatId != 0 {
+		info, err := reporter.cache.GetState(ctx, report.ChatId)
+		if err == nil && info != nil {
+			report.State = info.State()
+		}
+	}
+	return report
+}
+
+func (reporter *CrashReporter) resetFlow(ctx context.Context, chatId int64) error {
+	err := reporter.cache.RemoveInfo(ctx, chatId)
+	if err != nil {
+		return fmt.Errorf("failed to remove info during crash flow reset: %w", err)
+	}
+	err = reporter.cache.SaveState(ctx, *interfaces.NewCachedInfo(chatId, constants.IDLE_STATE))
+	if err != nil {
+		return fmt.Errorf("failed to save idle state during crash flow reset: %w", err)
+	}
+	return nil
+}
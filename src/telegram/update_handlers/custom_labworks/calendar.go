@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
 	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
@@ -20,29 +21,29 @@ const (
 	calendarStart  = 2
 )
 
-var months = map[time.Month]string{
-	time.January:   "Январь",
-	time.February:  "Февраль",
-	time.March:     "Марть",
-	time.April:     "Апрель",
-	time.May:       "Май",
-	time.June:      "Июнь",
-	time.July:      "Июль",
-	time.August:    "Август",
-	time.September: "Сентябрь",
-	time.October:   "Октябрь",
-	time.November:  "Ноябрь",
-	time.December:  "Декабрь",
+var months = map[time.Month]i18n.Key{
+	time.January:   i18n.MONTH_JANUARY,
+	time.February:  i18n.MONTH_FEBRUARY,
+	time.March:     i18n.MONTH_MARCH,
+	time.April:     i18n.MONTH_APRIL,
+	time.May:       i18n.MONTH_MAY,
+	time.June:      i18n.MONTH_JUNE,
+	time.July:      i18n.MONTH_JULY,
+	time.August:    i18n.MONTH_AUGUST,
+	time.September: i18n.MONTH_SEPTEMBER,
+	time.October:   i18n.MONTH_OCTOBER,
+	time.November:  i18n.MONTH_NOVEMBER,
+	time.December:  i18n.MONTH_DECEMBER,
 }
 
-var days = map[time.Weekday]string{
-	time.Monday:    "Пн",
-	time.Tuesday:   "Вт",
-	time.Wednesday: "Ср",
-	time.Thursday:  "Чт",
-	time.Friday:    "Пт",
-	time.Saturday:  "Сб",
-	time.Sunday:    "Вс",
+var days = map[time.Weekday]i18n.Key{
+	time.Monday:    i18n.WEEKDAY_MONDAY,
+	time.Tuesday:   i18n.WEEKDAY_TUESDAY,
+	time.Wednesday: i18n.WEEKDAY_WEDNESDAY,
+	time.Thursday:  i18n.WEEKDAY_THURSDAY,
+	time.Friday:    i18n.WEEKDAY_FRIDAY,
+	time.Saturday:  i18n.WEEKDAY_SATURDAY,
+	time.Sunday:    i18n.WEEKDAY_SUNDAY,
 }
 
 const (
@@ -54,7 +55,7 @@ const (
 	twoSideNavigationSize = 3
 )
 
-func createCalendar(date time.Time, isCurrentMonth bool) *tgbotapi.InlineKeyboardMarkup {
+func createCalendar(ctx context.Context, date time.Time, isCurrentMonth bool) *tgbotapi.InlineKeyboardMarkup {
 	currentYear, currentMonth, currentDay := date.Date()
 	firstOfMonth := time.Date(currentYear, currentMonth, 1, 0, 0, 0, 0, time.Local)
 	lastOfMonth := firstOfMonth.AddDate(0, 1, -1)
@@ -64,8 +65,8 @@ func createCalendar(date time.Time, isCurrentMonth bool) *tgbotapi.InlineKeyboar
 
 	markup := make([][]tgbotapi.InlineKeyboardButton, int(math.Abs(float64(lastOfMonthWeek-firstOfMonthWeek+1)))+additionalRows)
 
-	createCalendarHeader(&markup, currentMonth, currentYear)
-	createDateRows(&markup, firstOfMonth.Weekday(), currentDay, int(currentMonth), currentYear, lastOfMonth)
+	createCalendarHeader(ctx, &markup, currentMonth, currentYear)
+	createDateRows(ctx, &markup, firstOfMonth.Weekday(), currentDay, int(currentMonth), currentYear, lastOfMonth)
 
 	if isCurrentMonth {
 		markup[len(markup)-1] = make([]tgbotapi.InlineKeyboardButton, oneSideNavigationSize)
@@ -81,10 +82,10 @@ func createCalendar(date time.Time, isCurrentMonth bool) *tgbotapi.InlineKeyboar
 	return &tgbotapi.InlineKeyboardMarkup{InlineKeyboard: markup}
 }
 
-func createDateRows(markup *[][]tgbotapi.InlineKeyboardButton, firstDayWeekday time.Weekday, currentDay, currentMonth, currentYear int,
+func createDateRows(ctx context.Context, markup *[][]tgbotapi.InlineKeyboardButton, firstDayWeekday time.Weekday, currentDay, currentMonth, currentYear int,
 	 lastOfMonth time.Time) {
 	for i := range 7 {
-		(*markup)[1][i] = tgbotapi.NewInlineKeyboardButtonData(days[time.Weekday(i)], constants.IGNORE_CALLBACKS)
+		(*markup)[1][i] = tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, days[time.Weekday(i)]), constants.IGNORE_CALLBACKS)
 	}
 
 	displayedDate := 1
@@ -108,8 +109,8 @@ func createDateRows(markup *[][]tgbotapi.InlineKeyboardButton, firstDayWeekday t
 }
 
 
-func createCalendarHeader(markup *[][]tgbotapi.InlineKeyboardButton, currentMonth time.Month, currentYear int) {
-	currentMonthString := fmt.Sprintf(months[currentMonth]+" %d", currentYear)
+func createCalendarHeader(ctx context.Context, markup *[][]tgbotapi.InlineKeyboardButton, currentMonth time.Month, currentYear int) {
+	currentMonthString := fmt.Sprintf("%s %d", i18n.T(ctx, months[currentMonth]), currentYear)
 	(*markup)[0] = make([]tgbotapi.InlineKeyboardButton, 1)
 	(*markup)[0] = tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(currentMonthString, constants.IGNORE_CALLBACKS))
 
@@ -120,7 +121,7 @@ func createCalendarHeader(markup *[][]tgbotapi.InlineKeyboardButton, currentMont
 	(*markup)[1] = make([]tgbotapi.InlineKeyboardButton, daysInWeek)
 	//Row of week days
 	for i := range 7 {
-		(*markup)[1][i] = tgbotapi.NewInlineKeyboardButtonData(days[time.Weekday(i)], constants.IGNORE_CALLBACKS)
+		(*markup)[1][i] = tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, days[time.Weekday(i)]), constants.IGNORE_CALLBACKS)
 	}
 }
 
@@ -210,7 +211,7 @@ func (handler *CalendarCallbackHandler) handleNavigateFront(ctx context.Context,
 
 	_, err := handler.bot.SendCtx(ctx, 
 		tgbotapi.NewEditMessageReplyMarkup(update.CallbackQuery.Message.Chat.ID, update.CallbackQuery.Message.MessageID, 
-			*createCalendar(curDate, false)))
+			*createCalendar(ctx, curDate, false)))
 	if err != nil {
 		return fmt.Errorf("failed to edit reply markup while navigating front in calendar: %w", err)
 	}
@@ -227,7 +228,7 @@ func (handler *CalendarCallbackHandler) handleNavigateBack(ctx context.Context,
 	}
 	_, err := handler.bot.SendCtx(ctx, 
 		tgbotapi.NewEditMessageReplyMarkup(update.CallbackQuery.Message.Chat.ID, update.CallbackQuery.Message.MessageID,
-			 *createCalendar(curDate, isCurrentMonth)))
+			 *createCalendar(ctx, curDate, isCurrentMonth)))
 	if err != nil {
 		return fmt.Errorf("failed to edit reply markup while navigating back in calendar: %w", err)
 	}
@@ -254,7 +255,7 @@ func (handler *CalendarCallbackHandler) handleDate(ctx context.Context, update *
 	}
 
 	_, err = handler.bot.SendCtx(ctx, 
-		tgbotapi.NewEditMessageReplyMarkup(update.CallbackQuery.Message.Chat.ID, update.CallbackQuery.Message.MessageID, *createTimePicker("")))
+		tgbotapi.NewEditMessageReplyMarkup(update.CallbackQuery.Message.Chat.ID, update.CallbackQuery.Message.MessageID, *createTimePicker(ctx, "")))
 	if err != nil {
 		return fmt.Errorf("failed to edit message during date callback handling in calendar: %w", err)
 	}
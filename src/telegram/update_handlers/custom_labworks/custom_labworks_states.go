@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
@@ -70,7 +71,7 @@ func (state *labworkAddStartState) Handle(ctx context.Context, message *tgbotapi
 	if err != nil {
 		return fmt.Errorf("failed to transition to custom labwork submit name state: %w", err)
 	}
-	_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, "Введите название добавленной пары"))
+	_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.ENTER_LESSON_NAME_TEXT)))
 	if err != nil {
 		return fmt.Errorf("failed to send response in labwork add start state: %w", err)
 	}
@@ -103,7 +104,7 @@ type LessonRequest struct {
 func (state *labworkAddSubmitNameState) Handle(ctx context.Context, message *tgbotapi.Message) error {
 	name := message.Text
 	if name == "" {
-		_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, "Отправьте текстовое сообщение, без прикрепленных файлов"))
+		_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.SEND_TEXT_ONLY_TEXT)))
 		if err != nil {
 			return fmt.Errorf("failed to send no text response during custom labwork name submit: %w", err)
 		}
@@ -120,8 +121,8 @@ func (state *labworkAddSubmitNameState) Handle(ctx context.Context, message *tgb
 		return fmt.Errorf("failed to unmarshal jsoned req (%s) in custom labwork name submit state: %w", jsonedReq, err)
 	}
 
-	resp := tgbotapi.NewMessage(message.Chat.ID, "Выберите дату добавленной пары")
-	resp.ReplyMarkup = createCalendar(time.Now(), true)
+	resp := tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.CHOOSE_LESSON_DATE_TEXT))
+	resp.ReplyMarkup = createCalendar(ctx, time.Now(), true)
 
 	sent, err := state.bot.SendCtx(ctx, resp)
 	if err != nil {
@@ -172,7 +173,7 @@ func NewLabworkAddWaitingState(bot *tgutils.Bot, cache HandlersCache, machine St
 }
 
 func (state *LabworkAddWaitingState) Handle(ctx context.Context, message *tgbotapi.Message) error {
-	_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, "Выберите дату и время пары"))
+	_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.CHOOSE_LESSON_DATE_TIME_TEXT)))
 	if err != nil {
 		return fmt.Errorf("failed to send message during labwork add waiting state: %w", err)
 	}
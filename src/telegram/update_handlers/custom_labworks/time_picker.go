@@ -11,6 +11,7 @@ import (
 	"time"
 
 	sheetsapi "github.com/aCrYoZPS/bsuir_queue_bot/src/google/sheets_api"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	iis_api_entities "github.com/aCrYoZPS/bsuir_queue_bot/src/iis_api/entities"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
@@ -21,7 +22,7 @@ import (
 )
 
 // Creates time picker markup with the given string in format "15:00" as time. Pass empty string to set default time
-func createTimePicker(currentTime string) *tgbotapi.InlineKeyboardMarkup {
+func createTimePicker(ctx context.Context, currentTime string) *tgbotapi.InlineKeyboardMarkup {
 	if currentTime == "" {
 		currentTime = "15:00"
 	}
@@ -32,8 +33,8 @@ func createTimePicker(currentTime string) *tgbotapi.InlineKeyboardMarkup {
 		tgbotapi.NewInlineKeyboardButtonData("-", createHoursDecreaseCallback(currentTime))}
 	markup[2] = []tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardButtonData("+", createMinutesIncreaseCallback(currentTime)),
 		tgbotapi.NewInlineKeyboardButtonData("-", createMinutesDecreaseCallback(currentTime))}
-	markup[3] = []tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardButtonData("Назад", constants.TIME_CANCEL)}
-	markup[4] = []tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardButtonData("Принять", createTimeAcceptCallback(currentTime))}
+	markup[3] = []tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.BACK_BUTTON), constants.TIME_CANCEL)}
+	markup[4] = []tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ACCEPT_BUTTON), createTimeAcceptCallback(currentTime))}
 	return &tgbotapi.InlineKeyboardMarkup{InlineKeyboard: markup}
 }
 
@@ -121,7 +122,7 @@ func (callbackHandler *TimePickerCallbackHandler) HandleCallback(ctx context.Con
 func (callbackHandler *TimePickerCallbackHandler) handleCancelCallback(ctx context.Context, update *tgbotapi.Update) error {
 	_, err := callbackHandler.bot.SendCtx(ctx,
 		 tgbotapi.NewEditMessageReplyMarkup(update.CallbackQuery.Message.Chat.ID, update.CallbackQuery.Message.MessageID,
-			 *createCalendar(time.Now(), true)))
+			 *createCalendar(ctx, time.Now(), true)))
 	if err != nil {
 		return fmt.Errorf("failed to edit markup when handling time picker cancel callback: %w", err)
 	}
@@ -149,7 +150,7 @@ func (callbackHandler *TimePickerCallbackHandler) handleHoursIncreaseCallback(ct
 
 	_, err = callbackHandler.bot.SendCtx(ctx, 
 		tgbotapi.NewEditMessageReplyMarkup(update.CallbackQuery.Message.Chat.ID, update.CallbackQuery.Message.MessageID,
-			 *createTimePicker(curTimeString)))
+			 *createTimePicker(ctx, curTimeString)))
 	if err != nil {
 		return fmt.Errorf("failed to edit reply markup during handling time picker hours increase callback: %w", err)
 	}
@@ -177,7 +178,7 @@ func (callbackHandler *TimePickerCallbackHandler) handleHoursDecreaseCallback(ct
 
 	_, err = callbackHandler.bot.SendCtx(ctx,
 		 tgbotapi.NewEditMessageReplyMarkup(update.CallbackQuery.Message.Chat.ID, update.CallbackQuery.Message.MessageID, 
-			*createTimePicker(curTimeString)))
+			*createTimePicker(ctx, curTimeString)))
 	if err != nil {
 		return fmt.Errorf("failed to edit reply markup during handling time picker hours decrease callback: %w", err)
 	}
@@ -205,7 +206,7 @@ func (callbackHandler *TimePickerCallbackHandler) handleMinutesIncreaseCallback(
 
 	_, err = callbackHandler.bot.SendCtx(ctx, 
 		tgbotapi.NewEditMessageReplyMarkup(update.CallbackQuery.Message.Chat.ID, update.CallbackQuery.Message.MessageID,
-			 *createTimePicker(curTimeString)))
+			 *createTimePicker(ctx, curTimeString)))
 	if err != nil {
 		return fmt.Errorf("failed to edit reply markup during handling time picker minutes increase callback: %w", err)
 	}
@@ -233,7 +234,7 @@ func (callbackHandler *TimePickerCallbackHandler) handleMinutesDecreaseCallback(
 
 	_, err = callbackHandler.bot.SendCtx(ctx,
 		 tgbotapi.NewEditMessageReplyMarkup(update.CallbackQuery.Message.Chat.ID, update.CallbackQuery.Message.MessageID,
-			 *createTimePicker(curTimeString)))
+			 *createTimePicker(ctx, curTimeString)))
 	if err != nil {
 		return fmt.Errorf("failed to edit reply markup during handling time picker minutes decrease callback: %w", err)
 	}
@@ -261,7 +262,7 @@ func (callbackHandler *TimePickerCallbackHandler) handleTimeSubmitCallback(ctx c
 	}
 	_, err = callbackHandler.bot.SendCtx(ctx, 
 		tgbotapi.NewEditMessageTextAndMarkup(update.CallbackQuery.Message.Chat.ID, update.CallbackQuery.Message.MessageID, 
-			"Ваша лабораторная была сохранена", tgbotapi.NewInlineKeyboardMarkup([]tgbotapi.InlineKeyboardButton{})))
+			i18n.T(ctx, i18n.LESSON_SAVED_TEXT), tgbotapi.NewInlineKeyboardMarkup([]tgbotapi.InlineKeyboardButton{})))
 	if err != nil {
 		return fmt.Errorf("failed to remove markup from message during time picker submit callback hadnling: %w", err)
 	}
@@ -277,7 +278,7 @@ func (callbackHandler *TimePickerCallbackHandler) wrapLessonsServiceError(ctx co
 	if errors.Is(err, sheetsapi.ErrSheetsExists()) {
 		_, err := callbackHandler.bot.SendCtx(ctx, 
 			tgbotapi.NewEditMessageTextAndMarkup(update.CallbackQuery.Message.Chat.ID, update.CallbackQuery.Message.MessageID,
-			"Пара под данным именем и датой уже существует. Пожалуйста, укажите другое имя", 
+			i18n.T(ctx, i18n.LESSON_EXISTS_TEXT), 
 			tgbotapi.NewInlineKeyboardMarkup([]tgbotapi.InlineKeyboardButton{})))
 		if err != nil {
 			return fmt.Errorf("failed to send sheet exists message during time picker submit callback handling: %w", err)
@@ -291,7 +292,7 @@ func (callbackHandler *TimePickerCallbackHandler) wrapLessonsServiceError(ctx co
 	} else if errors.Is(err, sheetsapi.ErrNoSheetCreated()) {
 		_, err := callbackHandler.bot.SendCtx(ctx, 
 			tgbotapi.NewEditMessageTextAndMarkup(update.CallbackQuery.Message.Chat.ID, update.CallbackQuery.Message.MessageID,
-			"Пара под данным именем и датой не создалась. Пожалуйста,проверьте,что имя валидно для названия в гугл таблицах", 
+			i18n.T(ctx, i18n.LESSON_NOT_CREATED_TEXT), 
 			tgbotapi.NewInlineKeyboardMarkup([]tgbotapi.InlineKeyboardButton{})))
 		if err != nil {
 			return fmt.Errorf("failed to send sheet exists message during time picker submit callback handling: %w", err)
@@ -305,7 +306,7 @@ func (callbackHandler *TimePickerCallbackHandler) wrapLessonsServiceError(ctx co
 	} else if googleErr, ok := err.(*googleapi.Error); ok {
 		if googleErr.Code == http.StatusInternalServerError {
 			_, err := callbackHandler.bot.SendCtx(ctx,
-				 tgbotapi.NewMessage(update.CallbackQuery.Message.Chat.ID, "Ошибка на стороне сервисов гугла. Пожалуйста,попробуйте позже"))
+				 tgbotapi.NewMessage(update.CallbackQuery.Message.Chat.ID, i18n.T(ctx, i18n.GOOGLE_ERROR_TEXT)))
 			if err != nil {
 				return fmt.Errorf("failed to send google service error response to user during time picker callback handling: %w", err)
 			}
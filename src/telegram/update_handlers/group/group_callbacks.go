@@ -2,29 +2,39 @@ package group
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
 	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
+type CommandsMenu interface {
+	Refresh(ctx context.Context, tgId int64) error
+}
+
 type GroupCallbackHandler struct {
 	users    interfaces.UsersRepository
 	requests interfaces.RequestsRepository
 	cache    interfaces.HandlersCache
+	menu     CommandsMenu
 }
 
-func NewGroupCallbackHandler(users interfaces.UsersRepository, cache interfaces.HandlersCache, requests interfaces.RequestsRepository) *GroupCallbackHandler {
+func NewGroupCallbackHandler(users interfaces.UsersRepository, cache interfaces.HandlersCache, requests interfaces.RequestsRepository,
+	menu CommandsMenu) *GroupCallbackHandler {
 	return &GroupCallbackHandler{
 		users:    users,
 		cache:    cache,
 		requests: requests,
+		menu:     menu,
 	}
 }
 
@@ -70,14 +80,18 @@ func (handler *GroupCallbackHandler) handleAcceptCallback(ctx context.Context, m
 	if err != nil {
 		return fmt.Errorf("failed to add new user in group accept callback: %w", err)
 	}
+	err = handler.menu.Refresh(ctx, form.UserId)
+	if err != nil {
+		return fmt.Errorf("failed to refresh commands of new user in group accept callback: %w", err)
+	}
 	err = handler.RemoveMarkup(ctx, msg, bot)
 	if err != nil {
 		return err
 	}
-	resp := tgbotapi.NewMessage(form.UserId, "Ваша заявка была одобрена")
-	user, err := handler.users.GetByTgId(ctx, msg.From.ID)
+	resp := tgbotapi.NewMessage(form.UserId, i18n.T(bot.Localize(ctx, form.UserId, ""), i18n.REQUEST_APPROVED_TEXT))
+	user, err := handler.users.GetByTgId(ctx, form.UserId)
 	if err != nil {
-		return fmt.Errorf("failed to get user by tg id (%d) during group accept callback handling: %w", msg.From.ID, err)
+		return fmt.Errorf("failed to get user by tg id (%d) during group accept callback handling: %w", form.UserId, err)
 	}
 	err = tgutils.CreateStartReplyMarkup(ctx, &resp, user, bot)
 	if err != nil {
@@ -107,7 +121,7 @@ func (handler *GroupCallbackHandler) handleDeclineCallback(ctx context.Context,
 		return fmt.Errorf("failed to transition to idle state in group decline callback")
 	}
 
-	resp := tgbotapi.NewMessage(form.UserId, "Ваша заявка была отклонена")
+	resp := tgbotapi.NewMessage(form.UserId, i18n.T(bot.Localize(ctx, form.UserId, ""), i18n.REQUEST_DECLINED_TEXT))
 	if _, err := bot.SendCtx(ctx, resp); err != nil {
 		return fmt.Errorf("failed to send response in group decline callback: %w", err)
 	}
@@ -135,3 +149,21 @@ func (handler *GroupCallbackHandler) RemoveMarkup(ctx context.Context, msg *tgbo
 	}
 	return nil
 }
+
+// RequestCallbackKey keys accept and decline callbacks by the group request uuid, shared by its copies sent to every admin
+func RequestCallbackKey(requests interfaces.RequestsRepository) tgutils.CallbackKeyFunc {
+	return func(ctx context.Context, update *tgbotapi.Update) (string, error) {
+		msg := update.CallbackQuery.Message
+		if msg == nil {
+			return "", nil
+		}
+		request, err := requests.GetByMsg(ctx, int64(msg.MessageID), msg.Chat.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", tgutils.ErrCallbackProcessed
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to get group request of the callback: %w", err)
+		}
+		return constants.GROUP_CALLBACKS + request.UUID, nil
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
 	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
@@ -47,7 +48,7 @@ func (state *groupSubmitStartState) Handle(ctx context.Context, message *tgbotap
 		return err
 	}
 	if user.GroupId != 0 {
-		_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, "Вы уже член группы"))
+		_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.ALREADY_GROUP_MEMBER_TEXT)))
 		if err != nil {
 			return fmt.Errorf("failed to send part of group response during group submit start state: %w", err)
 		}
@@ -61,7 +62,7 @@ func (state *groupSubmitStartState) Handle(ctx context.Context, message *tgbotap
 	if err != nil {
 		return err
 	}
-	msg := tgbotapi.NewMessage(message.Chat.ID, "Введите номер группы,в которую хотите вступить")
+	msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.ENTER_GROUP_NAME_TEXT))
 	_, err = state.bot.SendCtx(ctx, msg)
 	if err != nil {
 		return fmt.Errorf("failed to send group number request when starting group submit: %w", err)
@@ -90,7 +91,7 @@ func (state *groupSubmitGroupNameState) Handle(ctx context.Context, message *tgb
 		return err
 	}
 	if !groupExists {
-		msg := tgbotapi.NewMessage(message.Chat.ID, "Данная группа не найдена")
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.GROUP_NOT_FOUND_TEXT))
 		_, err := state.bot.SendCtx(ctx, msg)
 		if err != nil {
 			return fmt.Errorf("failed to send not found message during submitting group submit name state: %w", err)
@@ -107,7 +108,7 @@ func (state *groupSubmitGroupNameState) Handle(ctx context.Context, message *tgb
 		if err != nil {
 			return fmt.Errorf("failed to save idle state during group name submit: %w", err)
 		}
-		_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, "У данной группы пока нет администраторов. Попросите кого-либо из участников группы выступить в его роли"))
+		_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.GROUP_HAS_NO_ADMINS_TEXT)))
 		if err != nil {
 			return fmt.Errorf("failed to send message during group name submit: %w", err)
 		}
@@ -126,7 +127,7 @@ func (state *groupSubmitGroupNameState) Handle(ctx context.Context, message *tgb
 	if err != nil {
 		return fmt.Errorf("failed to save group submit name state: %w", err)
 	}
-	_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, "Введите ваши фамилию и имя (Пример формата: Иванов Иван)"))
+	_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.ENTER_FULL_NAME_TEXT)))
 	if err != nil {
 		return fmt.Errorf("failed to send message for submitting user info: %w", err)
 	}
@@ -171,7 +172,7 @@ func (state *groupSubmitNameState) Handle(ctx context.Context, message *tgbotapi
 	name := message.Text
 	fullName := strings.Fields(name)
 	if len(fullName) != 2 {
-		_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, "Введите фамилию и имя как в предоставленном образце"))
+		_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.INVALID_FULL_NAME_TEXT)))
 		if err != nil {
 			return fmt.Errorf("failed to send message when submiting name for attendance to group: %w", err)
 		}
@@ -211,7 +212,7 @@ func (state *groupSubmitNameState) Handle(ctx context.Context, message *tgbotapi
 	if err != nil {
 		return fmt.Errorf("failed to send messages to admins during group submit name state: %w", err)
 	}
-	_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, "Ваша заявка была отправлена администраторам группы"))
+	_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.GROUP_REQUEST_SENT_TEXT)))
 	if err != nil {
 		return fmt.Errorf("failed to send message during group name submit: %w", err)
 	}
@@ -232,20 +233,20 @@ func (state *groupSubmitNameState) SendMessagesToAdmins(ctx context.Context, sen
 	if len(admins) == 0 {
 		return errors.New("no admins found in group")
 	}
-	text := fmt.Sprintf("Пользователь под id @%s и именем \"%s\" хочет присоединиться к группе", form.UserName, form.Name)
 	reqUUID := uuid.NewString()
 	for _, admin := range admins {
-		msg := tgbotapi.NewMessage(admin.TgId, text)
-		msg.ReplyMarkup = createMarkupKeyboard(form)
+		adminCtx := state.bot.Localize(ctx, admin.TgId, "")
+		msg := tgbotapi.NewMessage(admin.TgId, i18n.T(adminCtx, i18n.GROUP_JOIN_REQUEST_TEXT, form.UserName, form.Name))
+		msg.ReplyMarkup = createMarkupKeyboard(adminCtx, form)
 		sentMsg, err := state.bot.SendCtx(ctx, msg)
 		if err != nil {
 			if errors.Is(err, tgutils.ErrMsgInvalidLen) {
 				resp := tgbotapi.NewMessage(senderMessage.Chat.ID, 
-					"Ваше сообщение превосходит лимиты размера сообщений в телеграме. Пожалуйста, измените его и отправьте снова")
+					i18n.T(ctx, i18n.MESSAGE_TOO_LONG_TEXT))
 				resp.ReplyToMessageID = senderMessage.MessageID
 				_, err := state.bot.SendCtx(ctx, 
 					tgbotapi.NewMessage(senderMessage.Chat.ID, 
-						"Ваше сообщение превосходит лимиты размера сообщений в телеграме. Пожалуйста, измените его и отправьте снова"))
+						i18n.T(ctx, i18n.MESSAGE_TOO_LONG_TEXT)))
 				if err != nil {
 					return fmt.Errorf("failed to send too large message as a response during group submit name state: %w", err)
 				}
@@ -271,7 +272,7 @@ func NewGroupWaitingState(cache interfaces.HandlersCache, bot *tgutils.Bot) *gro
 }
 
 func (state *groupWaitingState) Handle(ctx context.Context, message *tgbotapi.Message) error {
-	_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, "Ваша заявка всё ещё рассматривается, подождите"))
+	_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.REQUEST_PENDING_TEXT)))
 	if err != nil {
 		return fmt.Errorf("failed to send message to user waiting for group submit: %w", err)
 	}
@@ -282,12 +283,12 @@ func (state *groupWaitingState) Revert(ctx context.Context, msg *tgbotapi.Messag
 	return nil
 }
 
-func createMarkupKeyboard(form *groupSubmitForm) *tgbotapi.InlineKeyboardMarkup {
+func createMarkupKeyboard(ctx context.Context, form *groupSubmitForm) *tgbotapi.InlineKeyboardMarkup {
 	row := []tgbotapi.InlineKeyboardButton{}
-	acceptData := constants.GROUP_CALLBACKS + "accept" + fmt.Sprint(form.UserId)
-	declineData := constants.GROUP_CALLBACKS + "decline" + fmt.Sprint(form.UserId)
+	acceptData := constants.GROUP_ACCEPT_CALLBACKS + fmt.Sprint(form.UserId)
+	declineData := constants.GROUP_DECLINE_CALLBACKS + fmt.Sprint(form.UserId)
 	row = append(row, 
-		tgbotapi.NewInlineKeyboardButtonData("Принять", acceptData), tgbotapi.NewInlineKeyboardButtonData("Отклонить", declineData))
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ACCEPT_BUTTON), acceptData), tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.DECLINE_BUTTON), declineData))
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(row)
 	return &keyboard
 }
@@ -4,10 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"slices"
 	"strings"
+	"time"
 
-	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
 	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
@@ -20,31 +20,35 @@ type idleState struct {
 	usersRepo  interfaces.UsersRepository
 	groupsRepo interfaces.GroupsRepository
 	lessons    interfaces.LessonsRepository
+	crashes    interfaces.CrashesRepository
 	mux        tgutils.MuxHandler
 }
 
 func NewIdleState(cache interfaces.HandlersCache, bot *tgutils.Bot, usersRepo interfaces.UsersRepository, 
-	groupsRepo interfaces.GroupsRepository, lessons interfaces.LessonsRepository, mux tgutils.MuxHandler) *idleState {
-	return &idleState{cache: cache, bot: bot, usersRepo: usersRepo, groupsRepo: groupsRepo, lessons: lessons, mux: mux}
+	groupsRepo interfaces.GroupsRepository, lessons interfaces.LessonsRepository, crashes interfaces.CrashesRepository, mux tgutils.MuxHandler) *idleState {
+	return &idleState{cache: cache, bot: bot, usersRepo: usersRepo, groupsRepo: groupsRepo, lessons: lessons, crashes: crashes, mux: mux}
 }
 
 func (state *idleState) Handle(ctx context.Context, message *tgbotapi.Message) error {
-	switch message.Text {
-	case constants.ASSIGN_COMMAND:
-		err := state.cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, constants.ADMIN_SUBMIT_START_STATE))
+	command := message.Text
+	// Arguments of the command are ignored, e.g. /start parameter of deep links
+	if message.IsCommand() {
+		command = "/" + message.Command()
+	}
+	if next, ok := constants.COMMAND_STATES[command]; ok {
+		err := state.cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, next))
 		if err != nil {
-			return fmt.Errorf("failed to transition from idle to admin submit state: %w", err)
+			return fmt.Errorf("failed to transition from idle state to %s: %w", next, err)
 		}
+		return state.mux.Handle(ctx, message)
+	}
+	switch command {
 	case constants.HELP_COMMAND:
-		var commands []tgbotapi.BotCommand
-		commands = append(commands, GetUserCommands()...)
 		user, err := state.usersRepo.GetByTgId(ctx, message.From.ID)
 		if err != nil {
 			return fmt.Errorf("failed to get user by id during handling help command: %w", err)
 		}
-		if slices.Contains(user.Roles, entities.Admin) {
-			commands = append(commands, GetAdminCommands()...)
-		}
+		commands := GetCommands(i18n.FromContext(ctx), user, tgutils.IsOwner(message.From.ID))
 		builder := strings.Builder{}
 		for _, command := range commands {
 			builder.WriteString(command.Command)
@@ -57,40 +61,18 @@ func (state *idleState) Handle(ctx context.Context, message *tgbotapi.Message) e
 			return fmt.Errorf("failed to send message during help command: %w", err)
 		}
 		return nil
-	case constants.QUEUE_COMMAND:
-		err := state.cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, constants.QUEUE_START_STATE))
-		if err != nil {
-			return fmt.Errorf("failed to save queue start state: %w", err)
-		}
 	case constants.TABLE_COMMAND:
 		return state.HandleTableCommand(ctx, message)
-	case constants.JOIN_GROUP_COMMAND:
-		err := state.cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, constants.GROUP_SUBMIT_START_STATE))
-		if err != nil {
-			return fmt.Errorf("failed to save group submit start state: %w", err)
-		}
-	case constants.SUBMIT_COMMAND:
-		err := state.cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, constants.LABWORK_SUBMIT_START_STATE))
-		if err != nil {
-			return fmt.Errorf("failed to transition from idle state to labwork submit state: %w", err)
-		}
-	case constants.ADD_LABWORK_COMMAND:
-		err := state.cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, constants.LABWORK_ADD_START_STATE))
-		if err != nil {
-			return fmt.Errorf("failed to transition from idle state to labwork add state: %w", err)
-		}
-	case constants.DELETE_COMMAND:
-		err := state.cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, constants.DELETE_START_STATE))
-		if err != nil {
-			return fmt.Errorf("failed to transition from idle state")
-		}
+	case constants.CRASHES_COMMAND:
+		return state.HandleCrashesCommand(ctx, message)
+	case constants.LANGUAGE_COMMAND:
+		return state.HandleLanguageCommand(ctx, message)
 	case constants.START_COMMAND:
 		user, err := state.usersRepo.GetByTgId(ctx, message.From.ID)
 		if err != nil {
 			return fmt.Errorf("failed to get user by id during handling start command: %w", err)
 		}
-		msg := tgbotapi.NewMessage(message.Chat.ID, `Воспользуйтесь /help для получения списка команд. Для отправки заявок на лабораторные
-		 вы должны либо стать админом группы,с одобрения владельца бота,либо же членом группы,если у неё уже есть админ.`)
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.START_TEXT))
 		err = tgutils.CreateStartReplyMarkup(ctx, &msg, user, state.bot)
 		if err != nil {
 			return fmt.Errorf("failed to create start reply markup during start command: %w", err)
@@ -99,11 +81,6 @@ func (state *idleState) Handle(ctx context.Context, message *tgbotapi.Message) e
 	default:
 		return errors.Join(errors.ErrUnsupported, errors.New("answers are only to commands"))
 	}
-	err := state.mux.Handle(ctx, message)
-	if err != nil {
-		return err
-	}
-	return nil
 }
 
 func (state *idleState) HandleTableCommand(ctx context.Context, msg *tgbotapi.Message) error {
@@ -112,7 +89,7 @@ func (state *idleState) HandleTableCommand(ctx context.Context, msg *tgbotapi.Me
 		return fmt.Errorf("failed to get user by tg id during queue command handling: %w", err)
 	}
 	if usr.GroupId == 0 {
-		_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, "Вы пока не принадлежите ни к одной группе"))
+		_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, i18n.T(ctx, i18n.NO_GROUP_TEXT)))
 		if err != nil {
 			return fmt.Errorf("failed to send no group message during queue command handling: %w", err)
 		}
@@ -129,6 +106,56 @@ func (state *idleState) HandleTableCommand(ctx context.Context, msg *tgbotapi.Me
 	return nil
 }
 
+const (
+	crashesShownCount  = 5
+	crashStackMaxRunes = 3000
+)
+
+func (state *idleState) HandleCrashesCommand(ctx context.Context, msg *tgbotapi.Message) error {
+	if !tgutils.IsOwner(msg.From.ID) {
+		return errors.Join(errors.ErrUnsupported, errors.New("crashes are only available to owners"))
+	}
+	reports, err := state.crashes.GetLatest(ctx, crashesShownCount)
+	if err != nil {
+		return fmt.Errorf("failed to get latest crashes during crashes command handling: %w", err)
+	}
+	if len(reports) == 0 {
+		_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, i18n.T(ctx, i18n.NO_CRASHES_TEXT)))
+		if err != nil {
+			return fmt.Errorf("failed to send no crashes message during crashes command handling: %w", err)
+		}
+		return nil
+	}
+	for _, report := range reports {
+		_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, state.formatCrashReport(ctx, &report)))
+		if err != nil {
+			return fmt.Errorf("failed to send crash report during crashes command handling: %w", err)
+		}
+	}
+	return nil
+}
+
+func (state *idleState) formatCrashReport(ctx context.Context, report *interfaces.CrashReport) string {
+	builder := strings.Builder{}
+	fmt.Fprintf(&builder, "#%d %s\n", report.Id, report.Time.Format(time.DateTime))
+	builder.WriteString(i18n.T(ctx, i18n.CRASH_CHAT_LINE, report.ChatId, report.UserId, report.UpdateId))
+	builder.WriteString(i18n.T(ctx, i18n.CRASH_STATE_LINE, report.State))
+	if report.CallbackData != "" {
+		fmt.Fprintf(&builder, "Callback: %s\n", report.CallbackData)
+	}
+	if report.MessageText != "" {
+		builder.WriteString(i18n.T(ctx, i18n.CRASH_TEXT_LINE, report.MessageText))
+	}
+	builder.WriteString(i18n.T(ctx, i18n.CRASH_ERROR_LINE, report.Error))
+	if stack := []rune(report.Stack); len(stack) > crashStackMaxRunes {
+		builder.WriteString(string(stack[:crashStackMaxRunes]))
+		builder.WriteString("...")
+	} else {
+		builder.WriteString(report.Stack)
+	}
+	return builder.String()
+}
+
 func (state *idleState) createSheetUrl(spreadsheetId string) string {
 	return fmt.Sprintf("https://docs.google.com/spreadsheets/d/%s/edit#gid=0", spreadsheetId)
 }
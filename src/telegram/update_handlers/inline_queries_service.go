@@ -0,0 +1,44 @@
+package update_handlers
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+type InlineQueryHandler interface {
+	HandleInlineQuery(ctx context.Context, query *tgbotapi.InlineQuery, bot *tgutils.Bot) error
+}
+
+// InlineQueriesService handles inline queries, which aren't bound to any chat, so they don't take part in flows
+type InlineQueriesService struct {
+	handler  InlineQueryHandler
+	reporter *CrashReporter
+}
+
+func NewInlineQueriesService(handler InlineQueryHandler, reporter *CrashReporter) *InlineQueriesService {
+	return &InlineQueriesService{handler: handler, reporter: reporter}
+}
+
+func (serv *InlineQueriesService) HandleInlineQueries(update *tgbotapi.Update, bot *tgutils.Bot) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = serv.reporter.ReportPanic(update, r, debug.Stack())
+		}
+	}()
+	// Telegram drops answers to inline queries, which weren't given in a few seconds
+	ctx, cancel := context.WithTimeout(context.Background(), constants.TG_TIMEOUT)
+	defer cancel()
+	if update.InlineQuery == nil {
+		return nil
+	}
+	ctx = bot.Localize(ctx, update.InlineQuery.From.ID, update.InlineQuery.From.LanguageCode)
+	err = serv.handler.HandleInlineQuery(ctx, update.InlineQuery, bot)
+	if err != nil {
+		serv.reporter.Report(update, err)
+	}
+	return err
+}
@@ -2,16 +2,17 @@ package labworks
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"slices"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	iis_api_entities "github.com/aCrYoZPS/bsuir_queue_bot/src/iis_api/entities"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
@@ -19,8 +20,6 @@ import (
 	datetime "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/date_time"
 	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 	"google.golang.org/api/googleapi"
 )
 
@@ -30,6 +29,11 @@ type SheetsService interface {
 	AddLabworkRequest(context.Context, *AppendedLabwork) error
 }
 
+// QueueRefresher edits messages, which are subscribed to the queue of the lesson
+type QueueRefresher interface {
+	Refresh(lessonId int64)
+}
+
 type AppendedLabwork struct {
 	RequestedDate  datetime.DateOnly
 	SentProofTime  datetime.DateTime
@@ -79,11 +83,13 @@ type LabworksCallbackHandler struct {
 	sheets          SheetsService
 	labworks        LabworksService
 	users           UsersService
+	payloads        *tgutils.CallbackPayloads
+	queues          QueueRefresher
 }
 
 func NewLabworksCallbackHandler(bot *tgutils.Bot, cache interfaces.HandlersCache, labworks LabworksService, 
 	requests interfaces.RequestsRepository, labworkRequests interfaces.LessonsRequestsRepository, 
-	users UsersService, sheets SheetsService) *LabworksCallbackHandler {
+	users UsersService, sheets SheetsService, payloads *tgutils.CallbackPayloads, queues QueueRefresher) *LabworksCallbackHandler {
 	return &LabworksCallbackHandler{
 		bot:   bot,
 		cache: cache,
@@ -93,46 +99,55 @@ func NewLabworksCallbackHandler(bot *tgutils.Bot, cache interfaces.HandlersCache
 		labworkRequests: labworkRequests,
 		users:           users,
 		sheets:          sheets,
+		payloads:        payloads,
+		queues:          queues,
 	}
 }
 
 func (handler *LabworksCallbackHandler) HandleCallback(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) error {
 	if strings.HasPrefix(update.CallbackData(), constants.LABWORK_DISCIPLINE_CALLBACKS) {
-		discipline, userTgId := parseLabworkDisciplineCallback(update.CallbackData())
-		if discipline == "" || userTgId == 0 {
-			return errors.New("invalid command requested")
+		payload, err := parseLabworkDisciplineCallback(ctx, handler.payloads, update.CallbackData())
+		if err != nil {
+			return err
 		}
-		err := handler.handleDisciplineCallback(ctx, update.CallbackQuery.Message, discipline)
+		err = handler.handleDisciplineCallback(ctx, update.CallbackQuery.Message, payload.Discipline)
 		if err != nil {
 			if errors.Is(err, errNoLessons) {
 				err := handler.cache.SaveState(ctx, *interfaces.NewCachedInfo(update.CallbackQuery.Message.Chat.ID, constants.IDLE_STATE))
 				if err != nil {
 					return fmt.Errorf("failed to transition to idle state during labwork callback handling: %w", err)
 				}
-				_, err = bot.SendCtx(ctx, tgbotapi.NewMessage(update.CallbackQuery.Message.Chat.ID,
-					 cases.Title(language.English).String(errNoLessons.Error())))
-				return fmt.Errorf("failed to send no lessons error to user during labwork callback handling: %w", err)
+				_, err = bot.SendCtx(ctx, tgbotapi.NewMessage(update.CallbackQuery.Message.Chat.ID, i18n.T(ctx, i18n.NO_LABWORKS_LEFT_TEXT)))
+				if err != nil {
+					return fmt.Errorf("failed to send no lessons error to user during labwork callback handling: %w", err)
+				}
+				return nil
 			}
 			return err
 		}
 		return nil
 	}
-	if strings.HasPrefix(update.CallbackData(), constants.LABWORK_TIME_CALLBACKS) {
-		date, labworkId, subgroup := parseLabworkTimeCallback(update.CallbackData())
-		if date.Equal(time.Time{}) || labworkId == 0 {
-			return fmt.Errorf("couldn't get time and tg id from labwork callback (%s)", update.CallbackData())
-		}
-		err := handler.handleTimeCallback(ctx, update.CallbackQuery.Message, date, labworkId, subgroup)
+	if labworkTimeCodec.Matches(update.CallbackData()) {
+		data, err := labworkTimeCodec.Decode(update.CallbackData())
 		if err != nil {
 			return err
 		}
-		return nil
+		date := time.Date(data.Date.Year(), data.Date.Month(), data.Date.Day(), 0, 0, 0, 0, time.Local)
+		return handler.handleTimeCallback(ctx, update.CallbackQuery.Message, date, data.LabworkId, data.Subgroup)
 	}
-	if strings.HasPrefix(update.CallbackData(), constants.LABWORK_ACCEPT_CALLBACKS) {
-		return handler.handleAcceptCallback(ctx, update.CallbackQuery.Message, update.CallbackQuery.Data, bot)
+	if acceptCodec.Matches(update.CallbackData()) {
+		data, err := acceptCodec.Decode(update.CallbackData())
+		if err != nil {
+			return err
+		}
+		return handler.handleAcceptCallback(ctx, update.CallbackQuery.Message, data, bot)
 	}
-	if strings.HasPrefix(update.CallbackData(), constants.LABWORK_DECLINE_CALLBACK) {
-		return handler.handleDeclineCallback(ctx, update.CallbackQuery.Message, update.CallbackQuery.Data, bot)
+	if declineCodec.Matches(update.CallbackData()) {
+		data, err := declineCodec.Decode(update.CallbackData())
+		if err != nil {
+			return err
+		}
+		return handler.handleDeclineCallback(ctx, update.CallbackQuery.Message, data, bot)
 	}
 	if strings.HasPrefix(update.CallbackData(), constants.LABWORK_TIME_CANCEL_CALLBACKS) {
 		return handler.handleTimeCancelCallback(ctx, update.CallbackQuery.Message)
@@ -162,7 +177,7 @@ func (handler *LabworksCallbackHandler) handleDisciplineCallback(ctx context.Con
 	if err != nil {
 		return fmt.Errorf("failed to save info to cache during labworks discipline callback: %w", err)
 	}
-	keyboard := handler.createDisciplinesKeyboard(lessons)
+	keyboard := handler.createDisciplinesKeyboard(ctx, lessons)
 	_, err = handler.bot.SendCtx(ctx, tgbotapi.NewEditMessageReplyMarkup(message.Chat.ID, message.MessageID, *keyboard))
 	if err != nil {
 		return fmt.Errorf("failed to send keyboard during labworks callback handling: %w", err)
@@ -170,7 +185,7 @@ func (handler *LabworksCallbackHandler) handleDisciplineCallback(ctx context.Con
 	return nil
 }
 
-func (handler *LabworksCallbackHandler) createDisciplinesKeyboard(lessons []persistence.Lesson) *tgbotapi.InlineKeyboardMarkup {
+func (handler *LabworksCallbackHandler) createDisciplinesKeyboard(ctx context.Context, lessons []persistence.Lesson) *tgbotapi.InlineKeyboardMarkup {
 	markup := [][]tgbotapi.InlineKeyboardButton{}
 	for _, lesson := range lessons {
 		row := []tgbotapi.InlineKeyboardButton{}
@@ -178,67 +193,23 @@ func (handler *LabworksCallbackHandler) createDisciplinesKeyboard(lessons []pers
 		if lesson.SubgroupNumber != iis_api_entities.AllSubgroups {
 			formattedDate += fmt.Sprintf(" (%d)", lesson.SubgroupNumber)
 		}
-		row = append(row,
-			 tgbotapi.NewInlineKeyboardButtonData(formattedDate, createLabworkTimeCallback(lesson.Id, lesson.DateTime, lesson.SubgroupNumber)))
+		data := labworkTimeCodec.MustEncode(labworkTimeCallback{LabworkId: lesson.Id, Date: lesson.DateTime, Subgroup: lesson.SubgroupNumber})
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(formattedDate, data))
 		markup = append(markup, row)
 	}
 	markup = append(markup, 
-		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("Назад", constants.LABWORK_TIME_CANCEL_CALLBACKS)))
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.BACK_BUTTON), constants.LABWORK_TIME_CANCEL_CALLBACKS)))
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(markup...)
 	return &keyboard
 }
 
-const tgCallbackLimit = 64
-
-func createLabworkTimeCallback(labworkId int64, date time.Time, subgroup iis_api_entities.Subgroup) string {
-	builder := strings.Builder{}
-	builder.Grow(tgCallbackLimit)
-	builder.WriteString(constants.LABWORK_TIME_CALLBACKS)
-	builder.WriteString("|")
-	fmt.Fprintf(&builder, "%d.%d.%d", date.Day(), date.Month(), date.Year())
-	builder.WriteString("|")
-	fmt.Fprint(&builder, labworkId)
-	builder.WriteString("|")
-	fmt.Fprint(&builder, subgroup)
-	return builder.String()
+type labworkTimeCallback struct {
+	LabworkId int64
+	Date      time.Time
+	Subgroup  int8
 }
 
-func parseLabworkTimeCallback(callback string) (date time.Time, labworkId int64, subgroup int8) {
-	callback, _ = strings.CutPrefix(callback, constants.LABWORK_TIME_CALLBACKS+"|")
-	subgroup = iis_api_entities.AllSubgroups
-	formattedDate, after, _ := strings.Cut(callback, "|")
-
-	after, subgroupString, _ := strings.Cut(after, "|")
-	labworkId, err := strconv.ParseInt(after, 10, 64)
-	if err != nil {
-		return time.Time{}, 0, subgroup
-	}
-	nums := strings.Split(formattedDate, ".")
-	if len(nums) < 3 {
-		return time.Time{}, 0, subgroup
-	}
-	day, err := strconv.Atoi(nums[0])
-	if err != nil {
-		return time.Time{}, 0, subgroup
-	}
-	month, err := strconv.Atoi(nums[1])
-	if err != nil {
-		return time.Time{}, 0, subgroup
-	}
-	year, err := strconv.Atoi(nums[2])
-	if err != nil {
-		return time.Time{}, 0, subgroup
-	}
-	date = time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.Local)
-	if subgroupString != "" {
-		subgroupVal, err := strconv.Atoi(subgroupString)
-		if err != nil {
-			return time.Time{}, 0, subgroup
-		}
-		subgroup = int8(subgroupVal)
-	}
-	return date, labworkId, subgroup
-}
+var labworkTimeCodec = tgutils.NewCallbackCodec[labworkTimeCallback](constants.LABWORK_TIME_CALLBACKS, 1)
 
 func (handler *LabworksCallbackHandler) handleTimeCallback(ctx context.Context, msg *tgbotapi.Message, date time.Time, 
 	labworkId int64, subgroup int8) error {
@@ -275,7 +246,7 @@ func (handler *LabworksCallbackHandler) handleTimeCallback(ctx context.Context,
 		return fmt.Errorf("failed to remove markup during labwork time callback handling: %w", err)
 	}
 
-	_, err = handler.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, "Введите номер сдаваемой лабораторной работы"))
+	_, err = handler.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, i18n.T(ctx, i18n.ENTER_LABWORK_NUMBER_TEXT)))
 	if err != nil {
 		return fmt.Errorf("failed to send response to user during time callback handling: %w", err)
 	}
@@ -295,7 +266,11 @@ func (handler *LabworksCallbackHandler) handleTimeCancelCallback(ctx context.Con
 	for chunk := range slices.Chunk(disciplines, CHUNK_SIZE) {
 		row := []tgbotapi.InlineKeyboardButton{}
 		for _, discipline := range chunk {
-			row = append(row, tgbotapi.NewInlineKeyboardButtonData(discipline, createLabworkDisciplineCallback(msg.Chat.ID, discipline)))
+			data, err := createLabworkDisciplineCallback(ctx, handler.payloads, msg.Chat.ID, discipline)
+			if err != nil {
+				return err
+			}
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(discipline, data))
 		}
 		markup = append(markup, row)
 	}
@@ -307,11 +282,14 @@ func (handler *LabworksCallbackHandler) handleTimeCancelCallback(ctx context.Con
 	return nil
 }
 
-func (handler *LabworksCallbackHandler) handleAcceptCallback(ctx context.Context, msg *tgbotapi.Message, command string, 
+func (handler *LabworksCallbackHandler) handleAcceptCallback(ctx context.Context, msg *tgbotapi.Message, data *requestDecisionCallback, 
 	bot *tgutils.Bot) error {
-	chatId, msgId := parseAcceptCallback(command)
+	chatId, msgId := data.TgId, data.MessageId
 
 	request, err := handler.labworkRequests.GetByTgIds(ctx, msgId, chatId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return handler.handleWithdrawnRequest(ctx, msg, bot)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get labwork request by tg ids during labwork accept callback handling: %w", err)
 	}
@@ -336,7 +314,7 @@ func (handler *LabworksCallbackHandler) handleAcceptCallback(ctx context.Context
 		if googleErr, ok := err.(*googleapi.Error); ok {
 			if googleErr.Code == http.StatusInternalServerError {
 				_, err := handler.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, 
-					"Ошибка на стороне гугл сервисов. Попробуйте одобрить заявку позже"))
+					i18n.T(ctx, i18n.GOOGLE_ERROR_ACCEPT_TEXT)))
 				if err != nil {
 					return fmt.Errorf("failed to send google errors failure response during labworks accept callback handling: %w", err)
 				}
@@ -350,13 +328,14 @@ func (handler *LabworksCallbackHandler) handleAcceptCallback(ctx context.Context
 	if err != nil {
 		return fmt.Errorf("failed to add labwork request during labwork accept callback handling: %w", err)
 	}
+	handler.queues.Refresh(lesson.Id)
 
 	err = handler.RemoveMarkup(ctx, msg, bot)
 	if err != nil {
 		return fmt.Errorf("failed to remove markup during labworks accept callback handling: %w", err)
 	}
 
-	resp := tgbotapi.NewMessage(request.ChatId, "Ваша заявка была принята")
+	resp := tgbotapi.NewMessage(request.ChatId, i18n.T(bot.Localize(ctx, request.UserId, ""), i18n.LABWORK_REQUEST_ACCEPTED_TEXT))
 	resp.ReplyToMessageID = int(request.MsgId)
 	_, err = bot.SendCtx(ctx, resp)
 	if err != nil {
@@ -402,11 +381,27 @@ func (handler *LabworksCallbackHandler) RemoveMarkup(ctx context.Context, msg *t
 	return nil
 }
 
-func (handler *LabworksCallbackHandler) handleDeclineCallback(ctx context.Context, msg *tgbotapi.Message, command string, 
+// handleWithdrawnRequest removes buttons of the request, which was withdrawn by the student before admin's decision
+func (handler *LabworksCallbackHandler) handleWithdrawnRequest(ctx context.Context, msg *tgbotapi.Message, bot *tgutils.Bot) error {
+	err := handler.RemoveMarkup(ctx, msg, bot)
+	if err != nil {
+		return err
+	}
+	_, err = bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, i18n.T(ctx, i18n.LABWORK_REQUEST_WITHDRAWN_TEXT)))
+	if err != nil {
+		return fmt.Errorf("failed to notify admin about withdrawn labwork request: %w", err)
+	}
+	return nil
+}
+
+func (handler *LabworksCallbackHandler) handleDeclineCallback(ctx context.Context, msg *tgbotapi.Message, data *requestDecisionCallback, 
 	bot *tgutils.Bot) error {
-	chatId, msgId := parseDeclineCallback(command)
+	chatId, msgId := data.TgId, data.MessageId
 
 	request, err := handler.labworkRequests.GetByTgIds(ctx, msgId, chatId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return handler.handleWithdrawnRequest(ctx, msg, bot)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get labwork request by tg ids during labwork decline callback handling: %w", err)
 	}
@@ -423,7 +418,7 @@ func (handler *LabworksCallbackHandler) handleDeclineCallback(ctx context.Contex
 		return fmt.Errorf("failed to delete labwork request during decline callback handling: %w", err)
 	}
 
-	resp := tgbotapi.NewMessage(chatId, "Ваша заявка была отклонена")
+	resp := tgbotapi.NewMessage(chatId, i18n.T(bot.Localize(ctx, request.UserId, ""), i18n.REQUEST_DECLINED_TEXT))
 	resp.ReplyToMessageID = int(msgId)
 	_, err = bot.SendCtx(ctx, resp)
 	if err != nil {
@@ -6,16 +6,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"os"
 	"slices"
 	"strconv"
-	"strings"
 	"text/template"
 	"time"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
@@ -45,11 +42,12 @@ type labworkSubmitStartState struct {
 	cache    interfaces.HandlersCache
 	labworks LabworksService
 	users    UsersService
+	payloads *tgutils.CallbackPayloads
 }
 
 func NewLabworkSubmitStartState(bot *tgutils.Bot, cache interfaces.HandlersCache, labworks LabworksService, 
-	users UsersService) *labworkSubmitStartState {
-	return &labworkSubmitStartState{bot: bot, cache: cache, labworks: labworks, users: users}
+	users UsersService, payloads *tgutils.CallbackPayloads) *labworkSubmitStartState {
+	return &labworkSubmitStartState{bot: bot, cache: cache, labworks: labworks, users: users, payloads: payloads}
 }
 
 func (state *labworkSubmitStartState) Handle(ctx context.Context, message *tgbotapi.Message) error {
@@ -58,7 +56,7 @@ func (state *labworkSubmitStartState) Handle(ctx context.Context, message *tgbot
 		return err
 	}
 	if user.Id == 0 {
-		err := state.TransitionAndSend(ctx, tgbotapi.NewMessage(message.Chat.ID, "Вы ещё не присоединились к какой-либо группе"), 
+		err := state.TransitionAndSend(ctx, tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.NO_GROUP_TEXT)), 
 		interfaces.NewCachedInfo(message.Chat.ID, constants.IDLE_STATE))
 		return err
 	}
@@ -69,7 +67,7 @@ func (state *labworkSubmitStartState) Handle(ctx context.Context, message *tgbot
 		}
 		return err
 	}
-	resp := tgbotapi.NewMessage(message.Chat.ID, "Выберите предмет и дату пары")
+	resp := tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.CHOOSE_DISCIPLINE_AND_DATE_TEXT))
 	resp.ReplyMarkup = replyMarkup
 	err = state.cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, constants.LABWORK_SUBMIT_WAITING_STATE))
 	if err != nil {
@@ -109,7 +107,7 @@ func (state *labworkSubmitStartState) createDisciplinesKeyboard(ctx context.Cont
 	}
 	if len(disciplines) == 0 {
 		newState := interfaces.NewCachedInfo(chatId, constants.IDLE_STATE)
-		err = state.TransitionAndSend(ctx, tgbotapi.NewMessage(chatId, "Больше не осталось лабораторных. Отдохните"), newState)
+		err = state.TransitionAndSend(ctx, tgbotapi.NewMessage(chatId, i18n.T(ctx, i18n.NO_LABWORKS_LEFT_TEXT)), newState)
 		if err != nil {
 			return nil, err
 		}
@@ -118,7 +116,11 @@ func (state *labworkSubmitStartState) createDisciplinesKeyboard(ctx context.Cont
 	for chunk := range slices.Chunk(disciplines, CHUNK_SIZE) {
 		row := []tgbotapi.InlineKeyboardButton{}
 		for _, discipline := range chunk {
-			row = append(row, tgbotapi.NewInlineKeyboardButtonData(discipline, createLabworkDisciplineCallback(userTgId, discipline)))
+			data, err := createLabworkDisciplineCallback(ctx, state.payloads, userTgId, discipline)
+			if err != nil {
+				return nil, err
+			}
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(discipline, data))
 		}
 		markup = append(markup, row)
 	}
@@ -136,20 +138,26 @@ func (state *labworkSubmitStartState) TransitionAndSend(ctx context.Context, msg
 	return err
 }
 
-func createLabworkDisciplineCallback(userTgId int64, discipline string) string {
-	builder := strings.Builder{}
-	fmt.Fprintf(&builder, "%s|%s|%d", constants.LABWORK_DISCIPLINE_CALLBACKS, discipline, userTgId)
-	return builder.String()
+type disciplinePayload struct {
+	Discipline string `json:"discipline"`
+	UserTgId   int64  `json:"tg_id"`
 }
 
-func parseLabworkDisciplineCallback(callback string) (discipline string, userTgId int64) {
-	callback, _ = strings.CutPrefix(callback, constants.LABWORK_DISCIPLINE_CALLBACKS+"|")
-	discipline, after, _ := strings.Cut(callback, "|")
-	userTgId, err := strconv.ParseInt(after, 10, 64)
+// Discipline names are often too long to fit into callback data, so they are stored on the server side
+func createLabworkDisciplineCallback(ctx context.Context, payloads *tgutils.CallbackPayloads, userTgId int64, discipline string) (string, error) {
+	data, err := tgutils.PutPayload(ctx, payloads, constants.LABWORK_DISCIPLINE_CALLBACKS, disciplinePayload{Discipline: discipline, UserTgId: userTgId})
 	if err != nil {
-		return "", 0
+		return "", fmt.Errorf("failed to create labwork discipline callback: %w", err)
 	}
-	return discipline, userTgId
+	return data, nil
+}
+
+func parseLabworkDisciplineCallback(ctx context.Context, payloads *tgutils.CallbackPayloads, callback string) (*disciplinePayload, error) {
+	payload, err := tgutils.GetPayload[disciplinePayload](ctx, payloads, constants.LABWORK_DISCIPLINE_CALLBACKS, callback)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse labwork discipline callback: %w", err)
+	}
+	return payload, nil
 }
 
 type StateMachine interface {
@@ -167,7 +175,7 @@ func NewLabworkSubmitWaitingState(bot *tgutils.Bot, cache interfaces.HandlersCac
 
 func (state *labworkSubmitWaitingState) Handle(ctx context.Context, message *tgbotapi.Message) error {
 	_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, 
-		"Пожалуйста, закончите отправление заявки, прежде чем переходить к остальным командам"))
+		i18n.T(ctx, i18n.FINISH_REQUEST_FIRST_TEXT)))
 	if err != nil {
 		return fmt.Errorf("couldn't send wait message: %v", err)
 	}
@@ -225,7 +233,7 @@ func (state *labworkSubmitNumberState) Handle(ctx context.Context, message *tgbo
 	num, err := strconv.ParseUint(message.Text, 10, 8)
 	if err != nil || num == 0 || num > 255 {
 		_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, 
-			"Пожалуйста,введите корректный номер лабораторной (одно число, в разумных пределах)"))
+			i18n.T(ctx, i18n.INVALID_LABWORK_NUMBER_TEXT)))
 		if err != nil {
 			return fmt.Errorf("failed to send incorrect number msg during labwork submit number state: %w", err)
 		}
@@ -252,7 +260,7 @@ func (state *labworkSubmitNumberState) Handle(ctx context.Context, message *tgbo
 		return fmt.Errorf("failed to transition to labwork proof submit state during labwork submit number state handling: %w", err)
 	}
 	_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(message.Chat.ID, 
-		"Введите доказательство готовности лабораторной работы (один прикрепленный файл, возможно с текстовой подписью)"))
+		i18n.T(ctx, i18n.ENTER_LABWORK_PROOF_TEXT)))
 	if err != nil {
 		return fmt.Errorf("failed to send response during labwork number submit state: %w", err)
 	}
@@ -321,7 +329,7 @@ func (state *labworkSubmitProofState) Handle(ctx context.Context, message *tgbot
 		}
 	}
 
-	msg := tgbotapi.NewMessage(message.Chat.ID, "Ваша заявка была отправлена администраторам")
+	msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.LABWORK_REQUEST_SENT_TEXT))
 	msg.ReplyToMessageID = message.MessageID
 	_, err = state.bot.SendCtx(ctx, msg)
 	if err != nil {
@@ -341,7 +349,7 @@ func (state *labworkSubmitProofState) Revert(ctx context.Context, msg *tgbotapi.
 		return fmt.Errorf("failed to change state while reverting labwork submit proof state: %w", err)
 	}
 
-	_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, "Введите номер сдаваемой лабораторной работы"))
+	_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, i18n.T(ctx, i18n.ENTER_LABWORK_NUMBER_TEXT)))
 	if err != nil {
 		return fmt.Errorf("failed to send response to user during time callback handling: %w", err)
 	}
@@ -365,7 +373,7 @@ func (state *labworkSubmitProofState) handleDocumentType(ctx context.Context, ad
 	}
 	if errors.Is(err, tgutils.ErrMsgInvalidLen) {
 		_, err := state.bot.SendCtx(ctx,
-			 tgbotapi.NewMessage(message.Chat.ID, "Извините,ваше сообщение слишком большое для отправки. Измените его и отправьте снова"))
+			 tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.MESSAGE_TOO_LONG_TEXT)))
 		if err != nil {
 			return fmt.Errorf("failed to send too large message during labwork submit proof state: %w", err)
 		}
@@ -377,7 +385,7 @@ func (state *labworkSubmitProofState) handleDocumentType(ctx context.Context, ad
 func (state *labworkSubmitProofState) handlePhotoProof(ctx context.Context, admins []entities.User, message *tgbotapi.Message, 
 	form *LabworkRequest) error {
 	maxSizeId := tgutils.SelectMaxSizedPhoto(message.Photo)
-	fileBytes, err := state.GetFileBytes(maxSizeId)
+	fileBytes, err := state.bot.DownloadFile(ctx, maxSizeId)
 	if err != nil {
 		return err
 	}
@@ -389,7 +397,7 @@ func (state *labworkSubmitProofState) handlePhotoProof(ctx context.Context, admi
 func (state *labworkSubmitProofState) handleDocumentProof(ctx context.Context, admins []entities.User, message *tgbotapi.Message, 
 	form *LabworkRequest) error {
 	maxSizeId := message.Document.FileID
-	fileBytes, err := state.GetFileBytes(maxSizeId)
+	fileBytes, err := state.bot.DownloadFile(ctx, maxSizeId)
 	if err != nil {
 		return err
 	}
@@ -401,7 +409,7 @@ func (state *labworkSubmitProofState) handleDocumentProof(ctx context.Context, a
 func (state *labworkSubmitProofState) handleVideoProof(ctx context.Context, admins []entities.User, message *tgbotapi.Message,
 	 form *LabworkRequest) error {
 	maxSizeId := message.Video.FileID
-	fileBytes, err := state.GetFileBytes(maxSizeId)
+	fileBytes, err := state.bot.DownloadFile(ctx, maxSizeId)
 	if err != nil {
 		return err
 	}
@@ -410,28 +418,6 @@ func (state *labworkSubmitProofState) handleVideoProof(ctx context.Context, admi
 	return err
 }
 
-func (state *labworkSubmitProofState) GetFileBytes(fileId string) ([]byte, error) {
-	file, err := state.bot.GetFile(tgbotapi.FileConfig{FileID: fileId})
-	if err != nil {
-		return nil, err
-	}
-	token := os.Getenv("BOT_TOKEN")
-	if token == "" {
-		return nil, errors.New("couldn't receive bot_token")
-	}
-	link := file.Link(os.Getenv("BOT_TOKEN"))
-	resp, err := http.Get(link)
-	if err != nil && err != io.EOF {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	bytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	return bytes, nil
-}
-
 var funcMap = template.FuncMap{"dateTime": func(ts datetime.DateTime) string {
 	t := time.Time(ts)
 	return fmt.Sprintf("%02d.%02d.%02d %02d:%02d:%02d", t.Day(), t.Month(), t.Year(), t.Hour(), t.Minute(), t.Second())
@@ -440,22 +426,31 @@ var funcMap = template.FuncMap{"dateTime": func(ts datetime.DateTime) string {
 	return fmt.Sprintf("%02d.%02d.%d", t.Day(), t.Month(), t.Year())
 }}
 
-const tmplText = "Отправил: {{.FullName}}\nПредмет: {{.DisciplineName}}\nНомер лабораторной: {{.LabworkNumber}}\n"+
-"Дата: {{date .RequestedDate}}\nВремя отправки: {{dateTime .SentProofTime}}\n{{if .Notes}}Доп информация: {{.Notes}} {{end}}"
-
-var adminSendingTmpl = template.Must(template.New("adminProofSent").Funcs(funcMap).Parse(tmplText))
-
-func (state *labworkSubmitProofState) SendPhotosToAdmins(ctx context.Context, admins []entities.User, photo *tgbotapi.PhotoConfig,
-	 form *LabworkRequest) error {
+// Renders the request in the locale of ctx, so every admin gets it in their own language
+func renderRequest(ctx context.Context, form *LabworkRequest) (string, error) {
+	tmpl, err := template.New("adminProofSent").Funcs(funcMap).Parse(i18n.T(ctx, i18n.LABWORK_REQUEST_TEMPLATE))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse labwork request template: %w", err)
+	}
 	var buf bytes.Buffer
-	err := adminSendingTmpl.Execute(&buf, form)
+	err = tmpl.Execute(&buf, form)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("failed to execute labwork request template: %w", err)
 	}
-	photo.ReplyMarkup = createMarkupKeyboard(form)
-	photo.Caption = buf.String()
+	return buf.String(), nil
+}
+
+func (state *labworkSubmitProofState) SendPhotosToAdmins(ctx context.Context, admins []entities.User, photo *tgbotapi.PhotoConfig,
+	 form *LabworkRequest) error {
 	reqUUID := uuid.NewString()
 	for _, admin := range admins {
+		adminCtx := state.bot.Localize(ctx, admin.TgId, "")
+		text, err := renderRequest(adminCtx, form)
+		if err != nil {
+			return err
+		}
+		photo.ReplyMarkup = createMarkupKeyboard(adminCtx, form)
+		photo.Caption = text
 		photo.ChatID = admin.TgId
 		sentMsg, err := state.bot.SendCtx(ctx, photo)
 		if err != nil {
@@ -472,15 +467,15 @@ func (state *labworkSubmitProofState) SendPhotosToAdmins(ctx context.Context, ad
 
 func (state *labworkSubmitProofState) SendMessagesToAdmins(ctx context.Context, admins []entities.User, msg *tgbotapi.MessageConfig,
 	 form *LabworkRequest) error {
-	var buf bytes.Buffer
-	err := adminSendingTmpl.Execute(&buf, form)
-	if err != nil {
-		return err
-	}
-	msg.ReplyMarkup = createMarkupKeyboard(form)
-	msg.Text = buf.String()
 	reqUUID := uuid.NewString()
 	for _, admin := range admins {
+		adminCtx := state.bot.Localize(ctx, admin.TgId, "")
+		text, err := renderRequest(adminCtx, form)
+		if err != nil {
+			return err
+		}
+		msg.ReplyMarkup = createMarkupKeyboard(adminCtx, form)
+		msg.Text = text
 		msg.ChatID = admin.TgId
 		sentMsg, err := state.bot.SendCtx(ctx, msg)
 		if err != nil {
@@ -497,15 +492,15 @@ func (state *labworkSubmitProofState) SendMessagesToAdmins(ctx context.Context,
 
 func (state *labworkSubmitProofState) SendDocumentsToAdmins(ctx context.Context, admins []entities.User, msg *tgbotapi.DocumentConfig, 
 	form *LabworkRequest) error {
-	var buf bytes.Buffer
-	err := adminSendingTmpl.Execute(&buf, form)
-	if err != nil {
-		return err
-	}
-	msg.ReplyMarkup = createMarkupKeyboard(form)
-	msg.Caption = buf.String()
 	reqUUID := uuid.NewString()
 	for _, admin := range admins {
+		adminCtx := state.bot.Localize(ctx, admin.TgId, "")
+		text, err := renderRequest(adminCtx, form)
+		if err != nil {
+			return err
+		}
+		msg.ReplyMarkup = createMarkupKeyboard(adminCtx, form)
+		msg.Caption = text
 		msg.ChatID = admin.TgId
 		sentMsg, err := state.bot.SendCtx(ctx, msg)
 		if err != nil {
@@ -522,15 +517,15 @@ func (state *labworkSubmitProofState) SendDocumentsToAdmins(ctx context.Context,
 
 func (state *labworkSubmitProofState) SendVideoToAdmins(ctx context.Context, admins []entities.User, msg *tgbotapi.VideoConfig,
 	form *LabworkRequest) error {
-	var buf bytes.Buffer
-	err := adminSendingTmpl.Execute(&buf, form)
-	if err != nil {
-		return err
-	}
-	msg.ReplyMarkup = createMarkupKeyboard(form)
-	msg.Caption = buf.String()
 	reqUUID := uuid.NewString()
 	for _, admin := range admins {
+		adminCtx := state.bot.Localize(ctx, admin.TgId, "")
+		text, err := renderRequest(adminCtx, form)
+		if err != nil {
+			return err
+		}
+		msg.ReplyMarkup = createMarkupKeyboard(adminCtx, form)
+		msg.Caption = text
 		msg.ChatID = admin.TgId
 		sentMsg, err := state.bot.SendCtx(ctx, msg)
 		if err != nil {
@@ -545,54 +540,43 @@ func (state *labworkSubmitProofState) SendVideoToAdmins(ctx context.Context, adm
 	return nil
 }
 
-func createMarkupKeyboard(form *LabworkRequest) *tgbotapi.InlineKeyboardMarkup {
+func createMarkupKeyboard(ctx context.Context, form *LabworkRequest) *tgbotapi.InlineKeyboardMarkup {
 	row := []tgbotapi.InlineKeyboardButton{}
 	acceptData := createAcceptCallback(form)
 	declineData := createDeclineCallback(form)
 	row = append(row, 
-		tgbotapi.NewInlineKeyboardButtonData("Принять", acceptData), tgbotapi.NewInlineKeyboardButtonData("Отклонить", declineData))
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ACCEPT_BUTTON), acceptData), tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.DECLINE_BUTTON), declineData))
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(row)
 	return &keyboard
 }
 
-func createAcceptCallback(form *LabworkRequest) string {
-	return constants.LABWORK_ACCEPT_CALLBACKS + fmt.Sprint(form.TgId) + "|" + fmt.Sprint(form.MessageId)
+type requestDecisionCallback struct {
+	TgId      int64
+	MessageId int64
 }
 
-func createDeclineCallback(form *LabworkRequest) string {
-	return constants.LABWORK_DECLINE_CALLBACK + fmt.Sprint(form.TgId) + "|" + fmt.Sprint(form.MessageId)
-}
+var (
+	acceptCodec  = tgutils.NewCallbackCodec[requestDecisionCallback](constants.LABWORK_ACCEPT_CALLBACKS, 1)
+	declineCodec = tgutils.NewCallbackCodec[requestDecisionCallback](constants.LABWORK_DECLINE_CALLBACK, 1)
+)
 
-func parseAcceptCallback(callback string) (tgId, msgId int64) {
-	ids := strings.TrimPrefix(callback, constants.LABWORK_ACCEPT_CALLBACKS)
-	splittedIds := strings.Split(ids, "|")
-	if len(splittedIds) != 2 {
-		return 0, 0
-	}
-	tgId, err := strconv.ParseInt(splittedIds[0], 10, 64)
-	if err != nil {
-		return 0, 0
+// RequestCallbackKey keys accept and decline callbacks by the submitted request, so only one of its copies sent to admins is handled
+func RequestCallbackKey(ctx context.Context, update *tgbotapi.Update) (string, error) {
+	codec := acceptCodec
+	if declineCodec.Matches(update.CallbackQuery.Data) {
+		codec = declineCodec
 	}
-	msgId, err = strconv.ParseInt(splittedIds[1], 10, 64)
+	decision, err := codec.Decode(update.CallbackQuery.Data)
 	if err != nil {
-		return 0, 0
+		return "", fmt.Errorf("failed to decode labwork decision callback: %w", err)
 	}
-	return tgId, msgId
+	return fmt.Sprintf("%s:%d:%d", constants.LABWORK_CALLBACKS, decision.TgId, decision.MessageId), nil
 }
 
-func parseDeclineCallback(callback string) (tgId, msgId int64) {
-	ids := strings.TrimPrefix(callback, constants.LABWORK_DECLINE_CALLBACK)
-	splittedIds := strings.Split(ids, "|")
-	if len(splittedIds) != 2 {
-		return 0, 0
-	}
-	tgId, err := strconv.ParseInt(splittedIds[0], 10, 64)
-	if err != nil {
-		return 0, 0
-	}
-	msgId, err = strconv.ParseInt(splittedIds[1], 10, 64)
-	if err != nil {
-		return 0, 0
-	}
-	return tgId, msgId
+func createAcceptCallback(form *LabworkRequest) string {
+	return acceptCodec.MustEncode(requestDecisionCallback{TgId: form.TgId, MessageId: form.MessageId})
+}
+
+func createDeclineCallback(form *LabworkRequest) string {
+	return declineCodec.MustEncode(requestDecisionCallback{TgId: form.TgId, MessageId: form.MessageId})
 }
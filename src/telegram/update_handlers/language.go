@@ -0,0 +1,76 @@
+package update_handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+type languageCallback struct {
+	Locale string
+}
+
+var LanguageCodec = tgutils.NewCallbackCodec[languageCallback](constants.LANGUAGE_CALLBACKS, 1)
+
+func (state *idleState) HandleLanguageCommand(ctx context.Context, msg *tgbotapi.Message) error {
+	row := []tgbotapi.InlineKeyboardButton{}
+	for _, locale := range i18n.Locales {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(i18n.Name(locale), LanguageCodec.MustEncode(languageCallback{Locale: string(locale)})))
+	}
+	resp := tgbotapi.NewMessage(msg.Chat.ID, i18n.T(ctx, i18n.CHOOSE_LANGUAGE_TEXT))
+	resp.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(row)
+	_, err := state.bot.SendCtx(ctx, resp)
+	if err != nil {
+		return fmt.Errorf("failed to send languages keyboard during language command handling: %w", err)
+	}
+	return nil
+}
+
+type LanguagesRepository interface {
+	SetLanguage(ctx context.Context, tgId int64, language string) (bool, error)
+}
+
+type LanguageCallbackHandler struct {
+	languages LanguagesRepository
+	menu      *CommandsMenu
+}
+
+func NewLanguageCallbackHandler(languages LanguagesRepository, menu *CommandsMenu) *LanguageCallbackHandler {
+	return &LanguageCallbackHandler{languages: languages, menu: menu}
+}
+
+func (handler *LanguageCallbackHandler) HandleCallback(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot, data *languageCallback) error {
+	locale, ok := i18n.Parse(data.Locale)
+	if !ok {
+		return fmt.Errorf("%w: unsupported locale %s", tgutils.ErrCallbackMalformed, data.Locale)
+	}
+	saved, err := handler.languages.SetLanguage(ctx, update.SentFrom().ID, string(locale))
+	if err != nil {
+		return fmt.Errorf("failed to save language during language callback handling: %w", err)
+	}
+	if !saved {
+		_, err = bot.RequestCtx(ctx, tgbotapi.NewCallbackWithAlert(update.CallbackQuery.ID, i18n.T(ctx, i18n.LANGUAGE_UNAVAILABLE_TEXT)))
+		if err != nil {
+			return fmt.Errorf("failed to answer language callback of unregistered user: %w", err)
+		}
+		return nil
+	}
+
+	ctx = i18n.WithLocale(ctx, locale)
+	msg := update.CallbackQuery.Message
+	_, err = bot.SendCtx(ctx, tgbotapi.NewEditMessageTextAndMarkup(msg.Chat.ID, msg.MessageID, i18n.T(ctx, i18n.LANGUAGE_CHANGED_TEXT),
+		tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}))
+	if err != nil {
+		return fmt.Errorf("failed to edit languages keyboard during language callback handling: %w", err)
+	}
+	// Commands of the user are scoped to the private chat, so that they are shown in the chosen language
+	err = handler.menu.Refresh(ctx, update.SentFrom().ID)
+	if err != nil {
+		return fmt.Errorf("failed to refresh commands during language callback handling: %w", err)
+	}
+	return nil
+}
@@ -2,37 +2,66 @@ package update_handlers
 
 import (
 	"context"
-	"log/slog"
 	"runtime/debug"
-	"slices"
 
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
 	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-var userCommands = []tgbotapi.BotCommand{
-	{Command: constants.HELP_COMMAND, Description: "Команды и информация"},
-	{Command: constants.SUBMIT_COMMAND, Description: "Запись на сдачу лабораторной"},
-	{Command: constants.ASSIGN_COMMAND, Description: "Отправка заявки на роль администратора группы"},
-	{Command: constants.JOIN_GROUP_COMMAND, Description: "Отправка заявки на участие в группе"},
-	{Command: constants.QUEUE_COMMAND, Description: "Получение очереди своей группы"},
-	{Command: constants.REVERT_COMMAND, Description: "Откат к предыдущему состоянию"},
-	{Command: constants.TABLE_COMMAND, Description: "Получение ссылки на гугл-таблицу своей группы"},
+// command is a bot command, which description is translated into the locale of the user
+type command struct {
+	Command     string
+	Description i18n.Key
 }
 
-var adminCommands = []tgbotapi.BotCommand{
-	{Command: constants.ADD_LABWORK_COMMAND, Description: "Добавление собственной пары"},
-	{Command: constants.DELETE_COMMAND, Description: "Удаление участника из группы"},
+var userCommands = []command{
+	{Command: constants.HELP_COMMAND, Description: i18n.HELP_DESCRIPTION},
+	{Command: constants.SUBMIT_COMMAND, Description: i18n.SUBMIT_DESCRIPTION},
+	{Command: constants.ASSIGN_COMMAND, Description: i18n.ASSIGN_DESCRIPTION},
+	{Command: constants.JOIN_GROUP_COMMAND, Description: i18n.JOIN_GROUP_DESCRIPTION},
+	{Command: constants.QUEUE_COMMAND, Description: i18n.QUEUE_DESCRIPTION},
+	{Command: constants.MY_REQUESTS_COMMAND, Description: i18n.MY_REQUESTS_DESCRIPTION},
+	{Command: constants.RESULTS_COMMAND, Description: i18n.RESULTS_DESCRIPTION},
+	{Command: constants.REVERT_COMMAND, Description: i18n.REVERT_DESCRIPTION},
+	{Command: constants.CANCEL_COMMAND, Description: i18n.CANCEL_DESCRIPTION},
+	{Command: constants.TABLE_COMMAND, Description: i18n.TABLE_DESCRIPTION},
+	{Command: constants.LANGUAGE_COMMAND, Description: i18n.LANGUAGE_DESCRIPTION},
+	{Command: constants.SETTINGS_COMMAND, Description: i18n.SETTINGS_DESCRIPTION},
 }
 
-func GetUserCommands() []tgbotapi.BotCommand {
-	return userCommands
+var adminCommands = []command{
+	{Command: constants.ADD_LABWORK_COMMAND, Description: i18n.ADD_LABWORK_DESCRIPTION},
+	{Command: constants.DELETE_COMMAND, Description: i18n.DELETE_DESCRIPTION},
+	{Command: constants.REORDER_COMMAND, Description: i18n.REORDER_DESCRIPTION},
+	{Command: constants.REMOVE_REQUEST_COMMAND, Description: i18n.REMOVE_REQUEST_DESCRIPTION},
+	{Command: constants.LESSON_MODE_COMMAND, Description: i18n.LESSON_MODE_DESCRIPTION},
 }
 
-func GetAdminCommands() []tgbotapi.BotCommand {
-	return adminCommands
+var ownerCommands = []command{
+	{Command: constants.CRASHES_COMMAND, Description: i18n.CRASHES_DESCRIPTION},
+}
+
+func GetUserCommands(locale i18n.Locale) []tgbotapi.BotCommand {
+	return translateCommands(locale, userCommands)
+}
+
+func GetAdminCommands(locale i18n.Locale) []tgbotapi.BotCommand {
+	return translateCommands(locale, adminCommands)
+}
+
+func GetOwnerCommands(locale i18n.Locale) []tgbotapi.BotCommand {
+	return translateCommands(locale, ownerCommands)
+}
+
+func translateCommands(locale i18n.Locale, commands []command) []tgbotapi.BotCommand {
+	translated := make([]tgbotapi.BotCommand, 0, len(commands))
+	for _, command := range commands {
+		translated = append(translated, tgbotapi.BotCommand{Command: command.Command, Description: i18n.Translate(locale, command.Description)})
+	}
+	return translated
 }
 
 type StateMachine interface {
@@ -41,18 +70,17 @@ type StateMachine interface {
 type MessagesService struct {
 	cache        interfaces.HandlersCache
 	stateMachine StateMachine
+	reporter     *CrashReporter
 }
 
-func NewMessagesHandler(stateMachine StateMachine, cache interfaces.HandlersCache) *MessagesService {
-	tgbotapi.NewSetMyCommands(slices.Concat(userCommands, adminCommands)...)
-	return &MessagesService{cache: cache, stateMachine: stateMachine}
+func NewMessagesHandler(stateMachine StateMachine, cache interfaces.HandlersCache, reporter *CrashReporter) *MessagesService {
+	return &MessagesService{cache: cache, stateMachine: stateMachine, reporter: reporter}
 }
 
-func (srv *MessagesService) HandleMessages(update *tgbotapi.Update, bot *tgutils.Bot) {
+func (srv *MessagesService) HandleMessages(update *tgbotapi.Update, bot *tgutils.Bot) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			slog.Error("Recovered from panic", "err", r)
-			debug.PrintStack()
+			err = srv.reporter.ReportPanic(update, r, debug.Stack())
 		}
 	}()
 
@@ -65,8 +93,9 @@ func (srv *MessagesService) HandleMessages(update *tgbotapi.Update, bot *tgutils
 	defer mu.Unlock()
 	defer srv.cache.ReleaseLock(ctx, update.Message.Chat.ID, update.CallbackData())
 
-	err := srv.stateMachine.Handle(ctx, update.Message)
+	err = srv.stateMachine.Handle(ctx, update.Message)
 	if err != nil {
-		slog.Error(err.Error())
+		srv.reporter.Report(update, err)
 	}
+	return err
 }
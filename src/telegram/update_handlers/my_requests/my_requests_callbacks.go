@@ -0,0 +1,243 @@
+package myrequests
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+type UsersRepository interface {
+	GetByTgId(ctx context.Context, tgId int64) (*entities.User, error)
+}
+
+type LessonsRepository interface {
+	GetNext(ctx context.Context, subject string, groupId int64) ([]persistence.Lesson, error)
+}
+
+type withdrawCallback struct {
+	RequestId int64
+	Confirmed bool
+}
+
+type editCallback struct {
+	RequestId int64
+}
+
+type moveCallback struct {
+	RequestId int64
+	// Lesson to move the request to, lessons are offered first, if it's zero
+	LessonId int64
+}
+
+var (
+	WithdrawCodec = tgutils.NewCallbackCodec[withdrawCallback](constants.MY_REQUESTS_WITHDRAW_CALLBACKS, 1)
+	EditCodec     = tgutils.NewCallbackCodec[editCallback](constants.MY_REQUESTS_EDIT_CALLBACKS, 1)
+	NumberCodec   = tgutils.NewCallbackCodec[editCallback](constants.MY_REQUESTS_NUMBER_CALLBACKS, 1)
+	MoveCodec     = tgutils.NewCallbackCodec[moveCallback](constants.MY_REQUESTS_MOVE_CALLBACKS, 1)
+)
+
+type MyRequestsCallbackHandler struct {
+	cache    interfaces.HandlersCache
+	requests UserRequestsRepository
+	users    UsersRepository
+	lessons  LessonsRepository
+	editor   *RequestsEditor
+}
+
+func NewMyRequestsCallbackHandler(cache interfaces.HandlersCache, requests UserRequestsRepository, users UsersRepository,
+	lessons LessonsRepository, editor *RequestsEditor) *MyRequestsCallbackHandler {
+	return &MyRequestsCallbackHandler{cache: cache, requests: requests, users: users, lessons: lessons, editor: editor}
+}
+
+// HandleWithdraw asks for confirmation first, then removes the request from the queue and from the sheet of the group
+func (handler *MyRequestsCallbackHandler) HandleWithdraw(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot,
+	data *withdrawCallback) error {
+	requests, index, err := handler.findRequest(ctx, update, bot, data.RequestId)
+	if err != nil || index == -1 {
+		return err
+	}
+	request := requests[index]
+
+	if !data.Confirmed {
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.MY_REQUEST_CONFIRM_WITHDRAW_BUTTON),
+				WithdrawCodec.MustEncode(withdrawCallback{RequestId: request.Id, Confirmed: true})),
+			backButton(ctx),
+		))
+		return handler.showMenu(ctx, update, bot, i18n.T(ctx, i18n.MY_REQUEST_CONFIRM_WITHDRAW_TEXT, request.Subject,
+			request.LessonDateTime.Format(LESSON_DATE_FORMAT), request.LabworkNumber), keyboard)
+	}
+
+	user, err := handler.users.GetByTgId(ctx, update.SentFrom().ID)
+	if err != nil {
+		return fmt.Errorf("failed to get user during withdraw callback handling: %w", err)
+	}
+	err = handler.editor.Withdraw(ctx, user, request)
+	if err != nil {
+		return err
+	}
+	return handler.showRequests(ctx, update, bot, append(requests[:index:index], requests[index+1:]...),
+		i18n.T(ctx, i18n.MY_REQUEST_WITHDRAWN_TEXT))
+}
+
+// HandleEdit offers to change either the labwork number or the lesson of the request
+func (handler *MyRequestsCallbackHandler) HandleEdit(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot,
+	data *editCallback) error {
+	requests, index, err := handler.findRequest(ctx, update, bot, data.RequestId)
+	if err != nil || index == -1 {
+		return err
+	}
+	request := requests[index]
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.MY_REQUEST_CHANGE_NUMBER_BUTTON),
+			NumberCodec.MustEncode(editCallback{RequestId: request.Id}))),
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.MY_REQUEST_MOVE_BUTTON),
+			MoveCodec.MustEncode(moveCallback{RequestId: request.Id}))),
+		tgbotapi.NewInlineKeyboardRow(backButton(ctx)),
+	)
+	return handler.showMenu(ctx, update, bot, i18n.T(ctx, i18n.MY_REQUEST_EDIT_TEXT, request.Subject,
+		request.LessonDateTime.Format(LESSON_DATE_FORMAT), request.LabworkNumber), keyboard)
+}
+
+// HandleNumber waits for the new labwork number of the request in MY_REQUESTS_NUMBER_STATE
+func (handler *MyRequestsCallbackHandler) HandleNumber(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot,
+	data *editCallback) error {
+	_, index, err := handler.findRequest(ctx, update, bot, data.RequestId)
+	if err != nil || index == -1 {
+		return err
+	}
+	chatId := update.FromChat().ID
+	err = handler.cache.SaveInfo(ctx, chatId, fmt.Sprint(data.RequestId))
+	if err != nil {
+		return fmt.Errorf("failed to save request id during my requests number callback handling: %w", err)
+	}
+	err = handler.cache.SaveState(ctx, *interfaces.NewCachedInfo(chatId, constants.MY_REQUESTS_NUMBER_STATE))
+	if err != nil {
+		return fmt.Errorf("failed to save number state during my requests number callback handling: %w", err)
+	}
+	return handler.showMenu(ctx, update, bot, i18n.T(ctx, i18n.MY_REQUEST_ENTER_NUMBER_TEXT),
+		tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}})
+}
+
+// HandleMove offers upcoming lessons of the subject, then moves the request to the chosen one
+func (handler *MyRequestsCallbackHandler) HandleMove(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot,
+	data *moveCallback) error {
+	requests, index, err := handler.findRequest(ctx, update, bot, data.RequestId)
+	if err != nil || index == -1 {
+		return err
+	}
+	request := requests[index]
+	user, err := handler.users.GetByTgId(ctx, update.SentFrom().ID)
+	if err != nil {
+		return fmt.Errorf("failed to get user during my requests move callback handling: %w", err)
+	}
+	lessons, err := handler.lessons.GetNext(ctx, request.Subject, user.GroupId)
+	if err != nil {
+		return fmt.Errorf("failed to get next lessons during my requests move callback handling: %w", err)
+	}
+	lessons = slices.DeleteFunc(lessons, func(lesson persistence.Lesson) bool { return lesson.Id == request.LessonId })
+
+	if data.LessonId == 0 {
+		if len(lessons) == 0 {
+			_, err = bot.RequestCtx(ctx, tgbotapi.NewCallbackWithAlert(update.CallbackQuery.ID, i18n.T(ctx, i18n.MY_REQUEST_NO_OTHER_LESSONS_TEXT)))
+			if err != nil {
+				return fmt.Errorf("failed to answer my requests move callback without lessons: %w", err)
+			}
+			return nil
+		}
+		markup := [][]tgbotapi.InlineKeyboardButton{}
+		for _, lesson := range lessons {
+			markup = append(markup, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+				lesson.DateTime.Format(LESSON_DATE_FORMAT), MoveCodec.MustEncode(moveCallback{RequestId: request.Id, LessonId: lesson.Id}))))
+		}
+		markup = append(markup, tgbotapi.NewInlineKeyboardRow(backButton(ctx)))
+		return handler.showMenu(ctx, update, bot, i18n.T(ctx, i18n.MY_REQUEST_CHOOSE_LESSON_TEXT), tgbotapi.NewInlineKeyboardMarkup(markup...))
+	}
+
+	// Lessons could have passed or been deleted since the buttons were sent
+	lessonIndex := slices.IndexFunc(lessons, func(lesson persistence.Lesson) bool { return lesson.Id == data.LessonId })
+	if lessonIndex == -1 {
+		_, err = bot.RequestCtx(ctx, tgbotapi.NewCallbackWithAlert(update.CallbackQuery.ID, i18n.T(ctx, i18n.NO_LESSONS_TEXT)))
+		if err != nil {
+			return fmt.Errorf("failed to answer my requests move callback with unknown lesson: %w", err)
+		}
+		return nil
+	}
+	err = handler.editor.Update(ctx, user, request, lessons[lessonIndex], request.LabworkNumber)
+	if err != nil {
+		return err
+	}
+	requests, err = handler.requests.GetUserRequests(ctx, update.SentFrom().ID)
+	if err != nil {
+		return fmt.Errorf("failed to get requests of user after moving the request: %w", err)
+	}
+	return handler.showRequests(ctx, update, bot, requests, i18n.T(ctx, i18n.MY_REQUEST_UPDATED_TEXT))
+}
+
+// HandleBack returns the list of requests instead of confirmation
+func (handler *MyRequestsCallbackHandler) HandleBack(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) error {
+	requests, err := handler.requests.GetUserRequests(ctx, update.SentFrom().ID)
+	if err != nil {
+		return fmt.Errorf("failed to get requests of user during back callback handling: %w", err)
+	}
+	return handler.showRequests(ctx, update, bot, requests, "")
+}
+
+func (handler *MyRequestsCallbackHandler) showRequests(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot,
+	requests []entities.UserLessonRequest, answer string) error {
+	msg := update.CallbackQuery.Message
+	text, keyboard := renderRequests(ctx, requests)
+	if keyboard == nil {
+		keyboard = &tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}
+	}
+	_, err := bot.SendCtx(ctx, tgbotapi.NewEditMessageTextAndMarkup(msg.Chat.ID, msg.MessageID, text, *keyboard))
+	if err != nil {
+		return fmt.Errorf("failed to edit list of requests: %w", err)
+	}
+	_, err = bot.RequestCtx(ctx, tgbotapi.NewCallback(update.CallbackQuery.ID, answer))
+	if err != nil {
+		return fmt.Errorf("failed to answer callback of requests list: %w", err)
+	}
+	return nil
+}
+
+func (handler *MyRequestsCallbackHandler) showMenu(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot, text string,
+	keyboard tgbotapi.InlineKeyboardMarkup) error {
+	msg := update.CallbackQuery.Message
+	_, err := bot.SendCtx(ctx, tgbotapi.NewEditMessageTextAndMarkup(msg.Chat.ID, msg.MessageID, text, keyboard))
+	if err != nil {
+		return fmt.Errorf("failed to edit request menu: %w", err)
+	}
+	_, err = bot.RequestCtx(ctx, tgbotapi.NewCallback(update.CallbackQuery.ID, ""))
+	if err != nil {
+		return fmt.Errorf("failed to answer callback of request menu: %w", err)
+	}
+	return nil
+}
+
+// findRequest returns requests of the user and index of the requested one. If the request no longer exists,
+// e.g. it was declined or withdrawn in another message, the list is shown again and index is -1
+func (handler *MyRequestsCallbackHandler) findRequest(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot,
+	requestId int64) ([]entities.UserLessonRequest, int, error) {
+	requests, err := handler.requests.GetUserRequests(ctx, update.SentFrom().ID)
+	if err != nil {
+		return nil, -1, fmt.Errorf("failed to get requests of user during my requests callback handling: %w", err)
+	}
+	index := slices.IndexFunc(requests, func(request entities.UserLessonRequest) bool { return request.Id == requestId })
+	if index == -1 {
+		return requests, -1, handler.showRequests(ctx, update, bot, requests, i18n.T(ctx, i18n.MY_REQUEST_NOT_FOUND_TEXT))
+	}
+	return requests, index, nil
+}
+
+func backButton(ctx context.Context) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.BACK_BUTTON), constants.MY_REQUESTS_BACK_CALLBACKS)
+}
@@ -0,0 +1,106 @@
+package myrequests
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// MyRequestsNumberState changes labwork number of the request, which id is saved into info by the number callback
+type MyRequestsNumberState struct {
+	bot      *tgutils.Bot
+	cache    interfaces.HandlersCache
+	requests UserRequestsRepository
+	users    UsersRepository
+	editor   *RequestsEditor
+}
+
+func NewMyRequestsNumberState(bot *tgutils.Bot, cache interfaces.HandlersCache, requests UserRequestsRepository, users UsersRepository,
+	editor *RequestsEditor) *MyRequestsNumberState {
+	return &MyRequestsNumberState{bot: bot, cache: cache, requests: requests, users: users, editor: editor}
+}
+
+func (state *MyRequestsNumberState) Handle(ctx context.Context, msg *tgbotapi.Message) error {
+	number, err := strconv.ParseInt(msg.Text, 10, 8)
+	if err != nil || number <= 0 {
+		_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, i18n.T(ctx, i18n.INVALID_LABWORK_NUMBER_TEXT)))
+		if err != nil {
+			return fmt.Errorf("failed to send invalid number message during my requests number state: %w", err)
+		}
+		return nil
+	}
+	info, err := state.cache.GetInfo(ctx, msg.Chat.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get info during my requests number state: %w", err)
+	}
+	requestId, err := strconv.ParseInt(info, 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse request id during my requests number state: %w", err)
+	}
+	requests, err := state.requests.GetUserRequests(ctx, msg.From.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get requests of user during my requests number state: %w", err)
+	}
+
+	// Request could have been declined, while the user was entering the number
+	answer := i18n.T(ctx, i18n.MY_REQUEST_NOT_FOUND_TEXT)
+	index := slices.IndexFunc(requests, func(request entities.UserLessonRequest) bool { return request.Id == requestId })
+	if index != -1 {
+		request := requests[index]
+		user, err := state.users.GetByTgId(ctx, msg.From.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get user during my requests number state: %w", err)
+		}
+		lesson := persistence.Lesson{Id: request.LessonId, Subject: request.Subject, SubgroupNumber: request.SubgroupNumber,
+			DateTime: request.LessonDateTime}
+		err = state.editor.Update(ctx, user, request, lesson, int8(number))
+		if err != nil {
+			return err
+		}
+		answer = i18n.T(ctx, i18n.MY_REQUEST_UPDATED_TEXT)
+	}
+	return state.finish(ctx, msg, answer)
+}
+
+// Revert returns the user to the list of requests
+func (state *MyRequestsNumberState) Revert(ctx context.Context, msg *tgbotapi.Message) error {
+	return state.finish(ctx, msg, "")
+}
+
+func (state *MyRequestsNumberState) finish(ctx context.Context, msg *tgbotapi.Message, answer string) error {
+	err := state.cache.RemoveInfo(ctx, msg.Chat.ID)
+	if err != nil {
+		return fmt.Errorf("failed to remove info during my requests number state: %w", err)
+	}
+	err = state.cache.SaveState(ctx, *interfaces.NewCachedInfo(msg.Chat.ID, constants.IDLE_STATE))
+	if err != nil {
+		return fmt.Errorf("failed to save idle state during my requests number state: %w", err)
+	}
+	// Positions in the queue could change after the update, so the list is read anew
+	requests, err := state.requests.GetUserRequests(ctx, msg.From.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get requests of user during my requests number state: %w", err)
+	}
+	text, keyboard := renderRequests(ctx, requests)
+	if answer != "" {
+		text = answer + "\n\n" + text
+	}
+	resp := tgbotapi.NewMessage(msg.Chat.ID, text)
+	if keyboard != nil {
+		resp.ReplyMarkup = *keyboard
+	}
+	_, err = state.bot.SendCtx(ctx, resp)
+	if err != nil {
+		return fmt.Errorf("failed to send requests of user during my requests number state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package myrequests
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const LESSON_DATE_FORMAT = "02.01.2006 15:04"
+
+type UserRequestsRepository interface {
+	GetUserRequests(ctx context.Context, userTgId int64) ([]entities.UserLessonRequest, error)
+}
+
+// MyRequestsStartState lists requests of the user, the flow ends right away, since buttons of the list work from idle state
+type MyRequestsStartState struct {
+	bot      *tgutils.Bot
+	cache    interfaces.HandlersCache
+	requests UserRequestsRepository
+}
+
+func NewMyRequestsStartState(bot *tgutils.Bot, cache interfaces.HandlersCache, requests UserRequestsRepository) *MyRequestsStartState {
+	return &MyRequestsStartState{bot: bot, cache: cache, requests: requests}
+}
+
+func (state *MyRequestsStartState) Handle(ctx context.Context, msg *tgbotapi.Message) error {
+	err := state.cache.SaveState(ctx, *interfaces.NewCachedInfo(msg.Chat.ID, constants.IDLE_STATE))
+	if err != nil {
+		return fmt.Errorf("failed to save idle state during my requests command handling: %w", err)
+	}
+	requests, err := state.requests.GetUserRequests(ctx, msg.From.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get requests of user during my requests command handling: %w", err)
+	}
+	text, keyboard := renderRequests(ctx, requests)
+	resp := tgbotapi.NewMessage(msg.Chat.ID, text)
+	if keyboard != nil {
+		resp.ReplyMarkup = *keyboard
+	}
+	_, err = state.bot.SendCtx(ctx, resp)
+	if err != nil {
+		return fmt.Errorf("failed to send requests of user during my requests command handling: %w", err)
+	}
+	return nil
+}
+
+func (state *MyRequestsStartState) Revert(ctx context.Context, msg *tgbotapi.Message) error {
+	return nil
+}
+
+// renderRequests returns the list of requests and buttons for editing and withdrawing them, keyboard is nil, if there are no requests
+func renderRequests(ctx context.Context, requests []entities.UserLessonRequest) (string, *tgbotapi.InlineKeyboardMarkup) {
+	if len(requests) == 0 {
+		return i18n.T(ctx, i18n.MY_REQUESTS_EMPTY_TEXT), nil
+	}
+	var builder strings.Builder
+	builder.WriteString(i18n.T(ctx, i18n.MY_REQUESTS_TITLE))
+	for i, request := range requests {
+		date := request.LessonDateTime.Format(LESSON_DATE_FORMAT)
+		if request.IsPending {
+			builder.WriteString(i18n.T(ctx, i18n.MY_REQUEST_PENDING_LINE, i+1, request.Subject, date, request.LabworkNumber))
+			continue
+		}
+		builder.WriteString(i18n.T(ctx, i18n.MY_REQUEST_LINE, i+1, request.Subject, date, request.LabworkNumber, request.Position))
+	}
+
+	markup := make([][]tgbotapi.InlineKeyboardButton, 0, len(requests))
+	for i, request := range requests {
+		markup = append(markup, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.MY_REQUEST_EDIT_BUTTON, i+1),
+				EditCodec.MustEncode(editCallback{RequestId: request.Id})),
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.MY_REQUEST_WITHDRAW_BUTTON, i+1),
+				WithdrawCodec.MustEncode(withdrawCallback{RequestId: request.Id})),
+		))
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(markup...)
+	return builder.String(), &keyboard
+}
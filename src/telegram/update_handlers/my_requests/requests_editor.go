@@ -0,0 +1,120 @@
+package myrequests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/labworks"
+	datetime "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/date_time"
+)
+
+type SheetsService interface {
+	AddLabworkRequest(context.Context, *labworks.AppendedLabwork) error
+	DeleteLabworkRequest(context.Context, *labworks.AppendedLabwork) error
+}
+
+// QueueRefresher edits messages, which are subscribed to the queue of the lesson
+type QueueRefresher interface {
+	Refresh(lessonId int64)
+}
+
+// TurnNotifier tells the students, waiting in the queue of the lesson, their changed places
+type TurnNotifier interface {
+	Notify(ctx context.Context, lessonId int64)
+}
+
+type EditableRequestsRepository interface {
+	Delete(ctx context.Context, requestId int64) error
+	UpdateRequest(ctx context.Context, requestId, lessonId int64, labworkNumber int8) error
+}
+
+// RequestsEditor changes requests of the user, keeping the sheet of the group and live queues in sync.
+// Only accepted requests are in the sheet and in the queue, pending ones are changed in the database only
+type RequestsEditor struct {
+	requests EditableRequestsRepository
+	sheets   SheetsService
+	queues   QueueRefresher
+	turns    TurnNotifier
+}
+
+func NewRequestsEditor(requests EditableRequestsRepository, sheets SheetsService, queues QueueRefresher, turns TurnNotifier) *RequestsEditor {
+	return &RequestsEditor{requests: requests, sheets: sheets, queues: queues, turns: turns}
+}
+
+// Withdraw deletes the row of the request first, so that withdrawal can be retried, if google fails
+func (editor *RequestsEditor) Withdraw(ctx context.Context, user *entities.User, request entities.UserLessonRequest) error {
+	if !request.IsPending {
+		err := editor.sheets.DeleteLabworkRequest(ctx, appendedLabwork(user, request))
+		if err != nil {
+			return fmt.Errorf("failed to delete labwork request from sheets during its withdrawal: %w", err)
+		}
+	}
+	err := editor.requests.Delete(ctx, request.Id)
+	if err != nil {
+		return fmt.Errorf("failed to delete labwork request during its withdrawal: %w", err)
+	}
+	if !request.IsPending {
+		editor.queues.Refresh(request.LessonId)
+		editor.turns.Notify(ctx, request.LessonId)
+	}
+	return nil
+}
+
+// Update moves the request to the lesson of the same subject and sets its labwork number. Row of accepted request
+// is appended anew, since sheets of the old and the new lesson can differ. The database is updated first, and changes
+// are reverted, if google fails, so that the database and the sheet agree and the update can be retried
+func (editor *RequestsEditor) Update(ctx context.Context, user *entities.User, request entities.UserLessonRequest,
+	lesson persistence.Lesson, labworkNumber int8) error {
+	updated := request
+	updated.LessonId, updated.LessonDateTime, updated.SubgroupNumber = lesson.Id, lesson.DateTime, lesson.SubgroupNumber
+	updated.LabworkNumber = labworkNumber
+	err := editor.requests.UpdateRequest(ctx, request.Id, lesson.Id, labworkNumber)
+	if err != nil {
+		return fmt.Errorf("failed to update labwork request: %w", err)
+	}
+	if !request.IsPending {
+		err = editor.sheets.AddLabworkRequest(ctx, appendedLabwork(user, updated))
+		if err != nil {
+			err = fmt.Errorf("failed to append new row of labwork request during its update: %w", err)
+			return errors.Join(err, editor.revert(ctx, request))
+		}
+		err = editor.sheets.DeleteLabworkRequest(ctx, appendedLabwork(user, request))
+		if err != nil {
+			err = fmt.Errorf("failed to delete old row of labwork request during its update: %w", err)
+			return errors.Join(err, editor.sheets.DeleteLabworkRequest(ctx, appendedLabwork(user, updated)), editor.revert(ctx, request))
+		}
+	}
+	if !request.IsPending {
+		editor.queues.Refresh(request.LessonId)
+		editor.turns.Notify(ctx, request.LessonId)
+		if lesson.Id != request.LessonId {
+			editor.queues.Refresh(lesson.Id)
+			editor.turns.Notify(ctx, lesson.Id)
+		}
+	}
+	return nil
+}
+
+// revert returns the request to its lesson and labwork number before the update
+func (editor *RequestsEditor) revert(ctx context.Context, request entities.UserLessonRequest) error {
+	err := editor.requests.UpdateRequest(ctx, request.Id, request.LessonId, request.LabworkNumber)
+	if err != nil {
+		return fmt.Errorf("failed to revert update of labwork request: %w", err)
+	}
+	return nil
+}
+
+func appendedLabwork(user *entities.User, request entities.UserLessonRequest) *labworks.AppendedLabwork {
+	return &labworks.AppendedLabwork{
+		RequestedDate:  datetime.DateOnly(request.LessonDateTime),
+		SentProofTime:  datetime.DateTime(request.SubmitTime),
+		DisciplineName: request.Subject,
+		GroupName:      user.GroupName,
+		FullName:       user.FullName,
+		SubgroupNumber: request.SubgroupNumber,
+		LabworkNumber:  request.LabworkNumber,
+	}
+}
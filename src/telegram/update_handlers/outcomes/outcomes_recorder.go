@@ -0,0 +1,112 @@
+package outcomes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	iisEntities "github.com/aCrYoZPS/bsuir_queue_bot/src/iis_api/entities"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+)
+
+type OutcomesRepository interface {
+	Save(ctx context.Context, outcome *entities.LabworkOutcome) (int64, error)
+	Get(ctx context.Context, id int64) (*entities.LabworkOutcome, error)
+	AddIfAbsent(ctx context.Context, outcome *entities.LabworkOutcome) (bool, error)
+	SetGrade(ctx context.Context, id int64, grade int8, comment string) error
+	GetGroupOutcomes(ctx context.Context, groupId int64) ([]entities.LabworkOutcome, error)
+}
+
+// Google limits writes to about 60 per minute, while admins mark outcomes one after another during the lesson
+const DEFAULT_REFRESH_INTERVAL = 10 * time.Second
+
+type GroupsRepository interface {
+	GetById(ctx context.Context, id int) (*iisEntities.Group, error)
+}
+
+type SheetsService interface {
+	WriteOutcomes(ctx context.Context, groupName string, outcomes []entities.LabworkOutcome) error
+}
+
+// OutcomesRecorder keeps the history of outcomes of labworks together with its sheet. The database is the source of the history,
+// so the sheet is rewritten from it in the background after changes, and failures of google are only logged.
+// Rewrites of the same group are throttled, so that changes, made during the interval, are written at once
+type OutcomesRecorder struct {
+	outcomes OutcomesRepository
+	groups   GroupsRepository
+	sheets   SheetsService
+	throttle *tgutils.Throttle
+}
+
+func NewOutcomesRecorder(outcomes OutcomesRepository, groups GroupsRepository, sheets SheetsService, interval time.Duration) *OutcomesRecorder {
+	return &OutcomesRecorder{outcomes: outcomes, groups: groups, sheets: sheets, throttle: tgutils.NewThrottle(interval)}
+}
+
+// Record saves the outcome, marked now, and returns its id
+func (recorder *OutcomesRecorder) Record(ctx context.Context, outcome *entities.LabworkOutcome) (int64, error) {
+	outcome.MarkedAt = time.Now()
+	id, err := recorder.outcomes.Save(ctx, outcome)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record outcome of labwork: %w", err)
+	}
+	recorder.refresh(ctx, outcome.GroupId)
+	return id, nil
+}
+
+// RecordIfAbsent records the outcome, marked now, unless the request was already marked during the lesson, e.g. by admin in lesson mode
+func (recorder *OutcomesRecorder) RecordIfAbsent(ctx context.Context, outcome *entities.LabworkOutcome) error {
+	outcome.MarkedAt = time.Now()
+	added, err := recorder.outcomes.AddIfAbsent(ctx, outcome)
+	if err != nil {
+		return fmt.Errorf("failed to record outcome of labwork: %w", err)
+	}
+	if added {
+		recorder.refresh(ctx, outcome.GroupId)
+	}
+	return nil
+}
+
+// Get returns nil, if there is no such outcome
+func (recorder *OutcomesRecorder) Get(ctx context.Context, id int64) (*entities.LabworkOutcome, error) {
+	return recorder.outcomes.Get(ctx, id)
+}
+
+func (recorder *OutcomesRecorder) SetGrade(ctx context.Context, id int64, grade int8, comment string) error {
+	outcome, err := recorder.outcomes.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get outcome to grade: %w", err)
+	}
+	if outcome == nil {
+		return fmt.Errorf("no outcome %d found to grade", id)
+	}
+	err = recorder.outcomes.SetGrade(ctx, id, grade, comment)
+	if err != nil {
+		return err
+	}
+	recorder.refresh(ctx, outcome.GroupId)
+	return nil
+}
+
+func (recorder *OutcomesRecorder) refresh(ctx context.Context, groupId int64) {
+	ctx = context.WithoutCancel(ctx)
+	recorder.throttle.Do(groupId, func() {
+		err := recorder.writeSheet(ctx, groupId)
+		if err != nil {
+			slog.Error(fmt.Errorf("failed to write outcomes of group %d to sheets: %w", groupId, err).Error())
+		}
+	})
+}
+
+func (recorder *OutcomesRecorder) writeSheet(ctx context.Context, groupId int64) error {
+	group, err := recorder.groups.GetById(ctx, int(groupId))
+	if err != nil {
+		return fmt.Errorf("failed to get group: %w", err)
+	}
+	outcomes, err := recorder.outcomes.GetGroupOutcomes(ctx, groupId)
+	if err != nil {
+		return err
+	}
+	return recorder.sheets.WriteOutcomes(ctx, group.Name, outcomes)
+}
@@ -0,0 +1,86 @@
+package outcomes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const MARKED_DATE_FORMAT = "02.01.2006"
+
+var outcomeKeys = map[entities.Outcome]i18n.Key{
+	entities.Passed: i18n.OUTCOME_PASSED,
+	entities.Failed: i18n.OUTCOME_FAILED,
+	entities.Absent: i18n.OUTCOME_ABSENT,
+}
+
+type UserOutcomesRepository interface {
+	GetUserOutcomes(ctx context.Context, tgId int64) ([]entities.LabworkOutcome, error)
+}
+
+// ResultsStartState shows the history of outcomes of the user, the flow ends right away
+type ResultsStartState struct {
+	bot      *tgutils.Bot
+	cache    interfaces.HandlersCache
+	outcomes UserOutcomesRepository
+}
+
+func NewResultsStartState(bot *tgutils.Bot, cache interfaces.HandlersCache, outcomes UserOutcomesRepository) *ResultsStartState {
+	return &ResultsStartState{bot: bot, cache: cache, outcomes: outcomes}
+}
+
+func (state *ResultsStartState) Handle(ctx context.Context, msg *tgbotapi.Message) error {
+	err := state.cache.SaveState(ctx, *interfaces.NewCachedInfo(msg.Chat.ID, constants.IDLE_STATE))
+	if err != nil {
+		return fmt.Errorf("failed to save idle state during results command handling: %w", err)
+	}
+	outcomes, err := state.outcomes.GetUserOutcomes(ctx, msg.From.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get outcomes during results command handling: %w", err)
+	}
+	_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, renderResults(ctx, outcomes)))
+	if err != nil {
+		return fmt.Errorf("failed to send results during results command handling: %w", err)
+	}
+	return nil
+}
+
+func (state *ResultsStartState) Revert(ctx context.Context, msg *tgbotapi.Message) error {
+	return nil
+}
+
+// renderResults lists outcomes by subjects, outcomes are expected to be sorted by subjects
+func renderResults(ctx context.Context, outcomes []entities.LabworkOutcome) string {
+	if len(outcomes) == 0 {
+		return i18n.T(ctx, i18n.RESULTS_EMPTY_TEXT)
+	}
+	var builder strings.Builder
+	builder.WriteString(i18n.T(ctx, i18n.RESULTS_TITLE))
+	for i, outcome := range outcomes {
+		if i == 0 || outcomes[i-1].Subject != outcome.Subject {
+			builder.WriteString(i18n.T(ctx, i18n.RESULTS_SUBJECT, outcome.Subject))
+		}
+		builder.WriteString(i18n.T(ctx, i18n.RESULTS_LINE, outcome.LabworkNumber, i18n.T(ctx, outcomeKeys[outcome.Outcome])))
+		if outcome.Grade != 0 {
+			builder.WriteString(i18n.T(ctx, i18n.RESULTS_GRADE, outcome.Grade))
+		}
+		if outcome.Comment != "" {
+			builder.WriteString(i18n.T(ctx, i18n.RESULTS_COMMENT, outcome.Comment))
+		}
+		// Outcomes, confirmed by students themselves in reminders, have no one else to show
+		date := outcome.MarkedAt.Local().Format(MARKED_DATE_FORMAT)
+		if outcome.MarkedByName == "" || outcome.MarkedBy == outcome.UserId {
+			builder.WriteString(i18n.T(ctx, i18n.RESULTS_MARKED_AT, date))
+		} else {
+			builder.WriteString(i18n.T(ctx, i18n.RESULTS_MARKED_BY, outcome.MarkedByName, date))
+		}
+	}
+	return builder.String()
+}
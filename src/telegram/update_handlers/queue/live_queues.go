@@ -0,0 +1,212 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// Telegram allows about 20 edits of messages per minute in a group
+	DEFAULT_REFRESH_INTERVAL = 10 * time.Second
+	REFRESH_TIMEOUT          = 30 * time.Second
+	UPDATED_TIME_FORMAT      = "15:04"
+)
+
+type QueueSubscriptionsRepository interface {
+	Add(ctx context.Context, subscription *entities.QueueSubscription) error
+	Get(ctx context.Context, chatId int64, msgId int) (*entities.QueueSubscription, error)
+	GetByLesson(ctx context.Context, lessonId int64) ([]entities.QueueSubscription, error)
+	UpdateText(ctx context.Context, chatId int64, msgId int, text string) error
+	Delete(ctx context.Context, chatId int64, msgId int) error
+}
+
+type NextLessonsRepository interface {
+	GetNext(ctx context.Context, subject string, groupId int64) ([]persistence.Lesson, error)
+}
+
+// LiveQueues keeps subscribed queue messages up to date. Refreshes of the same lesson are throttled,
+// so that frequent changes of the queue don't exceed limits of telegram on edits
+type LiveQueues struct {
+	bot           *tgutils.Bot
+	subscriptions QueueSubscriptionsRepository
+	requests      LabworksRequest
+	lessons       NextLessonsRepository
+	throttle      *tgutils.Throttle
+}
+
+func NewLiveQueues(bot *tgutils.Bot, subscriptions QueueSubscriptionsRepository, requests LabworksRequest, lessons NextLessonsRepository,
+	interval time.Duration) *LiveQueues {
+	return &LiveQueues{
+		bot:           bot,
+		subscriptions: subscriptions,
+		requests:      requests,
+		lessons:       lessons,
+		throttle:      tgutils.NewThrottle(interval),
+	}
+}
+
+// Refresh edits subscribed messages of the lesson. Errors are only logged, since the change of the queue has already happened
+func (queues *LiveQueues) Refresh(lessonId int64) {
+	queues.throttle.Do(lessonId, func() { queues.refresh(lessonId) })
+}
+
+// RefreshSubject refreshes upcoming lessons of the subject, e.g. after all of them were reordered
+func (queues *LiveQueues) RefreshSubject(ctx context.Context, subject string, groupId int64) {
+	lessons, err := queues.lessons.GetNext(ctx, subject, groupId)
+	if err != nil {
+		slog.Error(fmt.Errorf("failed to get lessons of %s to refresh their queues: %w", subject, err).Error())
+		return
+	}
+	for _, lesson := range lessons {
+		queues.Refresh(lesson.Id)
+	}
+}
+
+// Delayed refreshes outlive the handler, which requested them, so they use their own context
+func (queues *LiveQueues) refresh(lessonId int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), REFRESH_TIMEOUT)
+	defer cancel()
+	subscriptions, err := queues.subscriptions.GetByLesson(ctx, lessonId)
+	if err != nil {
+		slog.Error(fmt.Errorf("failed to get subscriptions of lesson %d: %w", lessonId, err).Error())
+		return
+	}
+	if len(subscriptions) == 0 {
+		return
+	}
+	users, err := queues.requests.GetLabworkQueue(ctx, lessonId)
+	if err != nil {
+		slog.Error(fmt.Errorf("failed to get queue of lesson %d to refresh it: %w", lessonId, err).Error())
+		return
+	}
+	for _, subscription := range subscriptions {
+		err := queues.edit(queues.bot.Localize(ctx, subscription.ChatId, ""), subscription, users)
+		if err != nil {
+			slog.Error(fmt.Errorf("failed to refresh queue message %d in chat %d: %w", subscription.MsgId, subscription.ChatId, err).Error())
+		}
+	}
+}
+
+func (queues *LiveQueues) edit(ctx context.Context, subscription entities.QueueSubscription, users []entities.User) error {
+	text := renderQueue(ctx, users)
+	if text == subscription.Text {
+		return nil
+	}
+	_, err := queues.bot.SendCtx(ctx, tgbotapi.NewEditMessageTextAndMarkup(subscription.ChatId, subscription.MsgId,
+		text+i18n.T(ctx, i18n.QUEUE_UPDATED_TEXT, time.Now().Format(UPDATED_TIME_FORMAT)), unsubscribeKeyboard(ctx)))
+	if isMessageGone(err) {
+		return queues.subscriptions.Delete(ctx, subscription.ChatId, subscription.MsgId)
+	}
+	if err != nil {
+		return err
+	}
+	return queues.subscriptions.UpdateText(ctx, subscription.ChatId, subscription.MsgId, text)
+}
+
+// Subscribe makes the message live, optionally pinning it. Returns false, if the message couldn't be pinned,
+// e.g. bot isn't allowed to pin messages in the group
+func (queues *LiveQueues) Subscribe(ctx context.Context, msg *tgbotapi.Message, lessonId int64, pin bool) (bool, error) {
+	users, err := queues.requests.GetLabworkQueue(ctx, lessonId)
+	if err != nil {
+		return false, fmt.Errorf("failed to get queue of lesson %d during subscription: %w", lessonId, err)
+	}
+	text := renderQueue(ctx, users)
+	_, err = queues.bot.SendCtx(ctx, tgbotapi.NewEditMessageTextAndMarkup(msg.Chat.ID, msg.MessageID,
+		text+i18n.T(ctx, i18n.QUEUE_UPDATED_TEXT, time.Now().Format(UPDATED_TIME_FORMAT)), unsubscribeKeyboard(ctx)))
+	if err != nil {
+		return false, fmt.Errorf("failed to edit queue message during subscription: %w", err)
+	}
+	pinned := false
+	if pin {
+		_, err = queues.bot.RequestCtx(ctx, tgbotapi.PinChatMessageConfig{ChatID: msg.Chat.ID, MessageID: msg.MessageID, DisableNotification: true})
+		if err != nil {
+			slog.Error(fmt.Errorf("failed to pin queue message %d in chat %d: %w", msg.MessageID, msg.Chat.ID, err).Error())
+		}
+		pinned = err == nil
+	}
+	err = queues.subscriptions.Add(ctx, &entities.QueueSubscription{ChatId: msg.Chat.ID, MsgId: msg.MessageID, LessonId: lessonId,
+		Pinned: pinned, Text: text})
+	if err != nil {
+		return false, err
+	}
+	return pinned || !pin, nil
+}
+
+// Unsubscribe stops refreshes of the message and unpins it, returning buttons for subscribing again
+func (queues *LiveQueues) Unsubscribe(ctx context.Context, msg *tgbotapi.Message) error {
+	subscription, err := queues.subscriptions.Get(ctx, msg.Chat.ID, msg.MessageID)
+	if err != nil {
+		return err
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup([]tgbotapi.InlineKeyboardButton{})
+	if subscription != nil {
+		if subscription.Pinned {
+			_, err = queues.bot.RequestCtx(ctx, tgbotapi.UnpinChatMessageConfig{ChatID: msg.Chat.ID, MessageID: msg.MessageID})
+			if err != nil {
+				slog.Error(fmt.Errorf("failed to unpin queue message %d in chat %d: %w", msg.MessageID, msg.Chat.ID, err).Error())
+			}
+		}
+		err = queues.subscriptions.Delete(ctx, msg.Chat.ID, msg.MessageID)
+		if err != nil {
+			return err
+		}
+		keyboard = subscribeKeyboard(ctx, subscription.LessonId)
+	}
+	_, err = queues.bot.SendCtx(ctx, tgbotapi.NewEditMessageReplyMarkup(msg.Chat.ID, msg.MessageID, keyboard))
+	if err != nil {
+		return fmt.Errorf("failed to return subscribe buttons during unsubscription: %w", err)
+	}
+	return nil
+}
+
+// isMessageGone reports, whether the subscribed message was deleted or became too old to be edited
+func isMessageGone(err error) bool {
+	var tgErr *tgbotapi.Error
+	if !errors.As(err, &tgErr) {
+		return false
+	}
+	return strings.Contains(tgErr.Message, "message to edit not found") || strings.Contains(tgErr.Message, "message can't be edited")
+}
+
+func renderQueue(ctx context.Context, users []entities.User) string {
+	var output strings.Builder
+	for i, user := range users {
+		fmt.Fprintf(&output, "%d %s\n", i+1, user.FullName)
+	}
+	if output.String() == "" {
+		output.WriteString(i18n.T(ctx, i18n.QUEUE_EMPTY_TEXT))
+	}
+	return output.String()
+}
+
+type queueSubscribeCallback struct {
+	LessonId int64
+	Pin      bool
+}
+
+var queueSubscribeCodec = tgutils.NewCallbackCodec[queueSubscribeCallback](constants.QUEUE_SUBSCRIBE_CALLBACKS, 1)
+
+func subscribeKeyboard(ctx context.Context, lessonId int64) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.QUEUE_SUBSCRIBE_BUTTON),
+			queueSubscribeCodec.MustEncode(queueSubscribeCallback{LessonId: lessonId})),
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.QUEUE_SUBSCRIBE_PIN_BUTTON),
+			queueSubscribeCodec.MustEncode(queueSubscribeCallback{LessonId: lessonId, Pin: true})),
+	))
+}
+
+func unsubscribeKeyboard(ctx context.Context) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.QUEUE_UNSUBSCRIBE_BUTTON), constants.QUEUE_UNSUBSCRIBE_CALLBACKS)))
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	iis_api_entities "github.com/aCrYoZPS/bsuir_queue_bot/src/iis_api/entities"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
@@ -16,8 +17,6 @@ import (
 	customErrors "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/errors"
 	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 )
 
 type LabworksRequest interface {
@@ -30,41 +29,51 @@ type QueueCallbacksHandler struct {
 	cache    interfaces.HandlersCache
 	bot      *tgutils.Bot
 	requests LabworksRequest
+	payloads *tgutils.CallbackPayloads
+	queues   *LiveQueues
 }
 
 func NewQueueCallbackHandler(users interfaces.UsersRepository, labworks interfaces.LessonsRepository, cache interfaces.HandlersCache, 
-	bot *tgutils.Bot, requests LabworksRequest) *QueueCallbacksHandler {
+	bot *tgutils.Bot, requests LabworksRequest, payloads *tgutils.CallbackPayloads, queues *LiveQueues) *QueueCallbacksHandler {
 	return &QueueCallbacksHandler{
 		users:    users,
 		labworks: labworks,
 		cache:    cache,
 		bot:      bot,
 		requests: requests,
+		payloads: payloads,
+		queues:   queues,
 	}
 }
 
 func (handler *QueueCallbacksHandler) HandleCallback(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) error {
 	if strings.HasPrefix(update.CallbackData(), constants.QUEUE_DISCIPLINE_CALLBACKS) {
-		userTgId, discipline := parseQueueDisciplineCallback(update.CallbackData())
-		if discipline == "" || userTgId == 0 {
-			return errors.New("invalid command requested")
+		payload, err := parseQueueDisciplineCallback(ctx, handler.payloads, update.CallbackData())
+		if err != nil {
+			return err
 		}
-		err := handler.handleDisciplineCallback(ctx, update.CallbackQuery.Message, discipline)
+		err = handler.handleDisciplineCallback(ctx, update.CallbackQuery.Message, payload.UserTgId, payload.Discipline)
 		if err != nil {
 			if errors.Is(err, customErrors.ErrNoLabworks) {
 				err := handler.cache.SaveState(ctx, *interfaces.NewCachedInfo(update.CallbackQuery.Message.Chat.ID, constants.IDLE_STATE))
 				if err != nil {
 					return fmt.Errorf("failed to transition to idle state during labwork callback handling: %w", err)
 				}
-				_, err = bot.SendCtx(ctx, tgbotapi.NewMessage(update.CallbackQuery.Message.Chat.ID, 
-					cases.Title(language.English).String(customErrors.ErrNoLabworks.Error())))
-				return fmt.Errorf("failed to send no lessons error to user during labwork callback handling: %w", err)
+				_, err = bot.SendCtx(ctx, tgbotapi.NewMessage(update.CallbackQuery.Message.Chat.ID, i18n.T(ctx, i18n.NO_LABWORKS_LEFT_TEXT)))
+				if err != nil {
+					return fmt.Errorf("failed to send no lessons error to user during labwork callback handling: %w", err)
+				}
+				return nil
 			}
 			return err
 		}
 		return nil
-	} else if strings.HasPrefix(update.CallbackData(), constants.QUEUE_TIME_CALLBACKS) {
-		err := handler.handleTimeCallback(ctx, update)
+	} else if queueTimeCodec.Matches(update.CallbackData()) {
+		data, err := queueTimeCodec.Decode(update.CallbackData())
+		if err != nil {
+			return err
+		}
+		err = handler.handleTimeCallback(ctx, update, data.LessonId)
 		if err != nil {
 			return err
 		}
@@ -73,6 +82,40 @@ func (handler *QueueCallbacksHandler) HandleCallback(ctx context.Context, update
 		if err != nil {
 			return err
 		}
+	} else if queueSubscribeCodec.Matches(update.CallbackData()) {
+		data, err := queueSubscribeCodec.Decode(update.CallbackData())
+		if err != nil {
+			return err
+		}
+		err = handler.handleSubscribeCallback(ctx, update, data)
+		if err != nil {
+			return err
+		}
+	} else if strings.HasPrefix(update.CallbackData(), constants.QUEUE_UNSUBSCRIBE_CALLBACKS) {
+		err := handler.queues.Unsubscribe(ctx, update.CallbackQuery.Message)
+		if err != nil {
+			return fmt.Errorf("failed to unsubscribe from queue: %w", err)
+		}
+		_, err = handler.bot.RequestCtx(ctx, tgbotapi.NewCallback(update.CallbackQuery.ID, i18n.T(ctx, i18n.QUEUE_UNSUBSCRIBED_TEXT)))
+		if err != nil {
+			return fmt.Errorf("failed to answer queue unsubscribe callback: %w", err)
+		}
+	}
+	return nil
+}
+
+func (handler *QueueCallbacksHandler) handleSubscribeCallback(ctx context.Context, update *tgbotapi.Update, data *queueSubscribeCallback) error {
+	pinned, err := handler.queues.Subscribe(ctx, update.CallbackQuery.Message, data.LessonId, data.Pin)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to queue: %w", err)
+	}
+	answer := i18n.T(ctx, i18n.QUEUE_SUBSCRIBED_TEXT)
+	if !pinned {
+		answer = i18n.T(ctx, i18n.QUEUE_PIN_FAILED_TEXT)
+	}
+	_, err = handler.bot.RequestCtx(ctx, tgbotapi.NewCallback(update.CallbackQuery.ID, answer))
+	if err != nil {
+		return fmt.Errorf("failed to answer queue subscribe callback: %w", err)
 	}
 	return nil
 }
@@ -94,16 +137,20 @@ func (handler *QueueCallbacksHandler) handleCancelCallback(ctx context.Context,
 	if err != nil {
 		return fmt.Errorf("failed to get subjects during queue cancel callback handling: %w", err)
 	}
-	_, err = handler.bot.SendCtx(ctx,
-		 tgbotapi.NewEditMessageReplyMarkup(update.FromChat().ID, msgId, *createLabworksKeyboard(usr.TgId, subjects)))
+	keyboard, err := createLabworksKeyboard(ctx, handler.payloads, usr.TgId, subjects)
+	if err != nil {
+		return fmt.Errorf("failed to create labworks keyboard during queue cancel callback handling: %w", err)
+	}
+	_, err = handler.bot.SendCtx(ctx, tgbotapi.NewEditMessageReplyMarkup(update.FromChat().ID, msgId, *keyboard))
 	if err != nil {
 		return fmt.Errorf("failed to send new reply markup durning queue cancel callback handling: %w", err)
 	}
 	return nil
 }
 
-func (handler *QueueCallbacksHandler) handleDisciplineCallback(ctx context.Context, msg *tgbotapi.Message, discipline string) error {
-	user, err := handler.users.GetByTgId(ctx, msg.Chat.ID)
+// Chat of the message may be a group one, so user is taken from the payload instead
+func (handler *QueueCallbacksHandler) handleDisciplineCallback(ctx context.Context, msg *tgbotapi.Message, userTgId int64, discipline string) error {
+	user, err := handler.users.GetByTgId(ctx, userTgId)
 	if err != nil {
 		return fmt.Errorf("failed to get user by tg id during labworks discipline callback: %w", err)
 	}
@@ -115,7 +162,7 @@ func (handler *QueueCallbacksHandler) handleDisciplineCallback(ctx context.Conte
 		return customErrors.ErrNoLabworks
 	}
 
-	keyboard := handler.createDisciplineDatesKeyboard(lessons)
+	keyboard := handler.createDisciplineDatesKeyboard(ctx, lessons)
 	_, err = handler.bot.SendCtx(ctx, tgbotapi.NewEditMessageReplyMarkup(msg.Chat.ID, msg.MessageID, *keyboard))
 	if err != nil {
 		return fmt.Errorf("failed to send keyboard during labworks callback handling: %w", err)
@@ -123,7 +170,7 @@ func (handler *QueueCallbacksHandler) handleDisciplineCallback(ctx context.Conte
 	return nil
 }
 
-func (handler *QueueCallbacksHandler) createDisciplineDatesKeyboard(lessons []persistence.Lesson) *tgbotapi.InlineKeyboardMarkup {
+func (handler *QueueCallbacksHandler) createDisciplineDatesKeyboard(ctx context.Context, lessons []persistence.Lesson) *tgbotapi.InlineKeyboardMarkup {
 	markup := [][]tgbotapi.InlineKeyboardButton{}
 	for _, lesson := range lessons {
 		row := []tgbotapi.InlineKeyboardButton{}
@@ -131,82 +178,31 @@ func (handler *QueueCallbacksHandler) createDisciplineDatesKeyboard(lessons []pe
 		if lesson.SubgroupNumber != iis_api_entities.AllSubgroups {
 			formattedDate += fmt.Sprintf(" (%d)", lesson.SubgroupNumber)
 		}
-		row = append(row, 
-			tgbotapi.NewInlineKeyboardButtonData(formattedDate, createQueueTimeCallback(lesson.Id, lesson.DateTime, lesson.SubgroupNumber)))
+		data := queueTimeCodec.MustEncode(queueTimeCallback{LessonId: lesson.Id, Date: lesson.DateTime, Subgroup: lesson.SubgroupNumber})
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(formattedDate, data))
 		markup = append(markup, row)
 	}
-	markup = append(markup, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("Назад", constants.QUEUE_CANCEL_CALLBACKS)))
+	markup = append(markup, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.BACK_BUTTON), constants.QUEUE_CANCEL_CALLBACKS)))
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(markup...)
 	return &keyboard
 }
 
-const maxCallbackSize = 64
-func createQueueTimeCallback(lessonId int64, date time.Time, subgroup iis_api_entities.Subgroup) string {
-	builder := strings.Builder{}
-	builder.Grow(maxCallbackSize)
-	builder.WriteString(constants.QUEUE_TIME_CALLBACKS)
-	builder.WriteString("|")
-	fmt.Fprintf(&builder, "%d.%d.%d", date.Day(), date.Month(), date.Year())
-	builder.WriteString("|")
-	fmt.Fprint(&builder, lessonId)
-	builder.WriteString("|")
-	fmt.Fprint(&builder, subgroup)
-	return builder.String()
+type queueTimeCallback struct {
+	LessonId int64
+	Date     time.Time
+	Subgroup int8
 }
 
-func parseLabworkTimeCallback(callback string) (date time.Time, labworkId int64, subgroup int8) {
-	callback, _ = strings.CutPrefix(callback, constants.QUEUE_TIME_CALLBACKS+"|")
-	subgroup = iis_api_entities.AllSubgroups
-	formattedDate, after, _ := strings.Cut(callback, "|")
+var queueTimeCodec = tgutils.NewCallbackCodec[queueTimeCallback](constants.QUEUE_TIME_CALLBACKS, 1)
 
-	after, subgroupString, _ := strings.Cut(after, "|")
-	labworkId, err := strconv.ParseInt(after, 10, 64)
-	if err != nil {
-		return time.Time{}, 0, subgroup
-	}
-	nums := strings.Split(formattedDate, ".")
-	if len(nums) < 3 {
-		return time.Time{}, 0, subgroup
-	}
-	day, err := strconv.Atoi(nums[0])
-	if err != nil {
-		return time.Time{}, 0, subgroup
-	}
-	month, err := strconv.Atoi(nums[1])
-	if err != nil {
-		return time.Time{}, 0, subgroup
-	}
-	year, err := strconv.Atoi(nums[2])
-	if err != nil {
-		return time.Time{}, 0, subgroup
-	}
-	date = time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.Local)
-	if subgroupString != "" {
-		subgroupVal, err := strconv.Atoi(subgroupString)
-		if err != nil {
-			return time.Time{}, 0, subgroup
-		}
-		subgroup = int8(subgroupVal)
-	}
-	return date, labworkId, subgroup
-}
-
-func (handler *QueueCallbacksHandler) handleTimeCallback(ctx context.Context, update *tgbotapi.Update) error {
-	_, labworkId, _ := parseLabworkTimeCallback(update.CallbackData())
+func (handler *QueueCallbacksHandler) handleTimeCallback(ctx context.Context, update *tgbotapi.Update, labworkId int64) error {
 	users, err := handler.requests.GetLabworkQueue(ctx, labworkId)
 	if err != nil {
 		return fmt.Errorf("failed to get labwork queue from db: %w", err)
 	}
-	var output strings.Builder
-	for i, user := range users {
-		fmt.Fprintf(&output, "%d %s\n", i+1, user.FullName)
-	}
-	if output.String() == "" {
-		output.WriteString("На эту лабораторную нет заявок. Как знать,может,вы будете первым")
-	}
 	_, err = handler.bot.SendCtx(ctx, 
-		tgbotapi.NewEditMessageTextAndMarkup(update.FromChat().ChatConfig().ChatID, update.CallbackQuery.Message.MessageID, output.String(),
-		tgbotapi.InlineKeyboardMarkup{InlineKeyboard: make([][]tgbotapi.InlineKeyboardButton, 0)}))
+		tgbotapi.NewEditMessageTextAndMarkup(update.FromChat().ChatConfig().ChatID, update.CallbackQuery.Message.MessageID, renderQueue(ctx, users),
+		subscribeKeyboard(ctx, labworkId)))
 	if err != nil {
 		return fmt.Errorf("failed to send queue during queue time callback handling: %w", err)
 	}
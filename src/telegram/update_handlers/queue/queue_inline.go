@@ -0,0 +1,130 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	iis_api_entities "github.com/aCrYoZPS/bsuir_queue_bot/src/iis_api/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// Telegram doesn't accept more results in the answer to the inline query
+	maxInlineResults = 50
+	// Queue changes often, so results are cached only for a short time
+	inlineCacheTime = 10
+	// Telegram rejects inline results, which send longer text
+	maxArticleCharacters = 4096
+)
+
+type InlineQueues interface {
+	GetLabworkQueues(ctx context.Context, labworkIds []int64) (map[int64][]entities.User, error)
+}
+
+// QueueInlineHandler answers inline queries with queues of upcoming labworks of the group of the user,
+// so that queue can be shared into any chat. Text of the query filters disciplines by substring
+type QueueInlineHandler struct {
+	users    interfaces.UsersRepository
+	labworks interfaces.LessonsRepository
+	requests InlineQueues
+}
+
+func NewQueueInlineHandler(users interfaces.UsersRepository, labworks interfaces.LessonsRepository, requests InlineQueues) *QueueInlineHandler {
+	return &QueueInlineHandler{users: users, labworks: labworks, requests: requests}
+}
+
+func (handler *QueueInlineHandler) HandleInlineQuery(ctx context.Context, query *tgbotapi.InlineQuery, bot *tgutils.Bot) error {
+	answer := tgbotapi.InlineConfig{
+		InlineQueryID: query.ID,
+		Results:       []interface{}{},
+		CacheTime:     inlineCacheTime,
+		IsPersonal:    true,
+	}
+	user, err := handler.users.GetByTgId(ctx, query.From.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get user by tg id during queue inline query handling: %w", err)
+	}
+	if user == nil || user.GroupId == 0 {
+		answer.SwitchPMText = i18n.T(ctx, i18n.INLINE_JOIN_GROUP_TEXT)
+		answer.SwitchPMParameter = "inline"
+		return handler.answer(ctx, bot, answer)
+	}
+
+	subjects, err := handler.labworks.GetSubjects(ctx, user.GroupId)
+	if err != nil {
+		return fmt.Errorf("failed to get subjects during queue inline query handling: %w", err)
+	}
+	filter := strings.ToLower(strings.TrimSpace(query.Query))
+	lessons := []persistence.Lesson{}
+	for _, subject := range subjects {
+		if len(lessons) >= maxInlineResults {
+			break
+		}
+		if !strings.Contains(strings.ToLower(subject), filter) {
+			continue
+		}
+		next, err := handler.labworks.GetNext(ctx, subject, user.GroupId)
+		if err != nil {
+			return fmt.Errorf("failed to get next labworks of %s during queue inline query handling: %w", subject, err)
+		}
+		lessons = append(lessons, next...)
+	}
+	lessons = lessons[:min(len(lessons), maxInlineResults)]
+
+	ids := make([]int64, 0, len(lessons))
+	for _, lesson := range lessons {
+		ids = append(ids, lesson.Id)
+	}
+	queues, err := handler.requests.GetLabworkQueues(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get labwork queues during queue inline query handling: %w", err)
+	}
+	for _, lesson := range lessons {
+		answer.Results = append(answer.Results, createQueueArticle(ctx, lesson, queues[lesson.Id]))
+	}
+	return handler.answer(ctx, bot, answer)
+}
+
+func createQueueArticle(ctx context.Context, lesson persistence.Lesson, users []entities.User) tgbotapi.InlineQueryResultArticle {
+	title := fmt.Sprintf("%s %02d/%02d/%d", lesson.Subject, lesson.DateTime.Day(), lesson.DateTime.Month(), lesson.DateTime.Year())
+	if lesson.SubgroupNumber != iis_api_entities.AllSubgroups {
+		title += fmt.Sprintf(" (%d)", lesson.SubgroupNumber)
+	}
+
+	var output strings.Builder
+	output.WriteString(i18n.T(ctx, i18n.INLINE_QUEUE_TITLE, title))
+	for i, user := range users {
+		fmt.Fprintf(&output, "%d %s\n", i+1, user.FullName)
+	}
+	if len(users) == 0 {
+		output.WriteString(i18n.T(ctx, i18n.INLINE_QUEUE_EMPTY))
+	}
+
+	article := tgbotapi.NewInlineQueryResultArticle(strconv.FormatInt(lesson.Id, 10), title, truncateText(output.String()))
+	article.Description = i18n.N(ctx, i18n.QUEUE_SIZE, len(users))
+	return article
+}
+
+// truncateText cuts the text on line boundary, so that it fits into the inline result
+func truncateText(text string) string {
+	parts := tgutils.SplitText(text, maxArticleCharacters-1)
+	if len(parts) <= 1 {
+		return text
+	}
+	return parts[0] + "…"
+}
+
+func (handler *QueueInlineHandler) answer(ctx context.Context, bot *tgutils.Bot, answer tgbotapi.InlineConfig) error {
+	_, err := bot.RequestCtx(ctx, answer)
+	if err != nil {
+		return fmt.Errorf("failed to answer inline query: %w", err)
+	}
+	return nil
+}
@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"slices"
 	"strconv"
-	"strings"
 
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
 	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
@@ -18,15 +18,17 @@ type QueueStartState struct {
 	cache     interfaces.HandlersCache
 	usersRepo interfaces.UsersRepository
 	lessons   interfaces.LessonsRepository
+	payloads  *tgutils.CallbackPayloads
 }
 
 func NewQueueStartState(bot *tgutils.Bot, cache interfaces.HandlersCache, usersRepo interfaces.UsersRepository, 
-	lessons interfaces.LessonsRepository) *QueueStartState {
+	lessons interfaces.LessonsRepository, payloads *tgutils.CallbackPayloads) *QueueStartState {
 	return &QueueStartState{
 		bot:       bot,
 		cache:     cache,
 		usersRepo: usersRepo,
 		lessons:   lessons,
+		payloads:  payloads,
 	}
 }
 
@@ -36,7 +38,7 @@ func (state *QueueStartState) Handle(ctx context.Context, msg *tgbotapi.Message)
 		return fmt.Errorf("failed to get user by tg id during queue command handling: %w", err)
 	}
 	if usr.GroupId == 0 {
-		_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, "Вы пока не принадлежите ни к одной группе"))
+		_, err = state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, i18n.T(ctx, i18n.NO_GROUP_TEXT)))
 		if err != nil {
 			return fmt.Errorf("failed to send no group message during queue command handling: %w", err)
 		}
@@ -48,7 +50,7 @@ func (state *QueueStartState) Handle(ctx context.Context, msg *tgbotapi.Message)
 	}
 	if len(subjects) == 0 {
 		newState := interfaces.NewCachedInfo(msg.Chat.ID, constants.IDLE_STATE)
-		_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, "Больше не осталось лабораторных. Отдохните"))
+		_, err := state.bot.SendCtx(ctx, tgbotapi.NewMessage(msg.Chat.ID, i18n.T(ctx, i18n.NO_LABWORKS_LEFT_TEXT)))
 		if err != nil {
 			return fmt.Errorf("failed to send no labworks message during queue command handling: %w", err)
 		}
@@ -59,8 +61,11 @@ func (state *QueueStartState) Handle(ctx context.Context, msg *tgbotapi.Message)
 		return nil
 	}
 
-	response := tgbotapi.NewMessage(msg.Chat.ID, "Выберите предмет")
-	response.ReplyMarkup = createLabworksKeyboard(msg.From.ID, subjects)
+	response := tgbotapi.NewMessage(msg.Chat.ID, i18n.T(ctx, i18n.CHOOSE_DISCIPLINE_TEXT))
+	response.ReplyMarkup, err = createLabworksKeyboard(ctx, state.payloads, msg.From.ID, subjects)
+	if err != nil {
+		return fmt.Errorf("failed to create labworks keyboard during queue command handling: %w", err)
+	}
 	sentMsg, err := state.bot.SendCtx(ctx, response)
 	if err != nil {
 		return fmt.Errorf("failed to send respponse during queue command handling: %w", err)
@@ -83,34 +88,42 @@ func (state *QueueStartState) Revert(ctx context.Context, msg *tgbotapi.Message)
 }
 
 const labworksMarkupSize = 4
-func createLabworksKeyboard(userTgId int64, subjects []string) *tgbotapi.InlineKeyboardMarkup {
+func createLabworksKeyboard(ctx context.Context, payloads *tgutils.CallbackPayloads, userTgId int64, subjects []string) (*tgbotapi.InlineKeyboardMarkup, error) {
 	markup := [][]tgbotapi.InlineKeyboardButton{}
 	for chunk := range slices.Chunk(subjects, labworksMarkupSize) {
 		row := []tgbotapi.InlineKeyboardButton{}
 		for _, discipline := range chunk {
-			row = append(row, tgbotapi.NewInlineKeyboardButtonData(discipline, createQueueDisciplineCallback(userTgId, discipline)))
+			data, err := createQueueDisciplineCallback(ctx, payloads, userTgId, discipline)
+			if err != nil {
+				return nil, err
+			}
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(discipline, data))
 		}
 		markup = append(markup, row)
 	}
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(markup...)
-	return &keyboard
+	return &keyboard, nil
 }
 
-func createQueueDisciplineCallback(userTgId int64, discipline string) string {
-	return constants.QUEUE_DISCIPLINE_CALLBACKS + "|" + fmt.Sprint(userTgId) + "|" + discipline
+type disciplinePayload struct {
+	UserTgId   int64  `json:"tg_id"`
+	Discipline string `json:"discipline"`
 }
 
-func parseQueueDisciplineCallback(callback string) (tgId int64, discipline string) {
-	callback, _ = strings.CutPrefix(callback, constants.QUEUE_DISCIPLINE_CALLBACKS+"|")
-	callbackParts := strings.Split(callback, "|")
-	if len(callbackParts) != 2 {
-		return 0, ""
+func createQueueDisciplineCallback(ctx context.Context, payloads *tgutils.CallbackPayloads, userTgId int64, discipline string) (string, error) {
+	data, err := tgutils.PutPayload(ctx, payloads, constants.QUEUE_DISCIPLINE_CALLBACKS, disciplinePayload{UserTgId: userTgId, Discipline: discipline})
+	if err != nil {
+		return "", fmt.Errorf("failed to create queue discipline callback: %w", err)
 	}
-	tgId, err := strconv.ParseInt(callbackParts[0], 10, 64)
+	return data, nil
+}
+
+func parseQueueDisciplineCallback(ctx context.Context, payloads *tgutils.CallbackPayloads, callback string) (*disciplinePayload, error) {
+	payload, err := tgutils.GetPayload[disciplinePayload](ctx, payloads, constants.QUEUE_DISCIPLINE_CALLBACKS, callback)
 	if err != nil {
-		return 0, ""
+		return nil, fmt.Errorf("failed to parse queue discipline callback: %w", err)
 	}
-	return tgId, callbackParts[1]
+	return payload, nil
 }
 
 type QueueWaitingState struct {
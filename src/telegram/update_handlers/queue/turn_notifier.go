@@ -0,0 +1,155 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/sqlite/persistence"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const DEFAULT_TURN_NOTIFICATIONS = 3
+
+type LessonQueuesRepository interface {
+	GetLessonQueue(ctx context.Context, lessonId int64) ([]entities.QueuedRequest, error)
+}
+
+type LessonsGetter interface {
+	Get(ctx context.Context, id int64) (persistence.Lesson, error)
+}
+
+type TurnNotificationsRepository interface {
+	GetTurnNotifications(ctx context.Context, tgId int64) (bool, error)
+}
+
+// TurnNotifier tells the first students, waiting in the queue of the lesson, their places, whenever the places change.
+// Places are counted among requests without outcome, the request, called by the admin in lesson mode, isn't counted
+type TurnNotifier struct {
+	bot      *tgutils.Bot
+	requests LessonQueuesRepository
+	lessons  LessonsGetter
+	settings TurnNotificationsRepository
+	count    int
+	// Notifications of the same lesson are sent one at a time, in the background of handlers
+	throttle *tgutils.Throttle
+
+	mu    sync.Mutex
+	turns map[int64]*lessonTurns
+}
+
+// lessonTurns are the last places, sent to the students, by requests, so that unchanged places aren't sent again.
+// They are kept for the day of the lesson only
+type lessonTurns struct {
+	date   string
+	called int64
+	places map[int64]int
+}
+
+func NewTurnNotifier(bot *tgutils.Bot, requests LessonQueuesRepository, lessons LessonsGetter, settings TurnNotificationsRepository,
+	count int) *TurnNotifier {
+	return &TurnNotifier{
+		bot:      bot,
+		requests: requests,
+		lessons:  lessons,
+		settings: settings,
+		count:    count,
+		throttle: tgutils.NewThrottle(0),
+		turns:    map[int64]*lessonTurns{},
+	}
+}
+
+// Advance remembers the request, called by the admin, or zero, if there is none, and notifies the students after it
+func (notifier *TurnNotifier) Advance(ctx context.Context, lessonId, calledRequestId int64) {
+	notifier.mu.Lock()
+	notifier.lessonTurns(lessonId).called = calledRequestId
+	notifier.mu.Unlock()
+	notifier.Notify(ctx, lessonId)
+}
+
+// Notify sends changed places to the first students of the queue, e.g. after a request before them was removed.
+// Only lessons of today are handled, changes of queues of later lessons aren't worth a message.
+// Notifications are sent asynchronously, errors are only logged, since the change of the queue has already happened
+func (notifier *TurnNotifier) Notify(ctx context.Context, lessonId int64) {
+	ctx = context.WithoutCancel(ctx)
+	notifier.throttle.Do(lessonId, func() { notifier.notifyLesson(ctx, lessonId) })
+}
+
+func (notifier *TurnNotifier) notifyLesson(ctx context.Context, lessonId int64) {
+	lesson, err := notifier.lessons.Get(ctx, lessonId)
+	if err != nil {
+		slog.Error(fmt.Errorf("failed to get lesson %d to notify students about their turn: %w", lessonId, err).Error())
+		return
+	}
+	if lesson.DateTime.Format(time.DateOnly) != time.Now().Format(time.DateOnly) {
+		notifier.mu.Lock()
+		delete(notifier.turns, lessonId)
+		notifier.mu.Unlock()
+		return
+	}
+	queue, err := notifier.requests.GetLessonQueue(ctx, lessonId)
+	if err != nil {
+		slog.Error(fmt.Errorf("failed to get queue of lesson %d to notify students about their turn: %w", lessonId, err).Error())
+		return
+	}
+
+	notifier.mu.Lock()
+	turns := notifier.lessonTurns(lessonId)
+	places, changed := map[int64]int{}, []entities.QueuedRequest{}
+	for _, request := range queue {
+		if len(places) == notifier.count {
+			break
+		}
+		if request.Outcome != entities.NoOutcome || request.Id == turns.called {
+			continue
+		}
+		places[request.Id] = len(places) + 1
+		if turns.places[request.Id] != places[request.Id] {
+			changed = append(changed, request)
+		}
+	}
+	turns.places = places
+	notifier.mu.Unlock()
+
+	for _, request := range changed {
+		err := notifier.notify(ctx, lesson, request, places[request.Id])
+		if err != nil {
+			slog.Error(fmt.Errorf("failed to notify user %d about their turn: %w", request.UserId, err).Error())
+		}
+	}
+}
+
+// lessonTurns returns turns of the lesson for today, forgetting turns of previous days. It must be called with the lock held
+func (notifier *TurnNotifier) lessonTurns(lessonId int64) *lessonTurns {
+	today := time.Now().Format(time.DateOnly)
+	for id, turns := range notifier.turns {
+		if turns.date != today {
+			delete(notifier.turns, id)
+		}
+	}
+	turns, ok := notifier.turns[lessonId]
+	if !ok {
+		turns = &lessonTurns{date: today, places: map[int64]int{}}
+		notifier.turns[lessonId] = turns
+	}
+	return turns
+}
+
+func (notifier *TurnNotifier) notify(ctx context.Context, lesson persistence.Lesson, request entities.QueuedRequest, place int) error {
+	enabled, err := notifier.settings.GetTurnNotifications(ctx, request.UserId)
+	if err != nil || !enabled {
+		return err
+	}
+	ctx = notifier.bot.Localize(ctx, request.UserId, "")
+	text := i18n.T(ctx, i18n.TURN_POSITION_TEXT, request.LabworkNumber, lesson.Subject, place)
+	if place == 1 {
+		text = i18n.T(ctx, i18n.TURN_NEXT_TEXT, request.LabworkNumber, lesson.Subject)
+	}
+	_, err = notifier.bot.SendCtx(ctx, tgbotapi.NewMessage(request.ChatId, text))
+	return err
+}
@@ -0,0 +1,102 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+type SettingsRepository interface {
+	GetTurnNotifications(ctx context.Context, tgId int64) (bool, error)
+	SetTurnNotifications(ctx context.Context, tgId int64, enabled bool) (bool, error)
+}
+
+type settingsCallback struct {
+	TurnNotifications bool
+}
+
+var SettingsCodec = tgutils.NewCallbackCodec[settingsCallback](constants.SETTINGS_CALLBACKS, 1)
+
+// SettingsStartState shows settings of the user, the flow ends right away, since buttons of settings work from idle state
+type SettingsStartState struct {
+	bot      *tgutils.Bot
+	cache    interfaces.HandlersCache
+	settings SettingsRepository
+}
+
+func NewSettingsStartState(bot *tgutils.Bot, cache interfaces.HandlersCache, settings SettingsRepository) *SettingsStartState {
+	return &SettingsStartState{bot: bot, cache: cache, settings: settings}
+}
+
+func (state *SettingsStartState) Handle(ctx context.Context, msg *tgbotapi.Message) error {
+	err := state.cache.SaveState(ctx, *interfaces.NewCachedInfo(msg.Chat.ID, constants.IDLE_STATE))
+	if err != nil {
+		return fmt.Errorf("failed to save idle state during settings command handling: %w", err)
+	}
+	enabled, err := state.settings.GetTurnNotifications(ctx, msg.From.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get settings during settings command handling: %w", err)
+	}
+	resp := tgbotapi.NewMessage(msg.Chat.ID, "")
+	resp.Text, resp.ReplyMarkup = renderSettings(ctx, enabled)
+	_, err = state.bot.SendCtx(ctx, resp)
+	if err != nil {
+		return fmt.Errorf("failed to send settings during settings command handling: %w", err)
+	}
+	return nil
+}
+
+func (state *SettingsStartState) Revert(ctx context.Context, msg *tgbotapi.Message) error {
+	return nil
+}
+
+type SettingsCallbackHandler struct {
+	settings SettingsRepository
+}
+
+func NewSettingsCallbackHandler(settings SettingsRepository) *SettingsCallbackHandler {
+	return &SettingsCallbackHandler{settings: settings}
+}
+
+// HandleCallback saves the chosen value of the setting and shows settings anew
+func (handler *SettingsCallbackHandler) HandleCallback(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot,
+	data *settingsCallback) error {
+	saved, err := handler.settings.SetTurnNotifications(ctx, update.SentFrom().ID, data.TurnNotifications)
+	if err != nil {
+		return fmt.Errorf("failed to save settings during settings callback handling: %w", err)
+	}
+	if !saved {
+		_, err = bot.RequestCtx(ctx, tgbotapi.NewCallbackWithAlert(update.CallbackQuery.ID, i18n.T(ctx, i18n.SETTINGS_UNAVAILABLE_TEXT)))
+		if err != nil {
+			return fmt.Errorf("failed to answer settings callback of unregistered user: %w", err)
+		}
+		return nil
+	}
+
+	msg := update.CallbackQuery.Message
+	text, keyboard := renderSettings(ctx, data.TurnNotifications)
+	_, err = bot.SendCtx(ctx, tgbotapi.NewEditMessageTextAndMarkup(msg.Chat.ID, msg.MessageID, text, keyboard))
+	if err != nil {
+		return fmt.Errorf("failed to edit settings during settings callback handling: %w", err)
+	}
+	_, err = bot.RequestCtx(ctx, tgbotapi.NewCallback(update.CallbackQuery.ID, ""))
+	if err != nil {
+		return fmt.Errorf("failed to answer settings callback: %w", err)
+	}
+	return nil
+}
+
+func renderSettings(ctx context.Context, turnNotifications bool) (string, tgbotapi.InlineKeyboardMarkup) {
+	status, button := i18n.TURN_NOTIFICATIONS_DISABLED, i18n.TURN_NOTIFICATIONS_ON_BUTTON
+	if turnNotifications {
+		status, button = i18n.TURN_NOTIFICATIONS_ENABLED, i18n.TURN_NOTIFICATIONS_OFF_BUTTON
+	}
+	return i18n.T(ctx, i18n.SETTINGS_TEXT, i18n.T(ctx, status)), tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, button), SettingsCodec.MustEncode(settingsCallback{TurnNotifications: !turnNotifications})),
+	))
+}
@@ -7,6 +7,7 @@ const AsciiChars = 26
 type TrieNode[T any] struct {
 	children map[rune]*TrieNode[T]
 	isLeaf   bool
+	hasVal   bool
 	val      T
 }
 
@@ -18,6 +19,10 @@ func (node *TrieNode[T]) IsLeaf() bool {
 	return node.isLeaf
 }
 
+func (node *TrieNode[T]) HasVal() bool {
+	return node.hasVal
+}
+
 func (node *TrieNode[T]) Val() T {
 	return node.val
 }
@@ -32,7 +37,8 @@ func (root *TrieNode[T]) Insert(key string, val T) {
 		}
 		cur = cur.children[char]
 	}
-	cur.isLeaf = true
+	cur.isLeaf = len(cur.children) == 0
+	cur.hasVal = true
 	cur.val = val
 }
 
@@ -45,7 +51,7 @@ func (root *TrieNode[T]) SearchExact(key string) (T, bool) {
 		}
 		cur = cur.children[char]
 	}
-	return cur.val, cur.isLeaf
+	return cur.val, cur.hasVal
 }
 
 // Returns either longest prefix match, or zero value of a type
@@ -57,7 +63,9 @@ func (root *TrieNode[T]) Search(key string) T {
 			return result
 		}
 		cur = cur.children[char]
-		result = cur.val
+		if cur.hasVal {
+			result = cur.val
+		}
 	}
 	return result
 }
@@ -3,68 +3,424 @@ package tgutils
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
 	"unicode/utf8"
 
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
+// MarkupsStore keeps ids of messages with inline keyboards, which were sent to the chat during its current flow
+type MarkupsStore interface {
+	AddMarkup(ctx context.Context, chatId int64, msgId int) error
+	GetMarkups(ctx context.Context, chatId int64) ([]int, error)
+	RemoveMarkups(ctx context.Context, chatId int64) error
+	// GetMarkupUser returns the user, which flow the message belongs to, or zero, if it isn't tracked
+	GetMarkupUser(ctx context.Context, chatId int64, msgId int) (int64, error)
+}
+
+// LanguagesStore keeps languages, chosen by the users
+type LanguagesStore interface {
+	GetLanguage(ctx context.Context, tgId int64) (string, error)
+}
+
 type Bot struct {
 	*tgbotapi.BotAPI
+	markups   MarkupsStore
+	languages LanguagesStore
+	scheduler *Scheduler
+	maxParts  int
+	// Format of file download urls, see tgbotapi.FileEndpoint
+	fileEndpoint string
+}
+
+func NewBot(botApi *tgbotapi.BotAPI, opts ...func(*Bot)) *Bot {
+	bot := &Bot{BotAPI: botApi, maxParts: DEFAULT_MAX_MESSAGE_PARTS, fileEndpoint: tgbotapi.FileEndpoint}
+	for _, opt := range opts {
+		opt(bot)
+	}
+	return bot
 }
 
-func NewBot(botApi *tgbotapi.BotAPI) *Bot {
-	return &Bot{BotAPI: botApi}
+func WithMarkupsStore(markups MarkupsStore) func(*Bot) {
+	return func(bot *Bot) {
+		bot.markups = markups
+	}
+}
+
+func WithLanguagesStore(languages LanguagesStore) func(*Bot) {
+	return func(bot *Bot) {
+		bot.languages = languages
+	}
+}
+
+// WithRateLimits makes bot send messages through scheduler, which enforces given limits and retries failed sends
+func WithRateLimits(limits RateLimits) func(*Bot) {
+	return func(bot *Bot) {
+		bot.scheduler = NewScheduler(limits)
+	}
+}
+
+// WithMaxMessageParts sets amount of messages, which long text can be split into. Longer texts are sent as .txt documents
+func WithMaxMessageParts(parts int) func(*Bot) {
+	return func(bot *Bot) {
+		bot.maxParts = parts
+	}
+}
+
+// WithFileEndpoint makes bot download files from another Bot API server, e.g. local one or fake server in tests
+func WithFileEndpoint(endpoint string) func(*Bot) {
+	return func(bot *Bot) {
+		bot.fileEndpoint = endpoint
+	}
+}
+
+// APIEndpoint returns format of method urls of Bot API server, hosted on baseUrl, for tgbotapi.NewBotAPIWithAPIEndpoint
+func APIEndpoint(baseUrl string) string {
+	return strings.TrimSuffix(baseUrl, "/") + "/bot%s/%s"
+}
+
+// FileEndpoint returns format of file urls of Bot API server, hosted on baseUrl, for WithFileEndpoint
+func FileEndpoint(baseUrl string) string {
+	return strings.TrimSuffix(baseUrl, "/") + "/file/bot%s/%s"
+}
+
+// Localize stores locale of the user in context, so that messages to the user are translated into it.
+// Language, chosen by the user, takes precedence over the language code of the telegram client, which may be empty
+func (bot *Bot) Localize(ctx context.Context, userId int64, languageCode string) context.Context {
+	if bot.languages != nil {
+		language, err := bot.languages.GetLanguage(ctx, userId)
+		if err != nil {
+			slog.Error(fmt.Sprintf("failed to get language of user %d: %s", userId, err.Error()))
+		}
+		if locale, ok := i18n.Parse(language); ok {
+			return i18n.WithLocale(ctx, locale)
+		}
+	}
+	return i18n.WithLocale(ctx, i18n.FromLanguageCode(languageCode))
 }
 
 const (
 	tgMsgMaxCharacters     = 4096
 	tgCaptionMaxCharacters = 1024
+
+	DEFAULT_MAX_MESSAGE_PARTS = 5
+	LONG_MESSAGE_FILENAME     = "message.txt"
 )
 
 var ErrMsgInvalidLen = errors.New("tg message overflows max capacity of characters")
 
+// SendCtx sends the message, respecting deadline of the context. Text messages, which are too long for telegram,
+// are split into several messages or sent as a document. Edits with such texts keep only the first part in the edited message
 func (bot *Bot) SendCtx(ctx context.Context, c tgbotapi.Chattable) (tgbotapi.Message, error) {
-	resChan := make(chan struct {
-		tgbotapi.Message
-		error
-	})
-	go func(chan struct {
-		tgbotapi.Message
-		error
-	}) {
-		switch msg := c.(type) {
-		case tgbotapi.MessageConfig:
-			if utf8.RuneCountInString(msg.Text) > tgMsgMaxCharacters {
-				resChan <- struct {
-					tgbotapi.Message
-					error
-				}{tgbotapi.Message{}, ErrMsgInvalidLen}
-			}
-		case tgbotapi.DocumentConfig:
-			if utf8.RuneCountInString(msg.Caption) > tgCaptionMaxCharacters {
-				resChan <- struct {
-					tgbotapi.Message
-					error
-				}{tgbotapi.Message{}, ErrMsgInvalidLen}
-			}
-		case tgbotapi.PhotoConfig:
-			if utf8.RuneCountInString(msg.Caption) > tgCaptionMaxCharacters {
-				resChan <- struct {
-					tgbotapi.Message
-					error
-				}{tgbotapi.Message{}, ErrMsgInvalidLen}
-			}
+	c = chattableValue(c)
+	if msg, ok := c.(tgbotapi.MessageConfig); ok && utf8.RuneCountInString(msg.Text) > tgMsgMaxCharacters {
+		return bot.sendLong(ctx, msg)
+	}
+	if edit, ok := c.(tgbotapi.EditMessageTextConfig); ok && edit.ChatID != 0 && utf8.RuneCountInString(edit.Text) > tgMsgMaxCharacters {
+		return bot.editLong(ctx, edit)
+	}
+	if !hasValidLen(c) {
+		return tgbotapi.Message{}, ErrMsgInvalidLen
+	}
+	msg, err := bot.send(ctx, c)
+	if err != nil {
+		return msg, err
+	}
+	err = bot.trackMarkup(ctx, c, &msg)
+	if err != nil {
+		return msg, err
+	}
+	return msg, nil
+}
+
+// sendLong splits text of the message on line boundaries. Reply is kept on the first part and markup on the last one.
+// If there are more parts than allowed, text is sent as a .txt document instead
+func (bot *Bot) sendLong(ctx context.Context, msg tgbotapi.MessageConfig) (tgbotapi.Message, error) {
+	parts := SplitText(msg.Text, tgMsgMaxCharacters)
+	if len(parts) > bot.maxParts {
+		doc := tgbotapi.NewDocument(msg.ChatID, tgbotapi.FileBytes{Name: LONG_MESSAGE_FILENAME, Bytes: []byte(msg.Text)})
+		doc.ReplyToMessageID = msg.ReplyToMessageID
+		doc.ReplyMarkup = msg.ReplyMarkup
+		return bot.SendCtx(ctx, doc)
+	}
+	var sent tgbotapi.Message
+	for i, part := range parts {
+		partMsg := msg
+		partMsg.Text = part
+		if i != 0 {
+			partMsg.ReplyToMessageID = 0
+		}
+		if i != len(parts)-1 {
+			partMsg.ReplyMarkup = nil
+		}
+		var err error
+		sent, err = bot.SendCtx(ctx, partMsg)
+		if err != nil {
+			return sent, fmt.Errorf("failed to send part %d of %d of long message: %w", i+1, len(parts), err)
 		}
+	}
+	return sent, nil
+}
+
+// editLong edits the message with the first part of the text, keeping its markup, since handlers edit the same message again.
+// The rest of the text is sent as a reply to the message, split into several messages or as a document
+func (bot *Bot) editLong(ctx context.Context, edit tgbotapi.EditMessageTextConfig) (tgbotapi.Message, error) {
+	parts := SplitText(edit.Text, tgMsgMaxCharacters)
+	edit.Text = parts[0]
+	edited, err := bot.SendCtx(ctx, edit)
+	if err != nil {
+		return edited, err
+	}
+	rest := tgbotapi.NewMessage(edit.ChatID, strings.Join(parts[1:], ""))
+	rest.ParseMode = edit.ParseMode
+	rest.ReplyToMessageID = edit.MessageID
+	_, err = bot.SendCtx(ctx, rest)
+	if err != nil {
+		return edited, fmt.Errorf("failed to send the rest of long text of edited message %d: %w", edit.MessageID, err)
+	}
+	return edited, nil
+}
+
+// SplitText splits text into parts of at most limit characters. Text is split on line boundaries,
+// lines, which are longer than limit, are split on their own. Newline after such line is dropped,
+// if nothing of the line is left for it, as the part ends there anyway
+func SplitText(text string, limit int) []string {
+	parts := []string{}
+	var part strings.Builder
+	partLen := 0
+	flush := func() {
+		if partLen != 0 {
+			parts = append(parts, part.String())
+			part.Reset()
+			partLen = 0
+		}
+	}
+	for _, line := range strings.SplitAfter(text, "\n") {
+		lineLen := utf8.RuneCountInString(line)
+		if partLen+lineLen > limit {
+			flush()
+		}
+		split := lineLen > limit
+		for lineLen > limit {
+			runes := []rune(line)
+			parts = append(parts, string(runes[:limit]))
+			line = string(runes[limit:])
+			lineLen -= limit
+		}
+		if split && line == "\n" {
+			continue
+		}
+		part.WriteString(line)
+		partLen += lineLen
+	}
+	flush()
+	return parts
+}
+
+// send passes the message to scheduler, if bot has one, otherwise sends it right away
+func (bot *Bot) send(ctx context.Context, c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	if bot.scheduler != nil {
+		return bot.schedule(ctx, c, func() (tgbotapi.Message, error) { return bot.BotAPI.Send(c) })
+	}
+	resChan := make(chan sendResult, 1)
+	go func() {
 		msg, err := bot.BotAPI.Send(c)
-		resChan <- struct {
-			tgbotapi.Message
-			error
-		}{msg, err}
-	}(resChan)
+		resChan <- sendResult{msg: msg, err: err}
+	}()
 	select {
 	case res := <-resChan:
-		return res.Message, res.error
+		return res.msg, res.err
 	case <-ctx.Done():
 		return tgbotapi.Message{}, ctx.Err()
 	}
 }
+
+// Request sends the request, which doesn't return a message (e.g. answer to the callback), respecting rate limits of the bot.
+// It shadows BotAPI.Request, so that requests don't bypass the scheduler. Handlers should use RequestCtx instead
+func (bot *Bot) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	return bot.RequestCtx(context.Background(), c)
+}
+
+// RequestCtx is Request, respecting deadline of the context
+func (bot *Bot) RequestCtx(ctx context.Context, c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	c = chattableValue(c)
+	if bot.scheduler == nil {
+		return bot.BotAPI.Request(c)
+	}
+	var resp *tgbotapi.APIResponse
+	_, err := bot.schedule(ctx, c, func() (tgbotapi.Message, error) {
+		var err error
+		resp, err = bot.BotAPI.Request(c)
+		return tgbotapi.Message{}, err
+	})
+	// Response is written by the scheduler, so it is safe to read only after the result of the send was received
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (bot *Bot) schedule(ctx context.Context, c tgbotapi.Chattable, send func() (tgbotapi.Message, error)) (tgbotapi.Message, error) {
+	if isIdempotent(c) {
+		return bot.scheduler.SendIdempotent(ctx, chatOf(c), send)
+	}
+	return bot.scheduler.Send(ctx, chatOf(c), send)
+}
+
+// DownloadFile returns content of the file, sent to the bot
+func (bot *Bot) DownloadFile(ctx context.Context, fileId string) ([]byte, error) {
+	file, err := bot.GetFile(tgbotapi.FileConfig{FileID: fileId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file %s: %w", fileId, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(bot.fileEndpoint, bot.Token, file.FilePath), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download request of file %s: %w", fileId, err)
+	}
+	resp, err := bot.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file %s: %w", fileId, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download file %s: unexpected status %s", fileId, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", fileId, err)
+	}
+	return data, nil
+}
+
+func hasValidLen(c tgbotapi.Chattable) bool {
+	switch msg := c.(type) {
+	case tgbotapi.MessageConfig:
+		return utf8.RuneCountInString(msg.Text) <= tgMsgMaxCharacters
+	case tgbotapi.EditMessageTextConfig:
+		return utf8.RuneCountInString(msg.Text) <= tgMsgMaxCharacters
+	case tgbotapi.DocumentConfig:
+		return utf8.RuneCountInString(msg.Caption) <= tgCaptionMaxCharacters
+	case tgbotapi.PhotoConfig:
+		return utf8.RuneCountInString(msg.Caption) <= tgCaptionMaxCharacters
+	case tgbotapi.VideoConfig:
+		return utf8.RuneCountInString(msg.Caption) <= tgCaptionMaxCharacters
+	case tgbotapi.AnimationConfig:
+		return utf8.RuneCountInString(msg.Caption) <= tgCaptionMaxCharacters
+	case tgbotapi.AudioConfig:
+		return utf8.RuneCountInString(msg.Caption) <= tgCaptionMaxCharacters
+	case tgbotapi.VoiceConfig:
+		return utf8.RuneCountInString(msg.Caption) <= tgCaptionMaxCharacters
+	}
+	return true
+}
+
+// trackMarkup remembers messages with inline keyboards, sent to the chat of the flow, so they can be stripped when the flow is aborted
+func (bot *Bot) trackMarkup(ctx context.Context, c tgbotapi.Chattable, sent *tgbotapi.Message) error {
+	chatId, ok := FlowChatFromContext(ctx)
+	if bot.markups == nil || !ok || sent.Chat == nil || sent.Chat.ID != chatId || !hasInlineMarkup(c) {
+		return nil
+	}
+	err := bot.markups.AddMarkup(ctx, chatId, sent.MessageID)
+	if err != nil {
+		return fmt.Errorf("failed to track inline markup of message %d: %w", sent.MessageID, err)
+	}
+	return nil
+}
+
+func hasInlineMarkup(c tgbotapi.Chattable) bool {
+	var markup any
+	switch msg := c.(type) {
+	case tgbotapi.MessageConfig:
+		markup = msg.ReplyMarkup
+	case tgbotapi.PhotoConfig:
+		markup = msg.ReplyMarkup
+	case tgbotapi.DocumentConfig:
+		markup = msg.ReplyMarkup
+	case tgbotapi.VideoConfig:
+		markup = msg.ReplyMarkup
+	case tgbotapi.AnimationConfig:
+		markup = msg.ReplyMarkup
+	case tgbotapi.AudioConfig:
+		markup = msg.ReplyMarkup
+	case tgbotapi.VoiceConfig:
+		markup = msg.ReplyMarkup
+	}
+	switch markup.(type) {
+	case tgbotapi.InlineKeyboardMarkup, *tgbotapi.InlineKeyboardMarkup:
+		return true
+	}
+	return false
+}
+
+// StripMarkups removes inline keyboards of all messages, sent during the current flow of the chat
+func (bot *Bot) StripMarkups(ctx context.Context, chatId int64) error {
+	if bot.markups == nil {
+		return nil
+	}
+	msgIds, err := bot.markups.GetMarkups(ctx, chatId)
+	if err != nil {
+		return fmt.Errorf("failed to get markups of chat %d: %w", chatId, err)
+	}
+	for _, msgId := range msgIds {
+		_, err := bot.RequestCtx(ctx, tgbotapi.NewEditMessageReplyMarkup(chatId, msgId, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}))
+		// Message could've been deleted or its markup could've been already removed by the handlers, so it is not an error
+		if err != nil {
+			slog.Debug("failed to strip markup", "chat_id", chatId, "msg_id", msgId, "err", err)
+		}
+	}
+	return bot.ForgetMarkups(ctx, chatId)
+}
+
+// ForgetMarkups stops tracking inline keyboards of the chat, leaving them in place. Is called when the flow is finished
+func (bot *Bot) ForgetMarkups(ctx context.Context, chatId int64) error {
+	if bot.markups == nil {
+		return nil
+	}
+	err := bot.markups.RemoveMarkups(ctx, chatId)
+	if err != nil {
+		return fmt.Errorf("failed to remove markups of chat %d: %w", chatId, err)
+	}
+	return nil
+}
+
+type flowChatKey struct{}
+
+// WithFlowChat stores the chat, which update is being handled, so that messages sent to this chat are considered part of its flow
+func WithFlowChat(ctx context.Context, chatId int64) context.Context {
+	return context.WithValue(ctx, flowChatKey{}, chatId)
+}
+
+func FlowChatFromContext(ctx context.Context) (int64, bool) {
+	chatId, ok := ctx.Value(flowChatKey{}).(int64)
+	return chatId, ok
+}
+
+// IsFlowMarkup checks, whether message was sent with inline keyboard during the current flow of the chat
+func (bot *Bot) IsFlowMarkup(ctx context.Context, chatId int64, msgId int) (bool, error) {
+	if bot.markups == nil {
+		return false, nil
+	}
+	msgIds, err := bot.markups.GetMarkups(ctx, chatId)
+	if err != nil {
+		return false, fmt.Errorf("failed to get markups of chat %d: %w", chatId, err)
+	}
+	return slices.Contains(msgIds, msgId), nil
+}
+
+// FlowMarkupUser returns the user, which flow in the chat the message with inline keyboard was sent during, or zero, if there is none
+func (bot *Bot) FlowMarkupUser(ctx context.Context, chatId int64, msgId int) (int64, error) {
+	if bot.markups == nil {
+		return 0, nil
+	}
+	userId, err := bot.markups.GetMarkupUser(ctx, chatId, msgId)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get user of markup %d in chat %d: %w", msgId, chatId, err)
+	}
+	return userId, nil
+}
@@ -0,0 +1,201 @@
+package tgutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const callbackFieldsSeparator = "|"
+
+var (
+	ErrCallbackPrefix    = errors.New("callback data has unexpected prefix")
+	ErrCallbackVersion   = errors.New("callback data has unsupported version")
+	ErrCallbackMalformed = errors.New("callback data is malformed")
+)
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// CallbackCodec encodes exported fields of struct T into callback data in form of prefix|v<version>|field|field...,
+// in order of their declaration. Supported field types are integers, booleans, strings without separator and time.Time,
+// which is stored with precision of seconds. Fields with `callback:"-"` tag are skipped.
+// Version should be increased on every change of T, so that buttons, sent before the change, are rejected instead of misparsed
+type CallbackCodec[T any] struct {
+	prefix  string
+	version int
+}
+
+func NewCallbackCodec[T any](prefix string, version int) *CallbackCodec[T] {
+	if reflect.TypeFor[T]().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("callback codec of %s must be created for struct type", prefix))
+	}
+	return &CallbackCodec[T]{prefix: prefix, version: version}
+}
+
+func (codec *CallbackCodec[T]) Prefix() string {
+	return codec.prefix
+}
+
+// Matches reports, whether data was encoded with prefix of the codec. Data of other codecs, which prefix starts with
+// the prefix of this one, doesn't match
+func (codec *CallbackCodec[T]) Matches(data string) bool {
+	return strings.HasPrefix(data, codec.prefix+callbackFieldsSeparator)
+}
+
+func (codec *CallbackCodec[T]) Encode(value T) (string, error) {
+	parts := []string{codec.prefix, codec.versionPart()}
+	val := reflect.ValueOf(value)
+	for _, field := range callbackFields(val.Type()) {
+		part, err := encodeCallbackField(val.FieldByIndex(field.Index))
+		if err != nil {
+			return "", fmt.Errorf("failed to encode field %s of %s callback: %w", field.Name, codec.prefix, err)
+		}
+		parts = append(parts, part)
+	}
+	data := strings.Join(parts, callbackFieldsSeparator)
+	if len(data) > tgCallbackDataMaxBytes {
+		return "", fmt.Errorf("%w: %s", ErrCallbackDataTooLong, data)
+	}
+	return data, nil
+}
+
+// MustEncode is the same as Encode, but panics on error. Is meant for values, which size is known to fit into callback data
+func (codec *CallbackCodec[T]) MustEncode(value T) string {
+	data, err := codec.Encode(value)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func (codec *CallbackCodec[T]) Decode(data string) (*T, error) {
+	rest, found := strings.CutPrefix(data, codec.prefix+callbackFieldsSeparator)
+	if !found {
+		return nil, fmt.Errorf("%w: expected %s, got (%s)", ErrCallbackPrefix, codec.prefix, data)
+	}
+	version, rest, _ := strings.Cut(rest, callbackFieldsSeparator)
+	if version != codec.versionPart() {
+		return nil, fmt.Errorf("%w: expected %s, got (%s)", ErrCallbackVersion, codec.versionPart(), data)
+	}
+
+	value := new(T)
+	val := reflect.ValueOf(value).Elem()
+	fields := callbackFields(val.Type())
+	parts := []string{}
+	if len(fields) != 0 {
+		parts = strings.Split(rest, callbackFieldsSeparator)
+	}
+	if len(parts) != len(fields) || (len(fields) == 0 && rest != "") {
+		return nil, fmt.Errorf("%w: expected %d fields, got (%s)", ErrCallbackMalformed, len(fields), data)
+	}
+	for i, field := range fields {
+		err := decodeCallbackField(val.FieldByIndex(field.Index), parts[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %s of (%s): %w", ErrCallbackMalformed, field.Name, data, err)
+		}
+	}
+	return value, nil
+}
+
+func (codec *CallbackCodec[T]) versionPart() string {
+	return "v" + strconv.Itoa(codec.version)
+}
+
+// callbackFields returns encoded fields of the struct type in order of their declaration
+func callbackFields(typ reflect.Type) []reflect.StructField {
+	fields := []reflect.StructField{}
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() || field.Tag.Get("callback") == "-" {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func encodeCallbackField(field reflect.Value) (string, error) {
+	if field.Type() == timeType {
+		return strconv.FormatInt(field.Interface().(time.Time).Unix(), 10), nil
+	}
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(field.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(field.Uint(), 10), nil
+	case reflect.Bool:
+		if field.Bool() {
+			return "1", nil
+		}
+		return "0", nil
+	case reflect.String:
+		if strings.Contains(field.String(), callbackFieldsSeparator) {
+			return "", fmt.Errorf("string (%s) contains separator %s", field.String(), callbackFieldsSeparator)
+		}
+		return field.String(), nil
+	}
+	return "", fmt.Errorf("unsupported field type %s", field.Type())
+}
+
+func decodeCallbackField(field reflect.Value, part string) error {
+	if field.Type() == timeType {
+		unix, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(time.Unix(unix, 0)))
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		val, err := strconv.ParseInt(part, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(val)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		val, err := strconv.ParseUint(part, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(val)
+	case reflect.Bool:
+		switch part {
+		case "1":
+			field.SetBool(true)
+		case "0":
+			field.SetBool(false)
+		default:
+			return fmt.Errorf("invalid bool value %s", part)
+		}
+	case reflect.String:
+		field.SetString(part)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+type TypedCallbackHandlerFunc[T any] func(ctx context.Context, update *tgbotapi.Update, bot *Bot, data *T) error
+
+// TypedCallback adapts handler of decoded callback data to CallbackHandler, returning decoding errors as is
+func TypedCallback[T any](codec *CallbackCodec[T], handler TypedCallbackHandlerFunc[T]) CallbackHandler {
+	return CallbackHandlerFunc(func(ctx context.Context, update *tgbotapi.Update, bot *Bot) error {
+		data, err := codec.Decode(update.CallbackData())
+		if err != nil {
+			return err
+		}
+		return handler(ctx, update, bot, data)
+	})
+}
+
+// RegisterCallbackFor registers handler for the prefix of codec, passing decoded callback data to it
+func RegisterCallbackFor[T any](mux *Mux, codec *CallbackCodec[T], handler TypedCallbackHandlerFunc[T]) {
+	mux.RegisterCallback(codec.Prefix(), TypedCallback(codec, handler))
+}
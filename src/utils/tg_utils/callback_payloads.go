@@ -0,0 +1,122 @@
+package tgutils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	tgCallbackDataMaxBytes = 64
+	payloadTokenBytes      = 9
+)
+
+var (
+	ErrPayloadNotFound     = errors.New("callback payload not found or expired")
+	ErrCallbackDataTooLong = errors.New("callback data overflows max capacity of bytes")
+)
+
+type PayloadsStore interface {
+	SavePayload(ctx context.Context, token, payload string, expiresAt time.Time) error
+	// GetPayload returns ErrPayloadNotFound, if there is no payload with such token or it has expired
+	GetPayload(ctx context.Context, token string) (string, error)
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
+
+// CallbackPayloads keeps payloads of callbacks on the server side, so that only short opaque token is sent in callback data,
+// which is limited to 64 bytes by telegram
+type CallbackPayloads struct {
+	store PayloadsStore
+	ttl   time.Duration
+}
+
+func NewCallbackPayloads(store PayloadsStore, ttl time.Duration) *CallbackPayloads {
+	return &CallbackPayloads{store: store, ttl: ttl}
+}
+
+// PutPayload saves payload, returning callback data in form of prefix|token
+func PutPayload[T any](ctx context.Context, payloads *CallbackPayloads, prefix string, payload T) (string, error) {
+	token, err := newPayloadToken()
+	if err != nil {
+		return "", err
+	}
+	data := prefix + "|" + token
+	if len(data) > tgCallbackDataMaxBytes {
+		return "", fmt.Errorf("%w: %s", ErrCallbackDataTooLong, data)
+	}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal callback payload: %w", err)
+	}
+	err = payloads.store.SavePayload(ctx, token, string(bytes), time.Now().Add(payloads.ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to save callback payload: %w", err)
+	}
+	return data, nil
+}
+
+// GetPayload loads payload by the token from callback data, created by PutPayload with the same prefix
+func GetPayload[T any](ctx context.Context, payloads *CallbackPayloads, prefix, data string) (*T, error) {
+	token, found := strings.CutPrefix(data, prefix+"|")
+	if !found || token == "" {
+		return nil, fmt.Errorf("invalid payload callback data (%s) for prefix %s", data, prefix)
+	}
+	stored, err := payloads.store.GetPayload(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get callback payload by token %s: %w", token, err)
+	}
+	payload := new(T)
+	err = json.Unmarshal([]byte(stored), payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal callback payload: %w", err)
+	}
+	return payload, nil
+}
+
+func (payloads *CallbackPayloads) DeleteExpired(ctx context.Context) error {
+	return payloads.store.DeleteExpired(ctx, time.Now())
+}
+
+func newPayloadToken() (string, error) {
+	bytes := make([]byte, payloadTokenBytes)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate payload token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// ExpiredPayloadMiddleware answers callbacks, which payloads have expired or which data can't be decoded anymore,
+// e.g. buttons sent in the old format, instead of treating them as failures
+func ExpiredPayloadMiddleware() CallbackMiddleware {
+	return func(next CallbackHandler) CallbackHandler {
+		return CallbackHandlerFunc(func(ctx context.Context, update *tgbotapi.Update, bot *Bot) error {
+			err := next.HandleCallback(ctx, update, bot)
+			switch {
+			case errors.Is(err, ErrPayloadNotFound):
+				_, err = bot.RequestCtx(ctx, tgbotapi.NewCallbackWithAlert(update.CallbackQuery.ID, i18n.T(ctx, i18n.PAYLOAD_EXPIRED_TEXT)))
+				if err != nil {
+					return fmt.Errorf("failed to answer callback with expired payload: %w", err)
+				}
+				return nil
+			case errors.Is(err, ErrCallbackPrefix), errors.Is(err, ErrCallbackVersion), errors.Is(err, ErrCallbackMalformed):
+				slog.Info("outdated callback is answered as expired", "data", update.CallbackData(), "error", err)
+				_, err = bot.RequestCtx(ctx, tgbotapi.NewCallback(update.CallbackQuery.ID, i18n.T(ctx, i18n.BUTTONS_EXPIRED_TEXT)))
+				if err != nil {
+					return fmt.Errorf("failed to answer outdated callback: %w", err)
+				}
+				return nil
+			}
+			return err
+		})
+	}
+}
@@ -6,29 +6,23 @@ import (
 	"slices"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/entities"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-const (
-	JOIN_GROUP_KEYBOARD  = "Вступить в группу"
-	ASSIGN_KEYBOARD      = "Стать администратором группы"
-	SUBMIT_KEYBOARD      = "Отправить заявку на лабораторную"
-	ADD_LABWORK_KEYBOARD = "Добавить собственную лабораторную"
-)
-
 func CreateStartReplyMarkup(ctx context.Context, msg *tgbotapi.MessageConfig, user *entities.User, bot *Bot) error {
 	keyboard := []tgbotapi.KeyboardButton{}
 	if user.GroupId == 0 {
-		keyboard = append(keyboard, tgbotapi.KeyboardButton{Text: JOIN_GROUP_KEYBOARD})
+		keyboard = append(keyboard, tgbotapi.KeyboardButton{Text: i18n.T(ctx, i18n.JOIN_GROUP_BUTTON)})
 	}
 	if !slices.Contains(user.Roles, entities.Admin) {
-		keyboard = append(keyboard, tgbotapi.KeyboardButton{Text: ASSIGN_KEYBOARD})
+		keyboard = append(keyboard, tgbotapi.KeyboardButton{Text: i18n.T(ctx, i18n.ASSIGN_BUTTON)})
 	}
 	if user.GroupId != 0 {
-		keyboard = append(keyboard, tgbotapi.KeyboardButton{Text: SUBMIT_KEYBOARD})
+		keyboard = append(keyboard, tgbotapi.KeyboardButton{Text: i18n.T(ctx, i18n.SUBMIT_BUTTON)})
 	}
 	if slices.Contains(user.Roles, entities.Admin) {
-		keyboard = append(keyboard, tgbotapi.KeyboardButton{Text: ADD_LABWORK_KEYBOARD})
+		keyboard = append(keyboard, tgbotapi.KeyboardButton{Text: i18n.T(ctx, i18n.ADD_LABWORK_BUTTON)})
 	}
 	msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(keyboard)
 	_, err := bot.SendCtx(ctx, msg)
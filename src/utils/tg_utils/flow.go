@@ -0,0 +1,198 @@
+package tgutils
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+)
+
+var (
+	ErrUnregisteredState = errors.New("state has no registered handler")
+	ErrUnknownState      = errors.New("transition to unknown state")
+	ErrUnreachableState  = errors.New("state is unreachable from idle state")
+)
+
+type flowState struct {
+	state   constants.State
+	handler MuxHandler
+	next    []constants.State
+}
+
+type flowCallback struct {
+	prefix  string
+	handler CallbackHandler
+	next    []constants.State
+}
+
+// Flow declares states of a single scenario, which start with common prefix (e.g. constants.QUEUE_STATES),
+// transitions between them and callbacks, which drive the scenario. Transitions are used only by Mux.Validate,
+// handlers still save the next state by themselves
+type Flow struct {
+	prefix    constants.State
+	states    []flowState
+	callbacks []flowCallback
+}
+
+func NewFlow(prefix constants.State) *Flow {
+	return &Flow{prefix: prefix}
+}
+
+// State declares state of the flow with its handler and states, which it can transition to on handling or reverting
+func (flow *Flow) State(state constants.State, handler MuxHandler, next ...constants.State) *Flow {
+	flow.states = append(flow.states, flowState{state: state, handler: handler, next: next})
+	return flow
+}
+
+// Callback declares callback handler of the flow with states, which it can transition to.
+// Callbacks are considered to be pressed only in chats, which are already in one of the states of the flow
+func (flow *Flow) Callback(prefix string, handler CallbackHandler, next ...constants.State) *Flow {
+	flow.callbacks = append(flow.callbacks, flowCallback{prefix: prefix, handler: handler, next: next})
+	return flow
+}
+
+// RegisterFlow registers states and callbacks of the flow. Call Validate after all flows are registered
+func (mux *Mux) RegisterFlow(flow *Flow) {
+	for _, state := range flow.states {
+		if state.handler != nil {
+			mux.RegisterRoute(state.state, state.handler)
+		}
+	}
+	for _, callback := range flow.callbacks {
+		mux.RegisterCallback(callback.prefix, callback.handler)
+	}
+	mux.flows = append(mux.flows, flow)
+	for _, state := range flow.states {
+		if state.handler != nil {
+			mux.declared[state.state] = true
+		}
+	}
+}
+
+// Validate checks registered flows, returning errors for states without handlers, transitions to states,
+// which aren't declared by any flow, and states, which can't be reached from idle state. States, which handlers
+// have saved since the start, are checked too, so calling it after handling updates catches undeclared transitions
+func (mux *Mux) Validate() error {
+	var errs []error
+	for _, flow := range mux.flows {
+		for _, state := range flow.states {
+			if !strings.HasPrefix(string(state.state), string(flow.prefix)) {
+				errs = append(errs, fmt.Errorf("%w: %s doesn't belong to flow %s", ErrUnknownState, state.state, flow.prefix))
+			}
+			if state.handler == nil {
+				errs = append(errs, fmt.Errorf("%w: %s", ErrUnregisteredState, state.state))
+			}
+		}
+	}
+	declared := mux.declared
+	for _, flow := range mux.flows {
+		for _, transition := range flow.transitions() {
+			if declared[transition.to] {
+				continue
+			}
+			if owner := mux.flowOf(transition.to); owner != nil {
+				errs = append(errs, fmt.Errorf("%w: %s of flow %s, reached from %s", ErrUnregisteredState, transition.to, owner.prefix, transition.from))
+			} else {
+				errs = append(errs, fmt.Errorf("%w: %s, reached from %s", ErrUnknownState, transition.to, transition.from))
+			}
+		}
+	}
+
+	mux.savedMu.Lock()
+	for state := range mux.saved {
+		if !declared[state] {
+			errs = append(errs, fmt.Errorf("%w: %s, saved by handler", ErrUnknownState, state))
+		}
+	}
+	mux.savedMu.Unlock()
+
+	reached := mux.reachableStates()
+	for _, flow := range mux.flows {
+		for _, state := range flow.states {
+			if state.handler != nil && !reached[state.state] {
+				errs = append(errs, fmt.Errorf("%w: %s", ErrUnreachableState, state.state))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// rememberSavedState remembers the state, which handler has saved, so that Validate checks it. Handler has already
+// committed its changes, so undeclared state is only logged here
+func (mux *Mux) rememberSavedState(state constants.State) {
+	if len(mux.flows) == 0 || state == "" {
+		return
+	}
+	mux.savedMu.Lock()
+	mux.saved[state] = true
+	mux.savedMu.Unlock()
+	if !mux.declared[state] {
+		slog.Warn("handler saved state, which isn't declared by any flow", "state", state)
+	}
+}
+
+type flowTransition struct {
+	from string
+	to   constants.State
+}
+
+func (flow *Flow) transitions() []flowTransition {
+	transitions := []flowTransition{}
+	for _, state := range flow.states {
+		for _, next := range state.next {
+			transitions = append(transitions, flowTransition{from: state.state.String(), to: next})
+		}
+	}
+	for _, callback := range flow.callbacks {
+		for _, next := range callback.next {
+			transitions = append(transitions, flowTransition{from: callback.prefix + " callback", to: next})
+		}
+	}
+	return transitions
+}
+
+// flowOf returns flow with the longest prefix of the state
+func (mux *Mux) flowOf(state constants.State) *Flow {
+	var owner *Flow
+	for _, flow := range mux.flows {
+		if strings.HasPrefix(string(state), string(flow.prefix)) && (owner == nil || len(flow.prefix) > len(owner.prefix)) {
+			owner = flow
+		}
+	}
+	return owner
+}
+
+// reachableStates walks transitions, starting from idle state. Transitions of callbacks are walked,
+// once any state of their flow is reached
+func (mux *Mux) reachableStates() map[constants.State]bool {
+	reached := map[constants.State]bool{constants.IDLE_STATE: true}
+	queue := []constants.State{constants.IDLE_STATE}
+	walkedCallbacks := map[*Flow]bool{}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		next := []constants.State{}
+		for _, flow := range mux.flows {
+			for _, declared := range flow.states {
+				if declared.state == state {
+					next = append(next, declared.next...)
+				}
+			}
+			if mux.flowOf(state) == flow && !walkedCallbacks[flow] {
+				walkedCallbacks[flow] = true
+				for _, callback := range flow.callbacks {
+					next = append(next, callback.next...)
+				}
+			}
+		}
+		for _, state := range next {
+			if !reached[state] {
+				reached[state] = true
+				queue = append(queue, state)
+			}
+		}
+	}
+	return reached
+}
@@ -0,0 +1,92 @@
+package tgutils
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// NormalizeCommand strips mention of the bot from the command of the message, e.g. /queue@bot becomes /queue,
+// so that commands from group chats are matched the same way as from private ones.
+// Returns false, if the command is addressed to another bot
+func NormalizeCommand(message *tgbotapi.Message, botName string) bool {
+	if !message.IsCommand() {
+		return true
+	}
+	command := message.CommandWithAt()
+	name, mention, found := strings.Cut(command, "@")
+	if !found {
+		return true
+	}
+	if !strings.EqualFold(mention, botName) {
+		return false
+	}
+	message.Text = "/" + name + message.Text[1+len(command):]
+	// Offsets of the entities are in utf-16 code units, but command and mention consist of ascii symbols only
+	shift := len(mention) + 1
+	entities := slices.Clone(message.Entities)
+	entities[0].Length -= shift
+	for i := 1; i < len(entities); i++ {
+		entities[i].Offset -= shift
+	}
+	message.Entities = entities
+	return true
+}
+
+// GroupChatMiddleware lets only allowed commands through in group chats, redirecting users to the private chat with the bot
+// for the rest of them. Messages of private chats are passed as is
+func GroupChatMiddleware(bot *Bot, allowed ...string) Middleware {
+	return func(next MuxHandler) MuxHandler {
+		return NewHandlerFunc(
+			func(ctx context.Context, message *tgbotapi.Message) error {
+				if message.Chat.IsPrivate() || !message.IsCommand() || slices.Contains(allowed, "/"+message.Command()) {
+					return next.Handle(ctx, message)
+				}
+				return redirectToPrivateChat(ctx, bot, message)
+			},
+			func(ctx context.Context, message *tgbotapi.Message) error {
+				// Reverts are also done by mux on flow expiration, which isn't caused by the command of the user
+				if message.Chat.IsPrivate() || !message.IsCommand() {
+					return next.Revert(ctx, message)
+				}
+				return redirectToPrivateChat(ctx, bot, message)
+			})
+	}
+}
+
+func redirectToPrivateChat(ctx context.Context, bot *Bot, message *tgbotapi.Message) error {
+	response := tgbotapi.NewMessage(message.Chat.ID, i18n.T(ctx, i18n.PRIVATE_ONLY_TEXT, "/"+message.Command()))
+	response.ReplyToMessageID = message.MessageID
+	response.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonURL(i18n.T(ctx, i18n.PRIVATE_CHAT_BUTTON), "https://t.me/"+bot.Self.UserName)))
+	_, err := bot.SendCtx(ctx, response)
+	if err != nil {
+		return fmt.Errorf("failed to send redirect to private chat: %w", err)
+	}
+	return nil
+}
+
+// rejectForeignCallback answers callbacks, pressed by members of group chats on inline keyboards of flows of other members.
+// Returns true, if the callback was rejected
+func (mux *Mux) rejectForeignCallback(ctx context.Context, update *tgbotapi.Update) (bool, error) {
+	query := update.CallbackQuery
+	if query.Message == nil || query.Message.Chat.IsPrivate() {
+		return false, nil
+	}
+	userId, err := mux.bot.FlowMarkupUser(ctx, query.Message.Chat.ID, query.Message.MessageID)
+	if err != nil {
+		return false, err
+	}
+	if userId == 0 || userId == query.From.ID {
+		return false, nil
+	}
+	_, err = mux.bot.RequestCtx(ctx, tgbotapi.NewCallback(query.ID, i18n.T(ctx, i18n.FOREIGN_CALLBACK_TEXT)))
+	if err != nil {
+		return true, fmt.Errorf("failed to answer foreign callback: %w", err)
+	}
+	return true, nil
+}
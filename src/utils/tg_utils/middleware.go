@@ -0,0 +1,155 @@
+package tgutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// LoggingMiddleware logs every handled message with time it took to handle it
+func LoggingMiddleware() Middleware {
+	return func(next MuxHandler) MuxHandler {
+		return NewHandlerFunc(
+			func(ctx context.Context, message *tgbotapi.Message) error {
+				start := time.Now()
+				err := next.Handle(ctx, message)
+				slog.Debug("handled message", "chat_id", message.Chat.ID, "text", message.Text, "duration", time.Since(start), "err", err)
+				return err
+			},
+			func(ctx context.Context, message *tgbotapi.Message) error {
+				start := time.Now()
+				err := next.Revert(ctx, message)
+				slog.Debug("reverted message", "chat_id", message.Chat.ID, "duration", time.Since(start), "err", err)
+				return err
+			})
+	}
+}
+
+// LoggingCallbackMiddleware logs every handled callback with time it took to handle it
+func LoggingCallbackMiddleware() CallbackMiddleware {
+	return func(next CallbackHandler) CallbackHandler {
+		return CallbackHandlerFunc(func(ctx context.Context, update *tgbotapi.Update, bot *Bot) error {
+			start := time.Now()
+			err := next.HandleCallback(ctx, update, bot)
+			slog.Debug("handled callback", "data", update.CallbackData(), "duration", time.Since(start), "err", err)
+			return err
+		})
+	}
+}
+
+// RecoverMiddleware converts panics of the next handlers into errors, so that they are handled the same way as regular ones
+func RecoverMiddleware() Middleware {
+	return func(next MuxHandler) MuxHandler {
+		return NewHandlerFunc(
+			func(ctx context.Context, message *tgbotapi.Message) (err error) {
+				defer recoverInto(&err)
+				return next.Handle(ctx, message)
+			},
+			func(ctx context.Context, message *tgbotapi.Message) (err error) {
+				defer recoverInto(&err)
+				return next.Revert(ctx, message)
+			})
+	}
+}
+
+// RecoverCallbackMiddleware is the same as RecoverMiddleware, but for callbacks
+func RecoverCallbackMiddleware() CallbackMiddleware {
+	return func(next CallbackHandler) CallbackHandler {
+		return CallbackHandlerFunc(func(ctx context.Context, update *tgbotapi.Update, bot *Bot) (err error) {
+			defer recoverInto(&err)
+			return next.HandleCallback(ctx, update, bot)
+		})
+	}
+}
+
+// PanicError is returned by recover middlewares, storing recovered value and stack of the panicked goroutine
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func NewPanicError(value any, stack []byte) *PanicError {
+	return &PanicError{Value: value, Stack: stack}
+}
+
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("recovered from panic: %v", err.Value)
+}
+
+func recoverInto(err *error) {
+	if r := recover(); r != nil {
+		*err = NewPanicError(r, debug.Stack())
+	}
+}
+
+type CallbackMarker interface {
+	MarkCallback(ctx context.Context, key string) (bool, error)
+	UnmarkCallback(ctx context.Context, key string) error
+}
+
+// CallbackKeyFunc returns the key, under which callback is marked as processed. Returning ErrCallbackProcessed
+// means the callback was already handled, even if it can't be keyed anymore
+type CallbackKeyFunc func(ctx context.Context, update *tgbotapi.Update) (string, error)
+
+var ErrCallbackProcessed = errors.New("callback was already processed")
+
+// OnceCallbackMiddleware lets only the first callback with the given key through. Is meant for terminal callbacks,
+// e.g. acceptance of the request, which mustn't be applied twice, even when request copies were sent to several admins.
+// If handling fails, the key is unmarked and callback can be pressed again
+func OnceCallbackMiddleware(marker CallbackMarker, keyOf CallbackKeyFunc) CallbackMiddleware {
+	return func(next CallbackHandler) CallbackHandler {
+		return CallbackHandlerFunc(func(ctx context.Context, update *tgbotapi.Update, bot *Bot) error {
+			key, err := keyOf(ctx, update)
+			if errors.Is(err, ErrCallbackProcessed) {
+				return answerProcessed(ctx, update, bot)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get key of the callback: %w", err)
+			}
+			if key == "" {
+				return next.HandleCallback(ctx, update, bot)
+			}
+			marked, err := marker.MarkCallback(ctx, key)
+			if err != nil {
+				return fmt.Errorf("failed to mark callback %s as processed: %w", key, err)
+			}
+			if !marked {
+				return answerProcessed(ctx, update, bot)
+			}
+			err = next.HandleCallback(ctx, update, bot)
+			if err != nil {
+				return errors.Join(err, marker.UnmarkCallback(ctx, key))
+			}
+			return nil
+		})
+	}
+}
+
+func answerProcessed(ctx context.Context, update *tgbotapi.Update, bot *Bot) error {
+	_, err := bot.RequestCtx(ctx, tgbotapi.NewCallback(update.CallbackQuery.ID, i18n.T(ctx, i18n.ALREADY_PROCESSED_TEXT)))
+	if err != nil {
+		return fmt.Errorf("failed to answer already processed callback: %w", err)
+	}
+	return nil
+}
+
+// CallbackMessageKey keys callback by the message its button belongs to
+func CallbackMessageKey(ctx context.Context, update *tgbotapi.Update) (string, error) {
+	query := update.CallbackQuery
+	if query == nil {
+		return "", nil
+	}
+	if query.Message != nil {
+		return fmt.Sprintf("%d:%d", query.Message.Chat.ID, query.Message.MessageID), nil
+	}
+	if query.InlineMessageID != "" {
+		return query.InlineMessageID, nil
+	}
+	return "", nil
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
 	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
@@ -46,60 +48,176 @@ func (f CallbackHandlerFunc) HandleCallback(ctx context.Context, update *tgbotap
 type Cache interface {
 	SaveState(context.Context, interfaces.CachedInfo) error
 	GetState(ctx context.Context, chatId int64) (*interfaces.CachedInfo, error)
+	RemoveInfo(ctx context.Context, chatId int64) error
+	GetStaleStates(ctx context.Context, before time.Time) ([]interfaces.CachedInfo, error)
 	AcquireLock(ctx context.Context, chatId int64, key string) *sync.Mutex
 	ReleaseLock(ctx context.Context, chatId int64, key string)
 }
 
+var ErrNoCallbackHandler = errors.New("no handler registered for callback")
+
+// Middleware wraps state handler, allowing to execute logic before and after handling (or to not call next handler at all)
+type Middleware func(next MuxHandler) MuxHandler
+
+// CallbackMiddleware is the same as Middleware, but for callback handlers
+type CallbackMiddleware func(next CallbackHandler) CallbackHandler
+
+type scopedMiddleware struct {
+	prefix     string
+	middleware Middleware
+}
+
+type scopedCallbackMiddleware struct {
+	prefix     string
+	middleware CallbackMiddleware
+}
+
 type Mux struct {
-	routes          datastructures.TrieNode[MuxHandler]
-	callbacks       datastructures.TrieNode[CallbackHandler]
-	cache           Cache
-	bot             *Bot
-	NotFoundHandler MuxHandler
+	routes              datastructures.TrieNode[MuxHandler]
+	callbacks           datastructures.TrieNode[CallbackHandler]
+	middlewares         []scopedMiddleware
+	callbackMiddlewares []scopedCallbackMiddleware
+	flows               []*Flow
+	declared            map[constants.State]bool
+	saved               map[constants.State]bool
+	savedMu             sync.Mutex
+	timeouts            datastructures.TrieNode[time.Duration]
+	minTimeout          time.Duration
+	cache               Cache
+	bot                 *Bot
+	NotFoundHandler     MuxHandler
+	// CancelHandler is called after the flow was reset by cancel command
+	CancelHandler MuxHandler
 }
 
 func NewMux(cache Cache, bot *Bot) *Mux {
-	return &Mux{cache: cache, bot: bot, routes: datastructures.NewTrieNode[MuxHandler](),
-		callbacks: datastructures.NewTrieNode[CallbackHandler](), 
+	return &Mux{cache: cache, bot: bot, declared: map[constants.State]bool{}, saved: map[constants.State]bool{}, routes: datastructures.NewTrieNode[MuxHandler](),
+		callbacks: datastructures.NewTrieNode[CallbackHandler](), timeouts: datastructures.NewTrieNode[time.Duration](),
 		NotFoundHandler: NewHandlerFunc(func(ctx context.Context, message *tgbotapi.Message) error { return errors.ErrUnsupported },
-		 func(ctx context.Context, message *tgbotapi.Message) error { return errors.ErrUnsupported })}
+		 func(ctx context.Context, message *tgbotapi.Message) error { return errors.ErrUnsupported }),
+		CancelHandler: NewHandlerFunc(func(ctx context.Context, message *tgbotapi.Message) error { return nil },
+			func(ctx context.Context, message *tgbotapi.Message) error { return nil })}
 }
 
 func (mu *Mux) RegisterRoute(state constants.State, handler MuxHandler) {
 	mu.routes.Insert(string(state), handler)
 }
 
+// Use registers middlewares, which are applied to handlers of every state. First registered middleware is the outermost one
+func (mux *Mux) Use(middlewares ...Middleware) {
+	mux.UseFor("", middlewares...)
+}
+
+// UseFor registers middlewares, which are applied only to handlers of states, starting with given prefix (e.g. constants.ADMIN_STATES)
+func (mux *Mux) UseFor(prefix constants.State, middlewares ...Middleware) {
+	for _, middleware := range middlewares {
+		mux.middlewares = append(mux.middlewares, scopedMiddleware{prefix: string(prefix), middleware: middleware})
+	}
+}
+
+// UseCallback registers middlewares, which are applied to every callback handler
+func (mux *Mux) UseCallback(middlewares ...CallbackMiddleware) {
+	mux.UseCallbackFor("", middlewares...)
+}
+
+// UseCallbackFor registers middlewares, which are applied only to callbacks, which data starts with given prefix
+func (mux *Mux) UseCallbackFor(prefix string, middlewares ...CallbackMiddleware) {
+	for _, middleware := range middlewares {
+		mux.callbackMiddlewares = append(mux.callbackMiddlewares, scopedCallbackMiddleware{prefix: prefix, middleware: middleware})
+	}
+}
+
+func (mux *Mux) wrap(state string, handler MuxHandler) MuxHandler {
+	for _, scoped := range slices.Backward(mux.middlewares) {
+		if strings.HasPrefix(state, scoped.prefix) {
+			handler = scoped.middleware(handler)
+		}
+	}
+	return handler
+}
+
+func (mux *Mux) wrapCallback(data string, handler CallbackHandler) CallbackHandler {
+	for _, scoped := range slices.Backward(mux.callbackMiddlewares) {
+		if strings.HasPrefix(data, scoped.prefix) {
+			handler = scoped.middleware(handler)
+		}
+	}
+	return handler
+}
+
 func (mux *Mux) Handle(ctx context.Context, message *tgbotapi.Message) error {
+	if !NormalizeCommand(message, mux.bot.Self.UserName) {
+		return nil
+	}
+	ctx = WithFlowChat(ctx, message.Chat.ID)
+	if message.From != nil {
+		ctx = interfaces.WithChatUser(ctx, message.Chat.ID, message.From.ID)
+		ctx = mux.bot.Localize(ctx, message.From.ID, message.From.LanguageCode)
+	}
+	if message.Command() == strings.Trim(constants.CANCEL_COMMAND, "/") {
+		return mux.cancel(ctx, message)
+	}
 	info, err := mux.cache.GetState(ctx, message.Chat.ID)
 	if err != nil {
 		return fmt.Errorf("couldn't get state in state machine: %w", err)
 	}
+	if mux.isExpired(info) {
+		err := mux.expire(ctx, info)
+		if err != nil {
+			return err
+		}
+		info = interfaces.NewCachedInfo(message.Chat.ID, constants.IDLE_STATE)
+	}
 	stateName := info.State()
+	handler := mux.wrap(stateName, mux.dispatcher(stateName))
 	if message.Command() == strings.Trim(constants.REVERT_COMMAND, "/") {
+		err = handler.Revert(ctx, message)
+	} else {
+		err = handler.Handle(ctx, message)
+	}
+	if err != nil {
+		return err
+	}
+	return mux.forgetFinishedFlow(ctx, message.Chat.ID)
+}
+
+// cancel aborts the whole flow from any state. Only global middlewares are applied, as the state doesn't matter
+func (mux *Mux) cancel(ctx context.Context, message *tgbotapi.Message) error {
+	return mux.wrap("", NewHandlerFunc(func(ctx context.Context, message *tgbotapi.Message) error {
+		err := mux.ResetFlow(ctx, message.Chat.ID)
+		if err != nil {
+			return fmt.Errorf("failed to reset flow during cancel command: %w", err)
+		}
+		return mux.CancelHandler.Handle(ctx, message)
+	}, mux.CancelHandler.Revert)).Handle(ctx, message)
+}
+
+// dispatcher returns handler, which calls every handler on the path to given state, so that middlewares are applied once per update
+func (mux *Mux) dispatcher(stateName string) MuxHandler {
+	if mux.routes.Search(stateName) == nil {
+		return mux.NotFoundHandler
+	}
+	return NewHandlerFunc(func(ctx context.Context, message *tgbotapi.Message) error {
 		for route := range mux.routes.Iterate(stateName) {
-			if route.IsLeaf() {
-				err := route.Val().Revert(ctx, message)
+			if route.Val() != nil {
+				err := route.Val().Handle(ctx, message)
 				if err != nil {
 					return err
 				}
 			}
 		}
 		return nil
-	}
-
-	if route := mux.routes.Search(stateName); route != nil {
+	}, func(ctx context.Context, message *tgbotapi.Message) error {
 		for route := range mux.routes.Iterate(stateName) {
-			if route.Val() != nil {
-				err := route.Val().Handle(ctx, message)
+			if route.IsLeaf() {
+				err := route.Val().Revert(ctx, message)
 				if err != nil {
 					return err
 				}
 			}
 		}
-	} else {
-		return mux.NotFoundHandler.Handle(ctx, message)
-	}
-	return nil
+		return nil
+	})
 }
 
 func (mux *Mux) Revert(ctx context.Context, message *tgbotapi.Message) error {
@@ -108,7 +226,11 @@ func (mux *Mux) Revert(ctx context.Context, message *tgbotapi.Message) error {
 		return fmt.Errorf("couldn't get state in state machine: %w", err)
 	}
 	stateName := info.State()
-	return mux.routes.Search(stateName).Revert(ctx, message)
+	handler := mux.routes.Search(stateName)
+	if handler == nil {
+		return mux.wrap(stateName, mux.NotFoundHandler).Revert(ctx, message)
+	}
+	return mux.wrap(stateName, handler).Revert(ctx, message)
 }
 
 func (mux *Mux) RegisterCallback(callbackName string, handler CallbackHandler) {
@@ -116,5 +238,32 @@ func (mux *Mux) RegisterCallback(callbackName string, handler CallbackHandler) {
 }
 
 func (mux *Mux) HandleCallback(ctx context.Context, update *tgbotapi.Update, bot *Bot) error {
-	return mux.callbacks.Search(update.CallbackData()).HandleCallback(ctx, update, bot)
+	data := update.CallbackData()
+	handler := mux.callbacks.Search(data)
+	if handler == nil {
+		return fmt.Errorf("%w: %s", ErrNoCallbackHandler, data)
+	}
+	if user := update.SentFrom(); user != nil {
+		ctx = mux.bot.Localize(ctx, user.ID, user.LanguageCode)
+	}
+	chat := update.FromChat()
+	if chat == nil {
+		return mux.wrapCallback(data, handler).HandleCallback(ctx, update, bot)
+	}
+
+	ctx = WithFlowChat(ctx, chat.ID)
+	ctx = interfaces.WithChatUser(ctx, chat.ID, update.SentFrom().ID)
+	foreign, err := mux.rejectForeignCallback(ctx, update)
+	if err != nil || foreign {
+		return err
+	}
+	expired, err := mux.expireCallbackFlow(ctx, update)
+	if err != nil || expired {
+		return err
+	}
+	err = mux.wrapCallback(data, handler).HandleCallback(ctx, update, bot)
+	if err != nil {
+		return err
+	}
+	return mux.forgetFinishedFlow(ctx, chat.ID)
 }
@@ -0,0 +1,384 @@
+package tgutils
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// RateLimits describes, how often messages can be sent. Every limit allows a burst of messages, after which
+// messages are sent once per interval
+type RateLimits struct {
+	GlobalInterval time.Duration
+	GlobalBurst    int
+	// Private chats are limited to about one message per second
+	ChatInterval time.Duration
+	ChatBurst    int
+	// Group chats are limited to 20 messages per minute
+	GroupInterval time.Duration
+	GroupBurst    int
+	// Transient failures (network errors, 5xx responses) are retried with exponential backoff, starting with RetryBackoff.
+	// Requests, which can't be safely repeated, are retried on network errors only if they didn't reach telegram
+	MaxRetries   int
+	RetryBackoff time.Duration
+}
+
+// DefaultRateLimits returns limits, documented by telegram: 30 messages per second overall,
+// one message per second in a private chat and 20 messages per minute in a group
+func DefaultRateLimits() RateLimits {
+	return RateLimits{
+		GlobalInterval: time.Second / 30,
+		GlobalBurst:    30,
+		ChatInterval:   time.Second,
+		ChatBurst:      3,
+		GroupInterval:  3 * time.Second,
+		GroupBurst:     3,
+		MaxRetries:     3,
+		RetryBackoff:   500 * time.Millisecond,
+	}
+}
+
+// limiter is a token bucket, which tracks the time, when the bucket is full again
+type limiter struct {
+	interval time.Duration
+	burst    int
+	full     time.Time
+	// blockedUntil is set by retry_after of 429 responses
+	blockedUntil time.Time
+}
+
+// reserve takes a token from the bucket, returning time to wait before using it
+func (l *limiter) reserve(now time.Time) time.Duration {
+	if l.full.Before(now) {
+		l.full = now
+	}
+	l.full = l.full.Add(l.interval)
+	wait := l.full.Sub(now) - time.Duration(l.burst)*l.interval
+	if blocked := l.blockedUntil.Sub(now); blocked > wait {
+		wait = blocked
+	}
+	return max(wait, 0)
+}
+
+// idle checks, whether the bucket is full, so the limiter can be dropped
+func (l *limiter) idle(now time.Time) bool {
+	return !l.full.After(now) && !l.blockedUntil.After(now)
+}
+
+func (l *limiter) block(until time.Time) {
+	if until.After(l.blockedUntil) {
+		l.blockedUntil = until
+	}
+}
+
+type sendResult struct {
+	msg tgbotapi.Message
+	err error
+}
+
+type sendJob struct {
+	ctx  context.Context
+	send func() (tgbotapi.Message, error)
+	// idempotent jobs can be repeated after network errors, even if telegram could've handled the first attempt
+	idempotent bool
+	result     chan sendResult
+}
+
+type chatQueue struct {
+	jobs    []*sendJob
+	limiter *limiter
+}
+
+// Scheduler sends messages, respecting global and per-chat rate limits. Messages to the same chat are sent one by one
+// in the order of scheduling, so that retries don't reorder them
+type Scheduler struct {
+	mu     sync.Mutex
+	limits RateLimits
+	global *limiter
+	chats  map[int64]*chatQueue
+	// Limiters outlive queues of the chats, which are removed, once they are empty
+	limiters map[int64]*limiter
+}
+
+func NewScheduler(limits RateLimits) *Scheduler {
+	return &Scheduler{
+		limits:   limits,
+		global:   &limiter{interval: limits.GlobalInterval, burst: limits.GlobalBurst},
+		chats:    map[int64]*chatQueue{},
+		limiters: map[int64]*limiter{},
+	}
+}
+
+// Send schedules send to the chat and waits for its result. Requests, which aren't bound to any chat, use zero chat id.
+// They aren't limited like messages, so they are sent right away and concurrently
+func (scheduler *Scheduler) Send(ctx context.Context, chatId int64, send func() (tgbotapi.Message, error)) (tgbotapi.Message, error) {
+	return scheduler.schedule(&sendJob{ctx: ctx, send: send, result: make(chan sendResult, 1)}, chatId)
+}
+
+// SendIdempotent is Send of the request, which can be repeated without side effects, e.g. edit of the message,
+// so that it is retried on any network error
+func (scheduler *Scheduler) SendIdempotent(ctx context.Context, chatId int64, send func() (tgbotapi.Message, error)) (tgbotapi.Message, error) {
+	return scheduler.schedule(&sendJob{ctx: ctx, send: send, idempotent: true, result: make(chan sendResult, 1)}, chatId)
+}
+
+func (scheduler *Scheduler) schedule(job *sendJob, chatId int64) (tgbotapi.Message, error) {
+	if chatId == 0 {
+		return scheduler.process(chatId, nil, job)
+	}
+	scheduler.mu.Lock()
+	queue, ok := scheduler.chats[chatId]
+	if !ok {
+		queue = &chatQueue{limiter: scheduler.chatLimiter(chatId)}
+		scheduler.chats[chatId] = queue
+	}
+	queue.jobs = append(queue.jobs, job)
+	if len(queue.jobs) == 1 {
+		go scheduler.run(chatId, queue)
+	}
+	scheduler.mu.Unlock()
+
+	select {
+	case res := <-job.result:
+		return res.msg, res.err
+	case <-job.ctx.Done():
+		return tgbotapi.Message{}, job.ctx.Err()
+	}
+}
+
+func (scheduler *Scheduler) chatLimiter(chatId int64) *limiter {
+	if l, ok := scheduler.limiters[chatId]; ok {
+		return l
+	}
+	l := &limiter{interval: scheduler.limits.ChatInterval, burst: scheduler.limits.ChatBurst}
+	if chatId < 0 {
+		l = &limiter{interval: scheduler.limits.GroupInterval, burst: scheduler.limits.GroupBurst}
+	}
+	scheduler.limiters[chatId] = l
+	return l
+}
+
+// dropIdleLimiters forgets limiters of chats without queued messages, which buckets are already full
+func (scheduler *Scheduler) dropIdleLimiters(now time.Time) {
+	for chatId, l := range scheduler.limiters {
+		if _, queued := scheduler.chats[chatId]; !queued && l.idle(now) {
+			delete(scheduler.limiters, chatId)
+		}
+	}
+}
+
+// run sends jobs of the chat until its queue is empty
+func (scheduler *Scheduler) run(chatId int64, queue *chatQueue) {
+	for {
+		scheduler.mu.Lock()
+		job := queue.jobs[0]
+		scheduler.mu.Unlock()
+
+		msg, err := scheduler.process(chatId, queue.limiter, job)
+		job.result <- sendResult{msg: msg, err: err}
+
+		scheduler.mu.Lock()
+		queue.jobs = queue.jobs[1:]
+		if len(queue.jobs) == 0 {
+			delete(scheduler.chats, chatId)
+			scheduler.dropIdleLimiters(time.Now())
+			scheduler.mu.Unlock()
+			return
+		}
+		scheduler.mu.Unlock()
+	}
+}
+
+// process sends the job, retrying its failures. Jobs without chat limiter aren't bound to any chat, so they don't wait for limits
+func (scheduler *Scheduler) process(chatId int64, chat *limiter, job *sendJob) (tgbotapi.Message, error) {
+	backoff := scheduler.limits.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		if chat != nil {
+			err := scheduler.wait(job.ctx, chat)
+			if err != nil {
+				return tgbotapi.Message{}, err
+			}
+		}
+		msg, err := job.send()
+		if err == nil {
+			return msg, nil
+		}
+		if attempt >= scheduler.limits.MaxRetries {
+			return msg, err
+		}
+		if retryAfter, ok := RetryAfter(err); ok {
+			slog.Warn("telegram flood limit is hit", "chat_id", chatId, "retry_after", retryAfter)
+			// Requests, which aren't bound to any chat, don't hold back messages, so only the request itself waits
+			if chat == nil {
+				err = sleepCtx(job.ctx, retryAfter)
+				if err != nil {
+					return tgbotapi.Message{}, err
+				}
+				continue
+			}
+			scheduler.mu.Lock()
+			chat.block(time.Now().Add(retryAfter))
+			scheduler.mu.Unlock()
+			continue
+		}
+		if !job.canRetry(err) {
+			return msg, err
+		}
+		err = sleepCtx(job.ctx, backoff)
+		if err != nil {
+			return tgbotapi.Message{}, err
+		}
+		backoff *= 2
+	}
+}
+
+// wait blocks until both limiter of the chat and global one allow to send a message
+func (scheduler *Scheduler) wait(ctx context.Context, chat *limiter) error {
+	scheduler.mu.Lock()
+	wait := chat.reserve(time.Now())
+	scheduler.mu.Unlock()
+	err := sleepCtx(ctx, wait)
+	if err != nil {
+		return err
+	}
+
+	scheduler.mu.Lock()
+	wait = scheduler.global.reserve(time.Now())
+	scheduler.mu.Unlock()
+	return sleepCtx(ctx, wait)
+}
+
+func sleepCtx(ctx context.Context, duration time.Duration) error {
+	if duration <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RetryAfter returns time to wait before the next request, if telegram responded with 429 status
+func RetryAfter(err error) (time.Duration, bool) {
+	var tgErr *tgbotapi.Error
+	if !errors.As(err, &tgErr) || tgErr.Code != http.StatusTooManyRequests {
+		return 0, false
+	}
+	return time.Duration(tgErr.RetryAfter) * time.Second, true
+}
+
+// IsTransient checks, whether request can succeed, if it is sent again
+func IsTransient(err error) bool {
+	var tgErr *tgbotapi.Error
+	if errors.As(err, &tgErr) {
+		return tgErr.Code >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// canRetry checks, whether the job can be sent again after transient failure. After network errors it's unknown,
+// whether telegram has handled the request, so other jobs are retried only if connection wasn't even established
+func (job *sendJob) canRetry(err error) bool {
+	if !IsTransient(err) {
+		return false
+	}
+	var tgErr *tgbotapi.Error
+	if job.idempotent || errors.As(err, &tgErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// isIdempotent checks, whether request can be repeated without side effects. Pointers to configs must be dereferenced first
+func isIdempotent(c tgbotapi.Chattable) bool {
+	switch c.(type) {
+	case tgbotapi.EditMessageTextConfig, tgbotapi.EditMessageReplyMarkupConfig, tgbotapi.EditMessageCaptionConfig,
+		tgbotapi.EditMessageMediaConfig, tgbotapi.DeleteMessageConfig, tgbotapi.CallbackConfig, tgbotapi.InlineConfig,
+		tgbotapi.PinChatMessageConfig, tgbotapi.UnpinChatMessageConfig, tgbotapi.SetMyCommandsConfig, tgbotapi.DeleteMyCommandsConfig,
+		tgbotapi.FileConfig, tgbotapi.WebhookConfig, tgbotapi.DeleteWebhookConfig, tgbotapi.ChatActionConfig:
+		return true
+	}
+	return false
+}
+
+// chatOf returns the chat, which request is sent to, or zero, if it isn't bound to any chat. Pointers to configs
+// must be dereferenced with chattableValue first
+func chatOf(c tgbotapi.Chattable) int64 {
+	switch msg := c.(type) {
+	case tgbotapi.MessageConfig:
+		return msg.ChatID
+	case tgbotapi.ForwardConfig:
+		return msg.ChatID
+	case tgbotapi.CopyMessageConfig:
+		return msg.ChatID
+	case tgbotapi.PhotoConfig:
+		return msg.ChatID
+	case tgbotapi.AudioConfig:
+		return msg.ChatID
+	case tgbotapi.DocumentConfig:
+		return msg.ChatID
+	case tgbotapi.StickerConfig:
+		return msg.ChatID
+	case tgbotapi.VideoConfig:
+		return msg.ChatID
+	case tgbotapi.AnimationConfig:
+		return msg.ChatID
+	case tgbotapi.VideoNoteConfig:
+		return msg.ChatID
+	case tgbotapi.VoiceConfig:
+		return msg.ChatID
+	case tgbotapi.LocationConfig:
+		return msg.ChatID
+	case tgbotapi.VenueConfig:
+		return msg.ChatID
+	case tgbotapi.ContactConfig:
+		return msg.ChatID
+	case tgbotapi.SendPollConfig:
+		return msg.ChatID
+	case tgbotapi.DiceConfig:
+		return msg.ChatID
+	case tgbotapi.ChatActionConfig:
+		return msg.ChatID
+	case tgbotapi.MediaGroupConfig:
+		return msg.ChatID
+	case tgbotapi.EditMessageTextConfig:
+		return msg.ChatID
+	case tgbotapi.EditMessageReplyMarkupConfig:
+		return msg.ChatID
+	case tgbotapi.EditMessageCaptionConfig:
+		return msg.ChatID
+	case tgbotapi.EditMessageMediaConfig:
+		return msg.ChatID
+	case tgbotapi.DeleteMessageConfig:
+		return msg.ChatID
+	case tgbotapi.PinChatMessageConfig:
+		return msg.ChatID
+	case tgbotapi.UnpinChatMessageConfig:
+		return msg.ChatID
+	}
+	return 0
+}
+
+// chattableValue dereferences pointer to config, so that type switches over configs match it
+func chattableValue(c tgbotapi.Chattable) tgbotapi.Chattable {
+	value := reflect.ValueOf(c)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return c
+	}
+	if elem, ok := value.Elem().Interface().(tgbotapi.Chattable); ok {
+		return elem
+	}
+	return c
+}
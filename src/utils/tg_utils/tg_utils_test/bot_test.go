@@ -0,0 +1,55 @@
+package tgutilstest
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	fakeapi "github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/fake_api"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Handlers pass pointers to configs as often as values, so they must be limited and split the same way
+func TestPointerConfigsAreScheduled(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+	limits := testLimits()
+	limits.ChatInterval = 50 * time.Millisecond
+	bot, err := server.NewBot(tgutils.WithRateLimits(limits))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const chatId = 5
+	msg := tgbotapi.NewMessage(chatId, strings.Repeat("line\n", 1000))
+	photo := tgbotapi.NewPhoto(chatId, tgbotapi.FileBytes{Name: "photo.png", Bytes: []byte("photo")})
+	video := tgbotapi.NewVideo(chatId, tgbotapi.FileBytes{Name: "video.mp4", Bytes: []byte("video")})
+	start := time.Now()
+	for _, c := range []tgbotapi.Chattable{&msg, &photo, &video} {
+		_, err := bot.SendCtx(context.Background(), c)
+		if err != nil {
+			t.Fatalf("failed to send %T: %v", c, err)
+		}
+	}
+	_, err = bot.Request(&tgbotapi.PinChatMessageConfig{ChatID: chatId, MessageID: 1})
+	if err != nil {
+		t.Fatalf("failed to pin message: %v", err)
+	}
+
+	if elapsed, want := time.Since(start), 3*limits.ChatInterval; elapsed < want {
+		t.Errorf("5 requests to the chat were sent in %s, want at least %s", elapsed, want)
+	}
+	methods := []string{}
+	for _, call := range server.Calls() {
+		if call.ChatId() == chatId {
+			methods = append(methods, call.Method)
+		}
+	}
+	want := []string{"sendMessage", "sendMessage", "sendPhoto", "sendVideo", "pinChatMessage"}
+	if !slices.Equal(methods, want) {
+		t.Errorf("requests to the chat are %v, want %v", methods, want)
+	}
+}
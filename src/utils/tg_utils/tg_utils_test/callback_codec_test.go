@@ -0,0 +1,70 @@
+package tgutilstest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+)
+
+type testCallback struct {
+	Id       int64
+	Date     time.Time
+	Subgroup int8
+	Accepted bool
+	Name     string
+	skipped  string
+}
+
+func TestCallbackCodec(t *testing.T) {
+	codec := tgutils.NewCallbackCodec[testCallback]("test", 1)
+	value := testCallback{Id: 42, Date: time.Unix(1760000000, 0), Subgroup: 2, Accepted: true, Name: "name", skipped: "skipped"}
+
+	data, err := codec.Encode(value)
+	if err != nil {
+		t.Fatalf("Encode(%v) returned error: %v", value, err)
+	}
+	if want := "test|v1|42|1760000000|2|1|name"; data != want {
+		t.Errorf("Encode(%v) = %s, want %s", value, data, want)
+	}
+	decoded, err := codec.Decode(data)
+	if err != nil {
+		t.Fatalf("Decode(%s) returned error: %v", data, err)
+	}
+	value.skipped = ""
+	if !decoded.Date.Equal(value.Date) || decoded.Id != value.Id || decoded.Subgroup != value.Subgroup ||
+		decoded.Accepted != value.Accepted || decoded.Name != value.Name || decoded.skipped != "" {
+		t.Errorf("Decode(%s) = %v, want %v", data, *decoded, value)
+	}
+
+	errorCases := []struct {
+		data string
+		want error
+	}{
+		{"other|v1|42|1760000000|2|1|name", tgutils.ErrCallbackPrefix},
+		{"test_other|v1|42|1760000000|2|1|name", tgutils.ErrCallbackPrefix},
+		{"test|v2|42|1760000000|2|1|name", tgutils.ErrCallbackVersion},
+		{"test|42|1760000000|2|1|name", tgutils.ErrCallbackVersion},
+		{"test|v1|42|1760000000|2|1", tgutils.ErrCallbackMalformed},
+		{"test|v1|abc|1760000000|2|1|name", tgutils.ErrCallbackMalformed},
+		{"test|v1|42|1760000000|200|1|name", tgutils.ErrCallbackMalformed},
+		{"test|v1|42|1760000000|2|yes|name", tgutils.ErrCallbackMalformed},
+	}
+	for _, errorCase := range errorCases {
+		_, err := codec.Decode(errorCase.data)
+		if !errors.Is(err, errorCase.want) {
+			t.Errorf("Decode(%s) returned error %v, want %v", errorCase.data, err, errorCase.want)
+		}
+	}
+
+	_, err = codec.Encode(testCallback{Name: "with|separator"})
+	if err == nil {
+		t.Errorf("Encode of string with separator returned no error")
+	}
+	_, err = codec.Encode(testCallback{Name: strings.Repeat("a", 64)})
+	if !errors.Is(err, tgutils.ErrCallbackDataTooLong) {
+		t.Errorf("Encode of too long value returned error %v, want %v", err, tgutils.ErrCallbackDataTooLong)
+	}
+}
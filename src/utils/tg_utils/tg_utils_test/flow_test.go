@@ -0,0 +1,150 @@
+package tgutilstest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+var noopHandler = tgutils.NewHandlerFunc(func(ctx context.Context, message *tgbotapi.Message) error { return nil },
+	func(ctx context.Context, message *tgbotapi.Message) error { return nil })
+
+var noopCallback = tgutils.CallbackHandlerFunc(func(ctx context.Context, update *tgbotapi.Update, bot *tgutils.Bot) error { return nil })
+
+func queueFlow() *tgutils.Flow {
+	return tgutils.NewFlow(constants.QUEUE_STATES).
+		State(constants.QUEUE_START_STATE, noopHandler, constants.IDLE_STATE).
+		Callback(constants.QUEUE_CALLBACKS, noopCallback, constants.QUEUE_WAITING_STATE).
+		State(constants.QUEUE_WAITING_STATE, noopHandler, constants.IDLE_STATE)
+}
+
+func TestFlowValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		flows []*tgutils.Flow
+		want  error
+	}{
+		{
+			name: "valid",
+			flows: []*tgutils.Flow{
+				tgutils.NewFlow(constants.IDLE_STATE).State(constants.IDLE_STATE, noopHandler, constants.QUEUE_START_STATE),
+				queueFlow(),
+			},
+		},
+		{
+			name: "unregistered flow",
+			flows: []*tgutils.Flow{
+				tgutils.NewFlow(constants.IDLE_STATE).State(constants.IDLE_STATE, noopHandler, constants.QUEUE_START_STATE),
+			},
+			want: tgutils.ErrUnknownState,
+		},
+		{
+			name: "unregistered state",
+			flows: []*tgutils.Flow{
+				tgutils.NewFlow(constants.IDLE_STATE).State(constants.IDLE_STATE, noopHandler, constants.QUEUE_START_STATE),
+				tgutils.NewFlow(constants.QUEUE_STATES).State(constants.QUEUE_START_STATE, noopHandler, constants.QUEUE_WAITING_STATE),
+			},
+			want: tgutils.ErrUnregisteredState,
+		},
+		{
+			name: "state without handler",
+			flows: []*tgutils.Flow{
+				tgutils.NewFlow(constants.IDLE_STATE).State(constants.IDLE_STATE, noopHandler, constants.QUEUE_START_STATE),
+				tgutils.NewFlow(constants.QUEUE_STATES).State(constants.QUEUE_START_STATE, nil),
+			},
+			want: tgutils.ErrUnregisteredState,
+		},
+		{
+			name: "unreachable",
+			flows: []*tgutils.Flow{
+				tgutils.NewFlow(constants.IDLE_STATE).State(constants.IDLE_STATE, noopHandler),
+				queueFlow(),
+			},
+			want: tgutils.ErrUnreachableState,
+		},
+	}
+	for _, test := range tests {
+		mux := tgutils.NewMux(nil, nil)
+		for _, flow := range test.flows {
+			mux.RegisterFlow(flow)
+		}
+		err := mux.Validate()
+		if test.want == nil && err != nil {
+			t.Errorf("%s: Validate() returned error: %v", test.name, err)
+		}
+		if test.want != nil && !errors.Is(err, test.want) {
+			t.Errorf("%s: Validate() = %v, want %v", test.name, err, test.want)
+		}
+	}
+}
+
+type memoryCache struct {
+	mu     sync.Mutex
+	states map[int64]interfaces.CachedInfo
+}
+
+func (cache *memoryCache) SaveState(ctx context.Context, info interfaces.CachedInfo) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.states[info.ChatId()] = info
+	return nil
+}
+
+func (cache *memoryCache) GetState(ctx context.Context, chatId int64) (*interfaces.CachedInfo, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	info, ok := cache.states[chatId]
+	if !ok {
+		return interfaces.NewCachedInfo(chatId, constants.IDLE_STATE), nil
+	}
+	return &info, nil
+}
+
+func (cache *memoryCache) RemoveInfo(ctx context.Context, chatId int64) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	delete(cache.states, chatId)
+	return nil
+}
+
+func (cache *memoryCache) GetStaleStates(ctx context.Context, before time.Time) ([]interfaces.CachedInfo, error) {
+	return nil, nil
+}
+
+func (cache *memoryCache) AcquireLock(ctx context.Context, chatId int64, key string) *sync.Mutex {
+	return &sync.Mutex{}
+}
+
+func (cache *memoryCache) ReleaseLock(ctx context.Context, chatId int64, key string) {}
+
+func TestUndeclaredSavedState(t *testing.T) {
+	cache := &memoryCache{states: map[int64]interfaces.CachedInfo{}}
+	mux := tgutils.NewMux(cache, tgutils.NewBot(&tgbotapi.BotAPI{}))
+	// Handler of idle state saves the state, which isn't declared as its transition
+	idle := tgutils.NewHandlerFunc(func(ctx context.Context, message *tgbotapi.Message) error {
+		return cache.SaveState(ctx, *interfaces.NewCachedInfo(message.Chat.ID, constants.SETTINGS_START_STATE))
+	}, noopHandler.Revert)
+	mux.RegisterFlow(tgutils.NewFlow(constants.IDLE_STATE).State(constants.IDLE_STATE, idle, constants.QUEUE_START_STATE))
+	mux.RegisterFlow(queueFlow())
+	err := mux.Validate()
+	if err != nil {
+		t.Fatalf("Validate() before handling returned error: %v", err)
+	}
+
+	// Handler has already done its work, so the update doesn't fail
+	err = mux.Handle(context.Background(), &tgbotapi.Message{Text: "hi", Chat: &tgbotapi.Chat{ID: 1}})
+	if err != nil {
+		t.Errorf("Handle() returned error: %v", err)
+	}
+	err = mux.Validate()
+	if !errors.Is(err, tgutils.ErrUnknownState) {
+		t.Errorf("Validate() after handling = %v, want %v", err, tgutils.ErrUnknownState)
+	}
+}
@@ -0,0 +1,24 @@
+package tgutilstest
+
+import (
+	"slices"
+	"testing"
+
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+)
+
+func TestOwners(t *testing.T) {
+	t.Setenv("OWNERS", "100, 200 ,x,,300")
+	want := []int64{100, 200, 300}
+	if owners := tgutils.GetOwners(); !slices.Equal(owners, want) {
+		t.Errorf("GetOwners() = %v, want %v", owners, want)
+	}
+	for _, owner := range want {
+		if !tgutils.IsOwner(owner) {
+			t.Errorf("IsOwner(%d) = false, want true", owner)
+		}
+	}
+	if tgutils.IsOwner(400) {
+		t.Errorf("IsOwner(400) = true, want false")
+	}
+}
@@ -0,0 +1,187 @@
+package tgutilstest
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func testLimits() tgutils.RateLimits {
+	return tgutils.RateLimits{
+		GlobalInterval: time.Millisecond,
+		GlobalBurst:    100,
+		ChatInterval:   20 * time.Millisecond,
+		ChatBurst:      1,
+		GroupInterval:  20 * time.Millisecond,
+		GroupBurst:     1,
+		MaxRetries:     3,
+		RetryBackoff:   time.Millisecond,
+	}
+}
+
+func TestSchedulerPreservesChatOrderOnRetries(t *testing.T) {
+	scheduler := tgutils.NewScheduler(testLimits())
+	var (
+		mu       sync.Mutex
+		sent     []int
+		failures = map[int]int{0: 2}
+	)
+	var wg sync.WaitGroup
+	for i := range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := scheduler.Send(context.Background(), 1, func() (tgbotapi.Message, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				if failures[i] > 0 {
+					failures[i]--
+					return tgbotapi.Message{}, &tgbotapi.Error{Code: http.StatusBadGateway, Message: "Bad Gateway"}
+				}
+				sent = append(sent, i)
+				return tgbotapi.Message{MessageID: i}, nil
+			})
+			if err != nil {
+				t.Errorf("Send(%d) returned error: %v", i, err)
+			}
+		}()
+		// Jobs are queued in the order of Send calls
+		time.Sleep(5 * time.Millisecond)
+	}
+	wg.Wait()
+	if len(sent) != 3 || sent[0] != 0 || sent[1] != 1 || sent[2] != 2 {
+		t.Errorf("messages were sent in order %v, want [0 1 2]", sent)
+	}
+}
+
+func TestSchedulerHonorsRetryAfter(t *testing.T) {
+	scheduler := tgutils.NewScheduler(testLimits())
+	attempts := []time.Time{}
+	_, err := scheduler.Send(context.Background(), -1, func() (tgbotapi.Message, error) {
+		attempts = append(attempts, time.Now())
+		if len(attempts) == 1 {
+			return tgbotapi.Message{}, &tgbotapi.Error{Code: http.StatusTooManyRequests, Message: "Too Many Requests",
+				ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 1}}
+		}
+		return tgbotapi.Message{}, nil
+	})
+	if err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+	if len(attempts) != 2 {
+		t.Fatalf("message was sent %d times, want 2", len(attempts))
+	}
+	if waited := attempts[1].Sub(attempts[0]); waited < time.Second {
+		t.Errorf("message was resent after %s, want at least retry_after of 1s", waited)
+	}
+}
+
+func TestSchedulerDoesNotRetryPermanentErrors(t *testing.T) {
+	scheduler := tgutils.NewScheduler(testLimits())
+	attempts := 0
+	_, err := scheduler.Send(context.Background(), 1, func() (tgbotapi.Message, error) {
+		attempts++
+		return tgbotapi.Message{}, &tgbotapi.Error{Code: http.StatusBadRequest, Message: "Bad Request: chat not found"}
+	})
+	var tgErr *tgbotapi.Error
+	if !errors.As(err, &tgErr) || tgErr.Code != http.StatusBadRequest {
+		t.Errorf("Send returned %v, want bad request error", err)
+	}
+	if attempts != 1 {
+		t.Errorf("message was sent %d times, want 1", attempts)
+	}
+}
+
+func TestSchedulerLimitsChatRate(t *testing.T) {
+	limits := testLimits()
+	scheduler := tgutils.NewScheduler(limits)
+	start := time.Now()
+	for range 4 {
+		_, err := scheduler.Send(context.Background(), 1, func() (tgbotapi.Message, error) { return tgbotapi.Message{}, nil })
+		if err != nil {
+			t.Fatalf("Send returned error: %v", err)
+		}
+	}
+	if elapsed, want := time.Since(start), 3*limits.ChatInterval; elapsed < want {
+		t.Errorf("4 messages were sent in %s, want at least %s", elapsed, want)
+	}
+}
+
+func TestSchedulerSendsUnboundRequestsRightAway(t *testing.T) {
+	limits := testLimits()
+	limits.ChatInterval = time.Hour
+	scheduler := tgutils.NewScheduler(limits)
+	// The first message uses up the burst of the chat, so that the second one waits for an hour
+	_, err := scheduler.Send(context.Background(), 1, func() (tgbotapi.Message, error) { return tgbotapi.Message{}, nil })
+	if err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go scheduler.Send(ctx, 1, func() (tgbotapi.Message, error) { return tgbotapi.Message{}, nil })
+
+	flooded := false
+	_, err = scheduler.SendIdempotent(context.Background(), 0, func() (tgbotapi.Message, error) {
+		if !flooded {
+			flooded = true
+			return tgbotapi.Message{}, &tgbotapi.Error{Code: http.StatusTooManyRequests, Message: "Too Many Requests",
+				ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 1}}
+		}
+		return tgbotapi.Message{}, nil
+	})
+	if err != nil {
+		t.Fatalf("SendIdempotent returned error: %v", err)
+	}
+
+	start := time.Now()
+	_, err = scheduler.Send(context.Background(), 2, func() (tgbotapi.Message, error) { return tgbotapi.Message{}, nil })
+	if err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("message to another chat was sent after %s, want flood limit of unbound request to not block it", elapsed)
+	}
+}
+
+func TestSchedulerRetriesNetworkErrorsOfIdempotentRequests(t *testing.T) {
+	timeout := &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}
+	tests := []struct {
+		name       string
+		idempotent bool
+		err        error
+		want       int
+	}{
+		{name: "timeout of message", err: timeout, want: 1},
+		{name: "timeout of edit", idempotent: true, err: timeout, want: 2},
+		{name: "refused connection", err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, want: 2},
+	}
+	for _, test := range tests {
+		scheduler := tgutils.NewScheduler(testLimits())
+		attempts := 0
+		send := func() (tgbotapi.Message, error) {
+			attempts++
+			if attempts == 1 {
+				return tgbotapi.Message{}, &url.Error{Op: "Post", URL: "https://api.telegram.org", Err: test.err}
+			}
+			return tgbotapi.Message{}, nil
+		}
+		if test.idempotent {
+			scheduler.SendIdempotent(context.Background(), 1, send)
+		} else {
+			scheduler.Send(context.Background(), 1, send)
+		}
+		if attempts != test.want {
+			t.Errorf("%s: request was sent %d times, want %d", test.name, attempts, test.want)
+		}
+	}
+}
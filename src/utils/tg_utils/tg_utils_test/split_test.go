@@ -0,0 +1,39 @@
+package tgutilstest
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+)
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  []string
+	}{
+		{name: "short", text: "abc\ndef", limit: 10, want: []string{"abc\ndef"}},
+		{name: "lines", text: "abc\ndef\ngh", limit: 8, want: []string{"abc\ndef\n", "gh"}},
+		{name: "long line", text: "ab\ncdefg\nh", limit: 3, want: []string{"ab\n", "cde", "fg\n", "h"}},
+		{name: "line of limit", text: "ab\ncdefgh\ni", limit: 3, want: []string{"ab\n", "cde", "fgh", "i"}},
+		{name: "runes", text: "абвгд", limit: 2, want: []string{"аб", "вг", "д"}},
+	}
+	for _, test := range tests {
+		got := tgutils.SplitText(test.text, test.limit)
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%s: SplitText(%q, %d) = %q, want %q", test.name, test.text, test.limit, got, test.want)
+		}
+		// Only newlines, which end the parts, can be dropped
+		if strings.ReplaceAll(strings.Join(got, ""), "\n", "") != strings.ReplaceAll(test.text, "\n", "") {
+			t.Errorf("%s: parts don't add up to the text", test.name)
+		}
+		for _, part := range got {
+			if strings.TrimSpace(part) == "" {
+				t.Errorf("%s: blank part %q", test.name, part)
+			}
+		}
+	}
+}
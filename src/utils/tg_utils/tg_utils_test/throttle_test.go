@@ -0,0 +1,97 @@
+package tgutilstest
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	tgutils "github.com/aCrYoZPS/bsuir_queue_bot/src/utils/tg_utils"
+)
+
+func TestThrottleCoalescesJobs(t *testing.T) {
+	throttle := tgutils.NewThrottle(50 * time.Millisecond)
+	var (
+		mu   sync.Mutex
+		runs = map[int64][]int{}
+	)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	for i := range 3 {
+		throttle.Do(1, func() {
+			mu.Lock()
+			defer mu.Unlock()
+			runs[1] = append(runs[1], i)
+			if i == 2 {
+				wg.Done()
+			}
+		})
+	}
+	throttle.Do(2, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		runs[2] = append(runs[2], 10)
+		wg.Done()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("throttled job didn't run")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	// The first job runs immediately, the second one is replaced by the third, other keys aren't throttled
+	if !slices.Equal(runs[1], []int{0, 2}) || !slices.Equal(runs[2], []int{10}) {
+		t.Fatalf("runs are %v, want 0 and 2 of the first key and 10 of the second one", runs)
+	}
+}
+
+func TestThrottleRunsJobsAsynchronously(t *testing.T) {
+	throttle := tgutils.NewThrottle(0)
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	started := make(chan struct{})
+	throttle.Do(1, func() {
+		close(started)
+		<-release
+	})
+	// The job of the same key waits for the running one, even though the interval has passed
+	throttle.Do(1, func() { close(finished) })
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("job didn't start")
+	}
+	select {
+	case <-finished:
+		t.Fatal("job of the key ran along with the running one")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("pending job didn't run after the running one")
+	}
+}
+
+func TestThrottleEvictsKeys(t *testing.T) {
+	throttle := tgutils.NewThrottle(10 * time.Millisecond)
+	for key := range int64(100) {
+		throttle.Do(key, func() {})
+	}
+	deadline := time.Now().Add(time.Second)
+	for throttle.Len() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("throttle remembers %d keys after their interval", throttle.Len())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
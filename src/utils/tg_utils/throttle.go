@@ -0,0 +1,89 @@
+package tgutils
+
+import (
+	"sync"
+	"time"
+)
+
+// Throttle runs jobs of the same key at most once per interval, e.g. edits of the same message, which telegram limits.
+// Jobs run on their own goroutines, one at a time per key. Jobs, requested while the key is throttled or its job is running,
+// are coalesced, only the latest one runs, once the interval passes
+type Throttle struct {
+	mu       sync.Mutex
+	interval time.Duration
+	lastRuns map[int64]time.Time
+	running  map[int64]bool
+	pending  map[int64]func()
+}
+
+func NewThrottle(interval time.Duration) *Throttle {
+	return &Throttle{interval: interval, lastRuns: map[int64]time.Time{}, running: map[int64]bool{}, pending: map[int64]func(){}}
+}
+
+// Do starts the job right away, if the key isn't throttled, otherwise it is delayed until the end of the interval
+func (throttle *Throttle) Do(key int64, job func()) {
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+	if _, ok := throttle.pending[key]; ok {
+		throttle.pending[key] = job
+		return
+	}
+	// The pending job is scheduled, once the running one finishes
+	if throttle.running[key] {
+		throttle.pending[key] = job
+		return
+	}
+	wait := throttle.interval - time.Since(throttle.lastRuns[key])
+	if wait > 0 {
+		throttle.pending[key] = job
+		time.AfterFunc(wait, func() { throttle.runPending(key) })
+		return
+	}
+	throttle.start(key, job)
+}
+
+func (throttle *Throttle) runPending(key int64) {
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+	job := throttle.pending[key]
+	delete(throttle.pending, key)
+	throttle.start(key, job)
+}
+
+// start must be called with the lock held
+func (throttle *Throttle) start(key int64, job func()) {
+	throttle.running[key] = true
+	throttle.lastRuns[key] = time.Now()
+	go func() {
+		defer throttle.finish(key)
+		job()
+	}()
+}
+
+func (throttle *Throttle) finish(key int64) {
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+	delete(throttle.running, key)
+	if _, ok := throttle.pending[key]; ok {
+		time.AfterFunc(throttle.interval-time.Since(throttle.lastRuns[key]), func() { throttle.runPending(key) })
+		return
+	}
+	time.AfterFunc(throttle.interval, func() { throttle.evict(key) })
+}
+
+// evict forgets the last run of the key, once it can't throttle anything, so that keys of past lessons don't pile up
+func (throttle *Throttle) evict(key int64) {
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+	_, pending := throttle.pending[key]
+	if !pending && !throttle.running[key] && time.Since(throttle.lastRuns[key]) >= throttle.interval {
+		delete(throttle.lastRuns, key)
+	}
+}
+
+// Len returns the number of keys, which the throttle remembers
+func (throttle *Throttle) Len() int {
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+	return len(throttle.lastRuns)
+}
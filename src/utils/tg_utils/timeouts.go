@@ -0,0 +1,151 @@
+package tgutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/i18n"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/repository/interfaces"
+	"github.com/aCrYoZPS/bsuir_queue_bot/src/telegram/update_handlers/constants"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// RegisterTimeout sets time to live for states, starting with given prefix. The most specific prefix is used,
+// so zero ttl can be registered to disable timeout for some of the states (e.g. waiting for approval of the admins)
+func (mux *Mux) RegisterTimeout(prefix constants.State, ttl time.Duration) {
+	mux.timeouts.Insert(string(prefix), ttl)
+	if ttl > 0 && (mux.minTimeout == 0 || ttl < mux.minTimeout) {
+		mux.minTimeout = ttl
+	}
+}
+
+func (mux *Mux) isExpired(info *interfaces.CachedInfo) bool {
+	ttl := mux.timeouts.Search(info.State())
+	return ttl > 0 && time.Since(info.SendTime()) > ttl
+}
+
+// ExpireStale aborts flows of all chats, which states have outlived their timeouts. Is meant to be run periodically,
+// as expiration is otherwise checked only on the next update of the chat
+func (mux *Mux) ExpireStale(ctx context.Context) error {
+	if mux.minTimeout == 0 {
+		return nil
+	}
+	infos, err := mux.cache.GetStaleStates(ctx, time.Now().Add(-mux.minTimeout))
+	if err != nil {
+		return fmt.Errorf("failed to get stale states: %w", err)
+	}
+	var errs []error
+	for _, info := range infos {
+		if !mux.isExpired(&info) {
+			continue
+		}
+		flowCtx := interfaces.WithChatUser(WithFlowChat(ctx, info.ChatId()), info.ChatId(), info.UserId())
+		flowCtx = mux.bot.Localize(flowCtx, info.UserId(), "")
+		errs = append(errs, mux.expireLocked(flowCtx, info.ChatId()))
+	}
+	return errors.Join(errs...)
+}
+
+// expireLocked expires the flow under the lock of the chat, which messages and callbacks are handled under
+func (mux *Mux) expireLocked(ctx context.Context, chatId int64) error {
+	mu := mux.cache.AcquireLock(ctx, chatId, "")
+	mu.Lock()
+	defer mu.Unlock()
+	defer mux.cache.ReleaseLock(ctx, chatId, "")
+
+	// State could've been changed by the update, handled before the lock was acquired
+	info, err := mux.cache.GetState(ctx, chatId)
+	if err != nil {
+		return fmt.Errorf("failed to get state of chat %d during expiration: %w", chatId, err)
+	}
+	if !mux.isExpired(info) {
+		return nil
+	}
+	return mux.expire(ctx, info)
+}
+
+// expire reverts expired state, aborts the flow and notifies user about it
+func (mux *Mux) expire(ctx context.Context, info *interfaces.CachedInfo) error {
+	userId := info.UserId()
+	if userId == 0 {
+		userId = interfaces.ChatUser(ctx, info.ChatId())
+	}
+	message := &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: info.ChatId()}, From: &tgbotapi.User{ID: userId}}
+	err := mux.wrap(info.State(), mux.dispatcher(info.State())).Revert(ctx, message)
+	if err != nil {
+		// Flow is reset anyway, so failed revert mustn't leave the chat stuck in expired state
+		slog.Error(fmt.Sprintf("failed to revert expired %s state of chat %d: %s", info.State(), info.ChatId(), err.Error()))
+	}
+	err = mux.ResetFlow(ctx, info.ChatId())
+	if err != nil {
+		return fmt.Errorf("failed to reset expired flow: %w", err)
+	}
+	_, err = mux.bot.SendCtx(ctx, tgbotapi.NewMessage(info.ChatId(), i18n.T(ctx, i18n.FLOW_EXPIRED_TEXT)))
+	if err != nil {
+		return fmt.Errorf("failed to send flow expiration message: %w", err)
+	}
+	return nil
+}
+
+// expireCallbackFlow expires flow of the chat, which callback was pressed in. Returns true, if the callback itself belonged to the expired flow
+func (mux *Mux) expireCallbackFlow(ctx context.Context, update *tgbotapi.Update) (bool, error) {
+	chatId := update.FromChat().ID
+	info, err := mux.cache.GetState(ctx, chatId)
+	if err != nil {
+		return false, fmt.Errorf("couldn't get state in state machine: %w", err)
+	}
+	if !mux.isExpired(info) {
+		return false, nil
+	}
+	isFlowCallback := false
+	if update.CallbackQuery.Message != nil {
+		isFlowCallback, err = mux.bot.IsFlowMarkup(ctx, chatId, update.CallbackQuery.Message.MessageID)
+		if err != nil {
+			return false, err
+		}
+	}
+	err = mux.expire(ctx, info)
+	if err != nil || !isFlowCallback {
+		return false, err
+	}
+	_, err = mux.bot.RequestCtx(ctx, tgbotapi.NewCallback(update.CallbackQuery.ID, i18n.T(ctx, i18n.BUTTONS_EXPIRED_TEXT)))
+	if err != nil {
+		return true, fmt.Errorf("failed to answer expired callback: %w", err)
+	}
+	return true, nil
+}
+
+// ResetFlow returns chat to idle state, removing info and inline keyboards of its current flow
+func (mux *Mux) ResetFlow(ctx context.Context, chatId int64) error {
+	err := mux.cache.RemoveInfo(ctx, chatId)
+	if err != nil {
+		return fmt.Errorf("failed to remove info of chat %d: %w", chatId, err)
+	}
+	err = mux.bot.StripMarkups(ctx, chatId)
+	if err != nil {
+		return err
+	}
+	err = mux.cache.SaveState(ctx, *interfaces.NewCachedInfo(chatId, constants.IDLE_STATE))
+	if err != nil {
+		return fmt.Errorf("failed to save idle state of chat %d: %w", chatId, err)
+	}
+	return nil
+}
+
+// forgetFinishedFlow stops tracking inline keyboards of the chat, once it has returned to idle state.
+// Saved state is remembered on the way, so that Validate checks it against declared flows
+func (mux *Mux) forgetFinishedFlow(ctx context.Context, chatId int64) error {
+	info, err := mux.cache.GetState(ctx, chatId)
+	if err != nil {
+		return fmt.Errorf("couldn't get state in state machine: %w", err)
+	}
+	mux.rememberSavedState(constants.State(info.State()))
+	if !slices.Contains([]string{"", string(constants.IDLE_STATE)}, info.State()) {
+		return nil
+	}
+	return mux.bot.ForgetMarkups(ctx, chatId)
+}
@@ -1,30 +1,35 @@
 package tgutils
 
 import (
-	"errors"
-	"fmt"
+	"context"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-var owners []string = nil
-
-func SendMessageToOwners(msg tgbotapi.MessageConfig, bot *tgbotapi.BotAPI) error {
-	if owners == nil {
-		owners = strings.Split(os.Getenv("OWNERS"), ",")
-	}
+func IsOwner(userId int64) bool {
+	return slices.Contains(GetOwners(), userId)
+}
 
-	for _, owner := range owners {
-		chatId, err := strconv.ParseInt(owner, 10, 64)
-		if err != nil {
-			return errors.Join(err, fmt.Errorf("invalid owner id value %s", owner))
+// GetOwners returns telegram ids of bot owners from comma separated OWNERS env, skipping malformed ones
+func GetOwners() []int64 {
+	ids := []int64{}
+	for _, owner := range strings.Split(os.Getenv("OWNERS"), ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(owner), 10, 64)
+		if err == nil {
+			ids = append(ids, id)
 		}
+	}
+	return ids
+}
 
-		msg.ChatID = chatId
-		_, err = bot.Send(msg)
+func SendMessageToOwners(ctx context.Context, msg tgbotapi.MessageConfig, bot *Bot) error {
+	for _, owner := range GetOwners() {
+		msg.ChatID = owner
+		_, err := bot.SendCtx(ctx, msg)
 		if err != nil {
 			return err
 		}